@@ -1,20 +1,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	"k8s.io/apimachinery/pkg/util/wait"
-
-	"github.com/olekukonko/tablewriter"
 
 	"github.com/integr8ly/cluster-service/pkg/clusterservice"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	awsclusterservice "github.com/integr8ly/cluster-service/pkg/aws"
 	"github.com/spf13/cobra"
@@ -28,10 +28,6 @@ var cleanupCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		//pre-req checks
 		clusterId := args[0]
-		region, err := cmd.Flags().GetString("region")
-		if err != nil {
-			exitError(fmt.Sprintf("failed to get regions list from flag: %+v", err), exitCodeErrUnknown)
-		}
 		outputFormat, err := cmd.Flags().GetString("output")
 		if err != nil {
 			exitError(fmt.Sprintf("failed to get output format from flag: %+v", err), exitCodeErrUnknown)
@@ -48,103 +44,199 @@ var cleanupCmd = &cobra.Command{
 		if err != nil {
 			exitError(fmt.Sprintf("failed to get timeout from flag: %+v", err), exitCodeErrUnknown)
 		}
-		types, err := cmd.Flags().GetStringSlice("types")
+		managers := managersFromFlags(cmd)
+		continueOnError, err := cmd.Flags().GetBool("continue-on-error")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get continue on error from flag: %+v", err), exitCodeErrUnknown)
+		}
+		phaseTimeout, err := cmd.Flags().GetDuration("phase-timeout")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get phase timeout from flag: %+v", err), exitCodeErrUnknown)
+		}
+		//a single cleanup returns at once unless asked to wait, the next run picks up the resources still being deleted
+		if !watch && !cmd.Flags().Changed("phase-timeout") {
+			phaseTimeout = 0
+		}
+		concurrency, err := cmd.Flags().GetInt("concurrency")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get concurrency from flag: %+v", err), exitCodeErrUnknown)
+		}
+		if concurrency < 1 {
+			exitError("concurrency must be at least 1", exitCodeErrKnown)
+		}
+		managerTimeout, err := cmd.Flags().GetDuration("manager-timeout")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get manager timeout from flag: %+v", err), exitCodeErrUnknown)
+		}
+		interval, err := cmd.Flags().GetDuration("interval")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get interval from flag: %+v", err), exitCodeErrUnknown)
+		}
+		if interval <= 0 {
+			exitError("interval must be greater than 0", exitCodeErrKnown)
+		}
+		maxInterval, err := cmd.Flags().GetDuration("max-interval")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get max interval from flag: %+v", err), exitCodeErrUnknown)
+		}
+		stateFile, err := cmd.Flags().GetString("state-file")
 		if err != nil {
-			exitError(fmt.Sprintf("failed to get types from flag: %+v", err), exitCodeErrUnknown)
+			exitError(fmt.Sprintf("failed to get state file from flag: %+v", err), exitCodeErrUnknown)
 		}
+		tags := tagsFromFlags(cmd)
 		//ensure the output format is supported
-		if outputFormat != "table" {
-			exitError(fmt.Sprintf("output format %s not supported, use table", outputFormat), exitCodeErrKnown)
-		}
-		//setup aws session
-		awsKeyID := os.Getenv("AWS_ACCESS_KEY_ID")
-		if awsKeyID == "" {
-			exitError("AWS_ACCESS_KEY_ID env var must be defined", exitCodeErrKnown)
-		}
-		awsSecretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
-		if awsSecretKey == "" {
-			exitError("AWS_SECRET_ACCESS_KEY env var must be defined", exitCodeErrKnown)
-		}
-		awsSession := session.Must(session.NewSession(&aws.Config{
-			Region:      aws.String(region),
-			Credentials: credentials.NewStaticCredentials(awsKeyID, awsSecretKey, ""),
-		}))
-		clusterService := buildAWSClientFromTypes(awsSession, types, logger)
+		renderer, err := clusterservice.NewRenderer(clusterservice.OutputFormat(outputFormat))
+		if err != nil {
+			exitError(err.Error(), exitCodeErrKnown)
+		}
+		//cancel in-flight requests on interrupt, the report of the work done so far is still printed
+		interruptCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		ctx, cancel := context.WithTimeout(interruptCtx, watchTimeout)
+		defer cancel()
+		//setup an aws client for each region
+		safetyPolicy := safetyPolicyFromFlags(cmd)
+		finalSnapshotPolicy := finalSnapshotPolicyFromFlags(cmd)
+		expectedAccount := expectedAccountFromFlags(cmd)
+		regions := regionsFromFlags(ctx, cmd)
+		regionalClients := buildRegionalClients(regions, func(region string) *awsclusterservice.Client {
+			regionalClient := buildAWSClient(newAWSSession(region), managers, logger)
+			regionalClient.ContinueOnError = continueOnError
+			regionalClient.PhaseTimeout = phaseTimeout
+			regionalClient.Concurrency = concurrency
+			regionalClient.ManagerTimeout = managerTimeout
+			regionalClient.SafetyPolicy = safetyPolicy
+			regionalClient.FinalSnapshot = finalSnapshotPolicy
+			regionalClient.ExpectedAccount = expectedAccount
+			if stateFile != "" {
+				regionalClient.StateStore = &clusterservice.FileStateStore{Path: regionalStateFile(stateFile, region, len(regions))}
+			}
+			return regionalClient
+		})
+		verifyIdentity(ctx, regionalClients[regions[0]], logger)
+		clusterService := combineRegionalClients(regionalClients, continueOnError)
 		if watch {
-			err := wait.PollImmediate(30*time.Second, watchTimeout, func() (bool, error) {
-				var currentReport *clusterservice.Report
-				newReport := runCleanupCommand(clusterService, clusterId, dryRun)
-				if currentReport == nil {
-					currentReport = newReport
+			reconciler := clusterservice.NewReconciler(clusterService, clusterId, dryRun)
+			reconciler.Tags = tags
+			reconciler.Interval = interval
+			reconciler.MaxInterval = maxInterval
+			reconciler.OnReport = func(report *clusterservice.Report) {
+				//only print intermediate reports for humans, machine readable formats get a single document
+				if outputFormat == string(clusterservice.OutputFormatTable) {
+					printReport(renderer, report)
 				}
-				currentReport.MergeForward(newReport)
-				printReportTable(currentReport)
-				logger.Info("watch is enabled, will attempt to delete resources every 30 seconds")
-				return currentReport.AllItemsComplete(), nil
-			})
+				logger.Info("watch is enabled, will attempt to delete resources until they are all deleted")
+			}
+			finalReport, err := reconciler.Reconcile(ctx)
 			if err != nil {
+				if finalReport == nil {
+					exitError(fmt.Sprintf("failed to cleanup resources for cluster, clusterId=%s: %+v", clusterId, err), exitCodeErrUnknown)
+				}
 				logger.Error(errors.Wrap(err, "failed to clean up all resources"))
 			}
 			logger.Info("finished cleaning up AWS resources")
+			if outputFormat != string(clusterservice.OutputFormatTable) {
+				printReport(renderer, finalReport)
+			}
+			exitOnInterrupt(interruptCtx)
+			exitOnFailedItems(finalReport)
 		} else {
-			report := runCleanupCommand(clusterService, clusterId, dryRun)
-			printReportTable(report)
+			report := runCleanupCommand(ctx, clusterService, clusterId, tags, dryRun)
+			printReport(renderer, report)
+			exitOnInterrupt(interruptCtx)
+			exitOnFailedItems(report)
 		}
 	},
 }
 
-func runCleanupCommand(clusterService *awsclusterservice.Client, clusterId string, dryRun bool) *clusterservice.Report {
-	report, err := clusterService.DeleteResourcesForCluster(clusterId, map[string]string{}, dryRun)
+func runCleanupCommand(ctx context.Context, clusterService clusterservice.Client, clusterId string, tags map[string]string, dryRun bool) *clusterservice.Report {
+	report, err := clusterService.DeleteResourcesForCluster(ctx, clusterId, tags, dryRun)
 	if err != nil {
-		exitError(fmt.Sprintf("failed to cleanup resources for cluster, clusterId=%s: %+v", clusterId, err), exitCodeErrUnknown)
+		//when continuing on error the failures are recorded in the report and summarised once the command completes
+		if report == nil {
+			exitError(fmt.Sprintf("failed to cleanup resources for cluster, clusterId=%s: %+v", clusterId, err), exitCodeErrUnknown)
+		}
+		logger.Debugf("failures occurred while cleaning up resources for cluster: %+v", err)
 	}
 	return report
 }
 
-func buildAWSClientFromTypes(awsSession *session.Session, types []string, logger *logrus.Entry) *awsclusterservice.Client {
-	if types == nil || len(types) == 0 {
-		return awsclusterservice.NewDefaultClient(awsSession, logger)
+//regionalStateFile State file of a region, each region keeps its own state when cleaning up several regions, e.g. state.json becomes state.eu-west-1.json
+func regionalStateFile(stateFile string, region string, regionCount int) string {
+	if regionCount == 1 {
+		return stateFile
 	}
-	client := &awsclusterservice.Client{
-		Logger:           logger,
-		ResourceManagers: make([]awsclusterservice.ClusterResourceManager, 0),
+	extension := filepath.Ext(stateFile)
+	return fmt.Sprintf("%s.%s%s", strings.TrimSuffix(stateFile, extension), region, extension)
+}
+
+//exitOnInterrupt Exit with an error once the report has been printed if the command was interrupted
+func exitOnInterrupt(interruptCtx context.Context) {
+	if interruptCtx.Err() != nil {
+		exitError("cleanup interrupted, the report only includes resources handled before the interruption", exitCodeErrKnown)
 	}
-	for _, t := range types {
-		switch t {
-		case "rds:instance":
-			client.ResourceManagers = append(client.ResourceManagers, awsclusterservice.NewDefaultRDSInstanceManager(awsSession, logger))
-		case "rds:snapshot":
-			client.ResourceManagers = append(client.ResourceManagers, awsclusterservice.NewDefaultRDSSnapshotManager(awsSession, logger))
-		case "s3":
-			client.ResourceManagers = append(client.ResourceManagers, awsclusterservice.NewDefaultS3Engine(awsSession, logger))
-		case "elasticache:replicationgroup":
-			client.ResourceManagers = append(client.ResourceManagers, awsclusterservice.NewDefaultElasticacheManager(awsSession, logger))
-		case "elasticache:snapshot":
-			client.ResourceManagers = append(client.ResourceManagers, awsclusterservice.NewDefaultElasticacheSnapshotManager(awsSession, logger))
-		case "ec2:subnet":
-			client.ResourceManagers = append(client.ResourceManagers, awsclusterservice.NewDefaultSubnetManager(awsSession, logger))
-		default:
-			logger.Debugf("could not find resource manager for specified type %s", t)
-		}
+}
+
+func exitOnFailedItems(report *clusterservice.Report) {
+	failedItems := report.FailedItems()
+	if len(failedItems) == 0 {
+		return
 	}
+	summary := fmt.Sprintf("%d resources failed to be cleaned up:\n", len(failedItems))
+	for _, item := range failedItems {
+		summary += fmt.Sprintf("  %s (%s): %s\n", item.ID, item.ResourceType, item.StatusReason)
+	}
+	exitError(summary, exitCodeErrKnown)
+}
+
+//buildAWSClient Build a client running the selected resource managers, every registered manager when none are selected
+func buildAWSClient(awsSession *session.Session, managers []*awsclusterservice.RegisteredManager, logger *logrus.Entry) *awsclusterservice.Client {
+	if len(managers) == 0 {
+		managers = awsclusterservice.RegisteredManagers()
+	}
+	client := awsclusterservice.NewClientForManagers(awsSession, logger, managers)
+	client.ManagerSettings = managerSettings
 	return client
 }
 
-func printReportTable(report *clusterservice.Report) {
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"ID", "Name", "Action", "Status"})
-	for _, reportItem := range report.Items {
-		table.Append([]string{reportItem.ID, reportItem.Name, string(reportItem.Action), string(reportItem.ActionStatus)})
+//addTagsFlag Add the flag filtering resources by additional tags
+func addTagsFlag(cmd *cobra.Command) {
+	cmd.Flags().StringToString("tags", map[string]string{}, "additional tags resources must have besides the cluster id tag, e.g. owner=team")
+}
+
+//tagsFromFlags Get the additional tags from the flag added by addTagsFlag
+func tagsFromFlags(cmd *cobra.Command) map[string]string {
+	tags, err := cmd.Flags().GetStringToString("tags")
+	if err != nil {
+		exitError(fmt.Sprintf("failed to get tags from flag: %+v", err), exitCodeErrUnknown)
+	}
+	return tags
+}
+
+func printReport(renderer clusterservice.Renderer, report *clusterservice.Report) {
+	if err := renderer.Render(os.Stdout, clusterservice.NewReportDocument(report)); err != nil {
+		exitError(fmt.Sprintf("failed to render report: %+v", err), exitCodeErrUnknown)
 	}
-	table.Render()
 }
 
 func init() {
 	rootCmd.AddCommand(cleanupCmd)
-	cleanupCmd.Flags().StringP("output", "o", "table", "set output format")
+	cleanupCmd.Flags().StringP("output", "o", string(clusterservice.OutputFormatTable), fmt.Sprintf("set output format, one of %v", clusterservice.SupportedOutputFormats()))
 	cleanupCmd.Flags().StringP("region", "r", "eu-west-1", "region to delete resources in")
+	addRegionsFlags(cleanupCmd)
 	cleanupCmd.Flags().BoolP("dry-run", "d", true, "skip performing actions")
 	cleanupCmd.Flags().BoolP("watch", "w", false, "poll actions being performed indefinitely")
-	cleanupCmd.Flags().Duration("timeout", 30*time.Minute, "duration before timing out in watch mode")
-	cleanupCmd.Flags().StringSliceP("types", "t", []string{}, "resource types to cleanup")
+	cleanupCmd.Flags().Duration("interval", clusterservice.DefaultReconcileInterval, "duration to wait between attempts when watching, and after an attempt which deleted resources")
+	cleanupCmd.Flags().Duration("max-interval", clusterservice.DefaultReconcileMaxInterval, "longest duration to wait between attempts when watching, the wait backs off up to it while no resources are deleted")
+	cleanupCmd.Flags().Duration("timeout", 30*time.Minute, "duration before timing out, in-flight requests are cancelled once it elapses")
+	addTypesFlags(cleanupCmd, "cleanup")
+	addTagsFlag(cleanupCmd)
+	cleanupCmd.Flags().Duration("phase-timeout", awsclusterservice.DefaultPhaseTimeout, "duration to wait for resources of a phase to be deleted before moving on to resources that depend on them, 0 to not wait, only applies to --watch unless set")
+	cleanupCmd.Flags().Int("concurrency", awsclusterservice.DefaultConcurrency, "maximum number of resource managers, and resources within each manager, handled at the same time")
+	cleanupCmd.Flags().Duration("manager-timeout", 0, "duration before a single run of a resource manager times out, 0 for no limit")
+	cleanupCmd.Flags().String("state-file", "", "json file to save cleanup progress to, a later cleanup of the same cluster with the same file resumes where it stopped, each region gets its own file named after the region when cleaning up several regions")
+	cleanupCmd.Flags().Bool("continue-on-error", false, "record failures in the report and continue cleaning up other resources, exits non-zero if any failed")
+	addSafetyPolicyFlags(cleanupCmd)
+	addFinalSnapshotFlags(cleanupCmd)
+	addExpectAccountFlag(cleanupCmd)
 }
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+	"github.com/spf13/cobra"
+
+	awsclusterservice "github.com/integr8ly/cluster-service/pkg/aws"
+)
+
+// clustersCmd represents the clusters command
+var clustersCmd = &cobra.Command{
+	Use:   "clusters [flags]",
+	Short: "list every rhmi cluster with aws resources in the account",
+	Long: `List every cluster id found in the integreatly.org/clusterID tag of aws resources in the account.
+
+Each cluster is shown with the number of resources of each type, the creation time of its oldest resource and the total size of its resources where available.
+Only read permissions are required, no resources are modified.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		outputFormat, err := cmd.Flags().GetString("output")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get output format from flag: %+v", err), exitCodeErrUnknown)
+		}
+		timeout, err := cmd.Flags().GetDuration("timeout")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get timeout from flag: %+v", err), exitCodeErrUnknown)
+		}
+		continueOnError, err := cmd.Flags().GetBool("continue-on-error")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get continue on error from flag: %+v", err), exitCodeErrUnknown)
+		}
+		renderer, err := clusterservice.NewRenderer(clusterservice.OutputFormat(outputFormat))
+		if err != nil {
+			exitError(err.Error(), exitCodeErrKnown)
+		}
+		interruptCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		ctx, cancel := context.WithTimeout(interruptCtx, timeout)
+		defer cancel()
+		regionalClients := buildRegionalClients(regionsFromFlags(ctx, cmd), func(region string) *awsclusterservice.Client {
+			regionalClient := buildAWSClient(newAWSSession(region), nil, logger)
+			regionalClient.ContinueOnError = continueOnError
+			return regionalClient
+		})
+		clusterService := combineRegionalClients(regionalClients, continueOnError)
+		clusterList, err := clusterService.ListClusters(ctx)
+		if err != nil {
+			if clusterList == nil {
+				exitError(fmt.Sprintf("failed to list clusters: %+v", err), exitCodeErrUnknown)
+			}
+			logger.Warnf("failures occurred while listing clusters, summaries may be incomplete: %+v", err)
+		}
+		if err := renderer.Render(os.Stdout, clusterservice.NewClusterListDocument(clusterList)); err != nil {
+			exitError(fmt.Sprintf("failed to render clusters: %+v", err), exitCodeErrUnknown)
+		}
+		if err != nil {
+			exitError("some clusters could not be fully listed", exitCodeErrKnown)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(clustersCmd)
+	clustersCmd.Flags().StringP("output", "o", string(clusterservice.OutputFormatTable), fmt.Sprintf("set output format, one of %v", clusterservice.SupportedOutputFormats()))
+	clustersCmd.Flags().StringP("region", "r", "eu-west-1", "region to find clusters in")
+	addRegionsFlags(clustersCmd)
+	clustersCmd.Flags().Duration("timeout", 30*time.Minute, "duration before timing out, in-flight requests are cancelled once it elapses")
+	clustersCmd.Flags().Bool("continue-on-error", false, "summarise clusters with the resources that could be listed, exits non-zero if any failed")
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	awsclusterservice "github.com/integr8ly/cluster-service/pkg/aws"
+	"github.com/integr8ly/cluster-service/pkg/config"
+)
+
+//managerSettings Settings of single resource managers read from the config file
+var managerSettings map[awsclusterservice.ResourceManagerType]awsclusterservice.ManagerSettings
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "manage the config file",
+	Long: fmt.Sprintf(`The config file sets the flags of every command, keyed by flag name, along with the settings of single resource managers
+keyed by the type names listed by the types command.
+Flags set on the command line take precedence over env vars named after the flag, e.g. %s, which take precedence over the config file.
+
+  region: eu-west-1
+  dry-run: false
+  output: json
+  concurrency: 10
+  tags:
+    owner: team
+  deny: ["*-final-snapshot"]
+  expect-account: "123456789012"
+  managers:
+    rds:instance:
+      concurrency: 2
+      timeout: 10m`, config.EnvVar("dry-run")),
+}
+
+// configValidateCmd represents the config validate command
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "check the config file only sets known flags and managers",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := configFilePath()
+		if err != nil {
+			exitError(err.Error(), exitCodeErrKnown)
+		}
+		if path == "" {
+			exitError("no config file found, set one with --config", exitCodeErrKnown)
+		}
+		//values were already checked when the config was applied on start up
+		cliConfig, err := config.Load(path)
+		if err != nil {
+			exitError(fmt.Sprintf("failed to load config: %+v", err), exitCodeErrKnown)
+		}
+		var problems []string
+		for _, key := range cliConfig.UnknownKeys(commandFlagSets(rootCmd)...) {
+			problems = append(problems, fmt.Sprintf("unknown key %s", key))
+		}
+		_, unknownManagers := managerSettingsFromConfig(cliConfig)
+		for _, manager := range unknownManagers {
+			problems = append(problems, fmt.Sprintf("unknown manager %s", manager))
+		}
+		if len(problems) > 0 {
+			exitError(fmt.Sprintf("config file %s is invalid:\n  %s\n", path, strings.Join(problems, "\n  ")), exitCodeErrKnown)
+		}
+		exitSuccess(fmt.Sprintf("config file %s is valid\n", path))
+	},
+}
+
+//initConfig Set the flags of every command which weren't set on the command line from env vars and the config file
+func initConfig() {
+	path, err := configFilePath()
+	if err != nil {
+		exitError(err.Error(), exitCodeErrKnown)
+	}
+	var cliConfig *config.Config
+	if path != "" {
+		cliConfig, err = config.Load(path)
+		if err != nil {
+			exitError(fmt.Sprintf("failed to load config: %+v", err), exitCodeErrKnown)
+		}
+	}
+	if err := cliConfig.Apply(os.LookupEnv, commandFlagSets(rootCmd)...); err != nil {
+		exitError(fmt.Sprintf("failed to apply config: %+v", err), exitCodeErrKnown)
+	}
+	if verbose {
+		logrus.SetLevel(logrus.DebugLevel)
+	}
+	if cliConfig == nil {
+		return
+	}
+	for _, key := range cliConfig.UnknownKeys(commandFlagSets(rootCmd)...) {
+		logger.Warnf("ignoring unknown key %s in config file %s", key, path)
+	}
+	var unknownManagers []string
+	managerSettings, unknownManagers = managerSettingsFromConfig(cliConfig)
+	for _, manager := range unknownManagers {
+		logger.Warnf("ignoring unknown manager %s in config file %s", manager, path)
+	}
+}
+
+//configFilePath Path of the config file to read, the --config flag, its env var or the default file when it exists, empty when there is none
+func configFilePath() (string, error) {
+	if cfgFile != "" {
+		return cfgFile, nil
+	}
+	if envConfigFile, ok := os.LookupEnv(config.EnvVar("config")); ok {
+		return envConfigFile, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", nil
+	}
+	defaultConfigFile := filepath.Join(home, ".cli.yaml")
+	if _, err := os.Stat(defaultConfigFile); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read config file %s: %w", defaultConfigFile, err)
+	}
+	return defaultConfigFile, nil
+}
+
+//commandFlagSets Flag sets of a command and every command below it
+func commandFlagSets(cmd *cobra.Command) []*pflag.FlagSet {
+	flagSets := []*pflag.FlagSet{cmd.PersistentFlags(), cmd.Flags()}
+	for _, subCmd := range cmd.Commands() {
+		flagSets = append(flagSets, commandFlagSets(subCmd)...)
+	}
+	return flagSets
+}
+
+//managerSettingsFromConfig Settings of the managers in the config, along with the sorted names of unknown managers
+func managerSettingsFromConfig(cliConfig *config.Config) (map[awsclusterservice.ResourceManagerType]awsclusterservice.ManagerSettings, []string) {
+	settings := map[awsclusterservice.ResourceManagerType]awsclusterservice.ManagerSettings{}
+	var unknownManagers []string
+	for name, managerConfig := range cliConfig.Managers {
+		manager, ok := awsclusterservice.LookupManager(name)
+		if !ok {
+			unknownManagers = append(unknownManagers, name)
+			continue
+		}
+		settings[manager.Type] = awsclusterservice.ManagerSettings{
+			Concurrency: managerConfig.Concurrency,
+			Timeout:     managerConfig.Timeout.Duration,
+		}
+	}
+	sort.Strings(unknownManagers)
+	return settings, unknownManagers
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd)
+}
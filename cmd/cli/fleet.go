@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+	clusterserviceerrors "github.com/integr8ly/cluster-service/pkg/errors"
+	"github.com/spf13/cobra"
+
+	awsclusterservice "github.com/integr8ly/cluster-service/pkg/aws"
+)
+
+// fleetCmd represents the fleet command
+var fleetCmd = &cobra.Command{
+	Use:   "fleet",
+	Short: "manage rhmi clusters across many aws accounts",
+}
+
+// fleetCleanupCmd represents the fleet cleanup command
+var fleetCleanupCmd = &cobra.Command{
+	Use:   "cleanup -f [fleet file] [flags]",
+	Short: "delete aws resources of rhmi clusters across the accounts in a fleet file",
+	Long: `Delete the aws resources of rhmi clusters across the accounts listed in a yaml or json fleet file.
+
+Each account lists the role to assume, the regions to clean up and either the cluster ids to clean up or discoverOrphans,
+which cleans up every cluster in the account that is not in the live clusters of the fleet. For example:
+
+  concurrency: 4
+  liveClusters: https://example.com/clusters.json
+  accounts:
+  - name: customer-a
+    accountId: "123456789012"
+    roleArn: arn:aws:iam::123456789012:role/cluster-cleanup
+    externalId: customer-a
+    regions: [eu-west-1, us-east-1]
+    clusterIds: [cluster-a]
+  - name: customer-b
+    roleArn: arn:aws:iam::210987654321:role/cluster-cleanup
+    regions: [eu-west-1]
+    discoverOrphans: true
+
+Each cluster is cleaned up once unless --watch is set, which cleans it up again until all of its resources are deleted.
+A failing account never stops the other accounts, the command exits non-zero once every account is done if any failed.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fleetFile, err := cmd.Flags().GetString("file")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get fleet file from flag: %+v", err), exitCodeErrUnknown)
+		}
+		outputFormat, err := cmd.Flags().GetString("output")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get output format from flag: %+v", err), exitCodeErrUnknown)
+		}
+		timeout, err := cmd.Flags().GetDuration("timeout")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get timeout from flag: %+v", err), exitCodeErrUnknown)
+		}
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get dry run from flag: %+v", err), exitCodeErrUnknown)
+		}
+		continueOnError, err := cmd.Flags().GetBool("continue-on-error")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get continue on error from flag: %+v", err), exitCodeErrUnknown)
+		}
+		watch, err := cmd.Flags().GetBool("watch")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get watch from flag: %+v", err), exitCodeErrUnknown)
+		}
+		interval, err := cmd.Flags().GetDuration("interval")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get interval from flag: %+v", err), exitCodeErrUnknown)
+		}
+		maxInterval, err := cmd.Flags().GetDuration("max-interval")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get max interval from flag: %+v", err), exitCodeErrUnknown)
+		}
+		concurrency, err := cmd.Flags().GetInt("concurrency")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get concurrency from flag: %+v", err), exitCodeErrUnknown)
+		}
+		reportDir, err := cmd.Flags().GetString("report-dir")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get report dir from flag: %+v", err), exitCodeErrUnknown)
+		}
+		renderer, err := clusterservice.NewRenderer(clusterservice.OutputFormat(outputFormat))
+		if err != nil {
+			exitError(err.Error(), exitCodeErrKnown)
+		}
+		fleet, err := clusterservice.LoadFleet(fleetFile)
+		if err != nil {
+			exitError(fmt.Sprintf("failed to load fleet: %+v", err), exitCodeErrKnown)
+		}
+		if concurrency < 0 {
+			exitError("concurrency must not be negative", exitCodeErrKnown)
+		}
+		//the flag overrides the concurrency of the fleet file
+		if concurrency > 0 {
+			fleet.Concurrency = concurrency
+		}
+		safetyPolicy := safetyPolicyFromFlags(cmd)
+		finalSnapshotPolicy := finalSnapshotPolicyFromFlags(cmd)
+		interruptCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		ctx, cancel := context.WithTimeout(interruptCtx, timeout)
+		defer cancel()
+		var liveClusterIds []string
+		if fleet.LiveClusters != "" {
+			liveClusterIds, err = clusterservice.NewLiveClusterSource(fleet.LiveClusters, os.Getenv(envLiveClustersToken)).GetLiveClusterIds(ctx)
+			if err != nil {
+				exitError(fmt.Sprintf("failed to get live clusters: %+v", err), exitCodeErrKnown)
+			}
+		}
+		clientFn := func(ctx context.Context, account *clusterservice.FleetAccount) (clusterservice.Client, error) {
+			regionalClients := map[string]*awsclusterservice.Client{}
+			for _, region := range account.Regions {
+				awsSession, err := newAccountAWSSession(region, account.RoleARN, account.ExternalID)
+				if err != nil {
+					return nil, err
+				}
+				regionalClient := buildAWSClient(awsSession, nil, logger.WithField("account", account.Name))
+				regionalClient.ContinueOnError = continueOnError
+				regionalClient.SafetyPolicy = safetyPolicy
+				regionalClient.FinalSnapshot = finalSnapshotPolicy
+				regionalClient.ExpectedAccount = account.AccountID
+				regionalClients[region] = regionalClient
+			}
+			return combineRegionalClients(regionalClients, continueOnError), nil
+		}
+		//without watch each cluster is cleaned up once
+		var reconcilerFn clusterservice.FleetReconcilerFunc
+		if watch {
+			reconcilerFn = func(client clusterservice.Client, clusterId string, dryRun bool) *clusterservice.Reconciler {
+				reconciler := clusterservice.NewReconciler(client, clusterId, dryRun)
+				reconciler.Interval = interval
+				reconciler.MaxInterval = maxInterval
+				return reconciler
+			}
+		}
+		logger.Infof("cleaning up %d accounts", len(fleet.Accounts))
+		fleetReport := clusterservice.CleanupFleet(ctx, fleet, clientFn, reconcilerFn, liveClusterIds, dryRun, continueOnError)
+		var writeErr error
+		if reportDir != "" {
+			writeErr = writeFleetAccountReports(reportDir, fleetReport)
+		}
+		combinedReport := fleetReport.Combined()
+		printReport(renderer, combinedReport)
+		exitOnInterrupt(interruptCtx)
+		if failedAccounts := fleetReport.FailedAccounts(); len(failedAccounts) > 0 {
+			summary := fmt.Sprintf("%d accounts failed to be cleaned up:\n", len(failedAccounts))
+			for _, accountReport := range failedAccounts {
+				summary += fmt.Sprintf("  %s: %s\n", accountReport.Account, accountReport.Error)
+			}
+			exitError(summary, exitCodeErrKnown)
+		}
+		if writeErr != nil {
+			exitError(fmt.Sprintf("failed to write account reports: %+v", writeErr), exitCodeErrKnown)
+		}
+		exitOnFailedItems(combinedReport)
+	},
+}
+
+//writeFleetAccountReports Write the report of each account to a json file named after the account
+//A report which can't be written is logged and the other reports are still written, the failures are returned together
+func writeFleetAccountReports(reportDir string, fleetReport *clusterservice.FleetReport) error {
+	if err := os.MkdirAll(reportDir, 0700); err != nil {
+		return fmt.Errorf("failed to create report dir %s: %w", reportDir, err)
+	}
+	var writeErrors []error
+	for _, accountReport := range fleetReport.Accounts {
+		data, err := json.MarshalIndent(clusterservice.NewFleetAccountReportDocument(accountReport), "", "  ")
+		if err != nil {
+			err = fmt.Errorf("failed to encode report of account %s: %w", accountReport.Account, err)
+			logger.Error(err)
+			writeErrors = append(writeErrors, err)
+			continue
+		}
+		reportFile := filepath.Join(reportDir, accountReport.Account+".json")
+		if err := os.WriteFile(reportFile, append(data, '\n'), 0600); err != nil {
+			err = fmt.Errorf("failed to write report file %s: %w", reportFile, err)
+			logger.Error(err)
+			writeErrors = append(writeErrors, err)
+		}
+	}
+	return clusterserviceerrors.Aggregate(writeErrors)
+}
+
+func init() {
+	rootCmd.AddCommand(fleetCmd)
+	fleetCmd.AddCommand(fleetCleanupCmd)
+	fleetCleanupCmd.Flags().StringP("file", "f", "", "yaml or json file listing the accounts to clean up")
+	fleetCleanupCmd.Flags().StringP("output", "o", string(clusterservice.OutputFormatTable), fmt.Sprintf("set output format of the combined report, one of %v", clusterservice.SupportedOutputFormats()))
+	fleetCleanupCmd.Flags().String("report-dir", "", "directory to write the json report of each account to, named after the account")
+	fleetCleanupCmd.Flags().Duration("timeout", 2*time.Hour, "duration before timing out, in-flight requests are cancelled once it elapses")
+	fleetCleanupCmd.Flags().BoolP("dry-run", "d", true, "skip performing actions")
+	fleetCleanupCmd.Flags().BoolP("watch", "w", false, "clean up each cluster again until all of its resources are deleted, instead of once")
+	fleetCleanupCmd.Flags().Duration("interval", clusterservice.DefaultReconcileInterval, "duration to wait between attempts on a cluster when watching, and after an attempt which deleted resources")
+	fleetCleanupCmd.Flags().Duration("max-interval", clusterservice.DefaultReconcileMaxInterval, "longest duration to wait between attempts on a cluster when watching, the wait backs off up to it while no resources are deleted")
+	fleetCleanupCmd.Flags().Int("concurrency", 0, fmt.Sprintf("number of accounts cleaned up at the same time, overrides the fleet file which defaults to %d", clusterservice.DefaultFleetConcurrency))
+	fleetCleanupCmd.Flags().Bool("continue-on-error", false, "record failures and continue with other resources and clusters of an account, failed accounts never stop other accounts")
+	addSafetyPolicyFlags(fleetCleanupCmd)
+	addFinalSnapshotFlags(fleetCleanupCmd)
+	_ = fleetCleanupCmd.MarkFlagRequired("file")
+}
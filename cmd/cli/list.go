@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+	"github.com/spf13/cobra"
+
+	awsclusterservice "github.com/integr8ly/cluster-service/pkg/aws"
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list [cluster id] [flags]",
+	Short: "list aws resources for an rhmi cluster without modifying them",
+	Long: `List the aws resources belonging to an rhmi cluster along with their state, creation time and size where available.
+
+Only read permissions are required, no resources are modified.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		clusterId := args[0]
+		outputFormat, err := cmd.Flags().GetString("output")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get output format from flag: %+v", err), exitCodeErrUnknown)
+		}
+		timeout, err := cmd.Flags().GetDuration("timeout")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get timeout from flag: %+v", err), exitCodeErrUnknown)
+		}
+		managers := managersFromFlags(cmd)
+		renderer, err := clusterservice.NewRenderer(clusterservice.OutputFormat(outputFormat))
+		if err != nil {
+			exitError(err.Error(), exitCodeErrKnown)
+		}
+		interruptCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		ctx, cancel := context.WithTimeout(interruptCtx, timeout)
+		defer cancel()
+		regionalClients := buildRegionalClients(regionsFromFlags(ctx, cmd), func(region string) *awsclusterservice.Client {
+			return buildAWSClient(newAWSSession(region), managers, logger)
+		})
+		clusterService := combineRegionalClients(regionalClients, false)
+		inventory, err := clusterService.ListResourcesForCluster(ctx, clusterId, tagsFromFlags(cmd))
+		if err != nil {
+			exitError(fmt.Sprintf("failed to list resources for cluster, clusterId=%s: %+v", clusterId, err), exitCodeErrUnknown)
+		}
+		if err := renderer.Render(os.Stdout, clusterservice.NewInventoryDocument(inventory)); err != nil {
+			exitError(fmt.Sprintf("failed to render inventory: %+v", err), exitCodeErrUnknown)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+	listCmd.Flags().StringP("output", "o", string(clusterservice.OutputFormatTable), fmt.Sprintf("set output format, one of %v", clusterservice.SupportedOutputFormats()))
+	listCmd.Flags().StringP("region", "r", "eu-west-1", "region to list resources in")
+	addRegionsFlags(listCmd)
+	listCmd.Flags().Duration("timeout", 5*time.Minute, "duration before timing out, in-flight requests are cancelled once it elapses")
+	addTypesFlags(listCmd, "list")
+	addTagsFlag(listCmd)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+	"github.com/spf13/cobra"
+
+	awsclusterservice "github.com/integr8ly/cluster-service/pkg/aws"
+)
+
+const (
+	//envLiveClustersToken Bearer token sent when requesting live clusters over http, e.g. an ocm access token
+	envLiveClustersToken = "LIVE_CLUSTERS_TOKEN"
+)
+
+// orphansCmd represents the orphans command
+var orphansCmd = &cobra.Command{
+	Use:   "orphans [flags]",
+	Short: "find, and optionally delete, aws resources of rhmi clusters that no longer exist",
+	Long: `Find every cluster id in the integreatly.org/clusterID tag of aws resources in the account that is not in a list of live clusters.
+
+The live clusters are read from a file or an http endpoint returning json. Files and responses can contain a json list of ids,
+an ocm style clusters list, e.g. from /api/clusters_mgmt/v1/clusters, or one id per line. The ` + envLiveClustersToken + ` env var is sent as a bearer token to http endpoints.
+
+Orphaned clusters are only listed unless --cleanup is set, cleanup is a dry run unless --dry-run=false is also set.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		liveClustersLocation, err := cmd.Flags().GetString("live-clusters")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get live clusters from flag: %+v", err), exitCodeErrUnknown)
+		}
+		outputFormat, err := cmd.Flags().GetString("output")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get output format from flag: %+v", err), exitCodeErrUnknown)
+		}
+		timeout, err := cmd.Flags().GetDuration("timeout")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get timeout from flag: %+v", err), exitCodeErrUnknown)
+		}
+		cleanup, err := cmd.Flags().GetBool("cleanup")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get cleanup from flag: %+v", err), exitCodeErrUnknown)
+		}
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get dry run from flag: %+v", err), exitCodeErrUnknown)
+		}
+		continueOnError, err := cmd.Flags().GetBool("continue-on-error")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get continue on error from flag: %+v", err), exitCodeErrUnknown)
+		}
+		renderer, err := clusterservice.NewRenderer(clusterservice.OutputFormat(outputFormat))
+		if err != nil {
+			exitError(err.Error(), exitCodeErrKnown)
+		}
+		interruptCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		ctx, cancel := context.WithTimeout(interruptCtx, timeout)
+		defer cancel()
+		safetyPolicy := safetyPolicyFromFlags(cmd)
+		expectedAccount := expectedAccountFromFlags(cmd)
+		regions := regionsFromFlags(ctx, cmd)
+		regionalClients := buildRegionalClients(regions, func(region string) *awsclusterservice.Client {
+			regionalClient := buildAWSClient(newAWSSession(region), nil, logger)
+			regionalClient.ContinueOnError = continueOnError
+			regionalClient.SafetyPolicy = safetyPolicy
+			regionalClient.ExpectedAccount = expectedAccount
+			return regionalClient
+		})
+		clusterService := combineRegionalClients(regionalClients, continueOnError)
+		//read the live clusters first, there is no point listing the account if they can't be found
+		liveClusterIds, err := clusterservice.NewLiveClusterSource(liveClustersLocation, os.Getenv(envLiveClustersToken)).GetLiveClusterIds(ctx)
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get live clusters: %+v", err), exitCodeErrKnown)
+		}
+		clusterList, err := clusterService.ListClusters(ctx)
+		if err != nil {
+			//a cluster missing from the list could hide an orphan, but never causes a live cluster to be deleted
+			if clusterList == nil {
+				exitError(fmt.Sprintf("failed to list clusters: %+v", err), exitCodeErrUnknown)
+			}
+			logger.Warnf("failures occurred while listing clusters, orphans may be incomplete: %+v", err)
+		}
+		orphans, err := clusterservice.FindOrphanedClusters(clusterList, liveClusterIds)
+		if err != nil {
+			exitError(err.Error(), exitCodeErrKnown)
+		}
+		if !cleanup {
+			if err := renderer.Render(os.Stdout, clusterservice.NewClusterListDocument(orphans)); err != nil {
+				exitError(fmt.Sprintf("failed to render orphaned clusters: %+v", err), exitCodeErrUnknown)
+			}
+			return
+		}
+		verifyIdentity(ctx, regionalClients[regions[0]], logger)
+		logger.Infof("cleaning up resources of %d orphaned clusters", len(orphans.Clusters))
+		report, err := clusterservice.DeleteOrphanedClusters(ctx, clusterService, orphans, dryRun, continueOnError)
+		if err != nil {
+			logger.Debugf("failures occurred while cleaning up orphaned clusters: %+v", err)
+		}
+		printReport(renderer, report)
+		exitOnInterrupt(interruptCtx)
+		if err != nil && !continueOnError {
+			exitError(fmt.Sprintf("failed to cleanup orphaned clusters: %+v", err), exitCodeErrUnknown)
+		}
+		exitOnFailedItems(report)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(orphansCmd)
+	orphansCmd.Flags().String("live-clusters", "", "file or http(s) url listing the clusters that still exist")
+	orphansCmd.Flags().StringP("output", "o", string(clusterservice.OutputFormatTable), fmt.Sprintf("set output format, one of %v", clusterservice.SupportedOutputFormats()))
+	orphansCmd.Flags().StringP("region", "r", "eu-west-1", "region to find orphaned clusters in")
+	addRegionsFlags(orphansCmd)
+	orphansCmd.Flags().Duration("timeout", 30*time.Minute, "duration before timing out, in-flight requests are cancelled once it elapses")
+	orphansCmd.Flags().Bool("cleanup", false, "delete the resources of orphaned clusters instead of listing them")
+	orphansCmd.Flags().BoolP("dry-run", "d", true, "skip performing actions when cleaning up")
+	orphansCmd.Flags().Bool("continue-on-error", false, "record failures and continue with other resources and clusters, exits non-zero if any failed")
+	addSafetyPolicyFlags(orphansCmd)
+	addExpectAccountFlag(orphansCmd)
+	_ = orphansCmd.MarkFlagRequired("live-clusters")
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+	"github.com/spf13/cobra"
+
+	awsclusterservice "github.com/integr8ly/cluster-service/pkg/aws"
+)
+
+// cleanupPlanCmd represents the cleanup plan command
+var cleanupPlanCmd = &cobra.Command{
+	Use:   "plan [cluster id] [flags]",
+	Short: "save the aws resources a cleanup of an rhmi cluster would delete to a plan file",
+	Long: `Save the aws resources belonging to an rhmi cluster to a plan file, along with their state and a fingerprint of their id, type, creation time and tags.
+
+The plan can be reviewed and later applied with cleanup apply, which deletes only the resources in the plan.
+Only read permissions are required, no resources are modified.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		clusterId := args[0]
+		region, err := cmd.Flags().GetString("region")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get regions list from flag: %+v", err), exitCodeErrUnknown)
+		}
+		outputFormat, err := cmd.Flags().GetString("output")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get output format from flag: %+v", err), exitCodeErrUnknown)
+		}
+		timeout, err := cmd.Flags().GetDuration("timeout")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get timeout from flag: %+v", err), exitCodeErrUnknown)
+		}
+		managers := managersFromFlags(cmd)
+		planFile, err := cmd.Flags().GetString("out")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get plan file from flag: %+v", err), exitCodeErrUnknown)
+		}
+		renderer, err := clusterservice.NewRenderer(clusterservice.OutputFormat(outputFormat))
+		if err != nil {
+			exitError(err.Error(), exitCodeErrKnown)
+		}
+		tags := tagsFromFlags(cmd)
+		clusterService := buildAWSClient(newAWSSession(region), managers, logger)
+		interruptCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		ctx, cancel := context.WithTimeout(interruptCtx, timeout)
+		defer cancel()
+		inventory, err := clusterService.ListResourcesForCluster(ctx, clusterId, tags)
+		if err != nil {
+			exitError(fmt.Sprintf("failed to list resources for cluster, clusterId=%s: %+v", clusterId, err), exitCodeErrUnknown)
+		}
+		plan := clusterservice.NewPlan(clusterId, region, tags, inventory, time.Now())
+		if err := clusterservice.SavePlan(planFile, plan); err != nil {
+			exitError(fmt.Sprintf("failed to save plan: %+v", err), exitCodeErrUnknown)
+		}
+		if err := renderer.Render(os.Stdout, clusterservice.NewInventoryDocument(plan.Inventory())); err != nil {
+			exitError(fmt.Sprintf("failed to render plan: %+v", err), exitCodeErrUnknown)
+		}
+		logger.Infof("saved plan to delete %d resources to %s, run cleanup apply %s to delete them", len(plan.Resources), planFile, planFile)
+	},
+}
+
+// cleanupApplyCmd represents the cleanup apply command
+var cleanupApplyCmd = &cobra.Command{
+	Use:   "apply [plan file] [flags]",
+	Short: "delete the aws resources saved in a plan file",
+	Long: `Delete only the aws resources saved in a plan file by cleanup plan.
+
+Resources which changed since the plan was made, e.g. their tags or state changed or they were recreated under the same id, are skipped and resources which no longer exist are reported complete.
+Resources of the cluster which are not in the plan are never deleted.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		plan, err := clusterservice.LoadPlan(args[0])
+		if err != nil {
+			exitError(err.Error(), exitCodeErrKnown)
+		}
+		outputFormat, err := cmd.Flags().GetString("output")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get output format from flag: %+v", err), exitCodeErrUnknown)
+		}
+		timeout, err := cmd.Flags().GetDuration("timeout")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get timeout from flag: %+v", err), exitCodeErrUnknown)
+		}
+		continueOnError, err := cmd.Flags().GetBool("continue-on-error")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get continue on error from flag: %+v", err), exitCodeErrUnknown)
+		}
+		phaseTimeout, err := cmd.Flags().GetDuration("phase-timeout")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get phase timeout from flag: %+v", err), exitCodeErrUnknown)
+		}
+		concurrency, err := cmd.Flags().GetInt("concurrency")
+		if err != nil {
+			exitError(fmt.Sprintf("failed to get concurrency from flag: %+v", err), exitCodeErrUnknown)
+		}
+		if concurrency < 1 {
+			exitError("concurrency must be at least 1", exitCodeErrKnown)
+		}
+		renderer, err := clusterservice.NewRenderer(clusterservice.OutputFormat(outputFormat))
+		if err != nil {
+			exitError(err.Error(), exitCodeErrKnown)
+		}
+		//resources are only found in the region the plan was made in
+		clusterService := buildAWSClient(newAWSSession(plan.Region), nil, logger)
+		clusterService.ContinueOnError = continueOnError
+		clusterService.PhaseTimeout = phaseTimeout
+		clusterService.Concurrency = concurrency
+		clusterService.SafetyPolicy = safetyPolicyFromFlags(cmd)
+		clusterService.FinalSnapshot = finalSnapshotPolicyFromFlags(cmd)
+		clusterService.ExpectedAccount = expectedAccountFromFlags(cmd)
+		interruptCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		ctx, cancel := context.WithTimeout(interruptCtx, timeout)
+		defer cancel()
+		verifyIdentity(ctx, clusterService, logger)
+		report, err := clusterService.ApplyPlan(ctx, plan)
+		if err != nil {
+			if report == nil {
+				exitError(fmt.Sprintf("failed to apply plan for cluster, clusterId=%s: %+v", plan.ClusterID, err), exitCodeErrUnknown)
+			}
+			logger.Debugf("failures occurred while applying plan: %+v", err)
+		}
+		printReport(renderer, report)
+		exitOnInterrupt(interruptCtx)
+		exitOnFailedItems(report)
+	},
+}
+
+func init() {
+	cleanupCmd.AddCommand(cleanupPlanCmd)
+	cleanupPlanCmd.Flags().StringP("output", "o", string(clusterservice.OutputFormatTable), fmt.Sprintf("set output format, one of %v", clusterservice.SupportedOutputFormats()))
+	cleanupPlanCmd.Flags().StringP("region", "r", "eu-west-1", "region to plan the cleanup in")
+	cleanupPlanCmd.Flags().Duration("timeout", 5*time.Minute, "duration before timing out, in-flight requests are cancelled once it elapses")
+	addTypesFlags(cleanupPlanCmd, "plan the cleanup of")
+	addTagsFlag(cleanupPlanCmd)
+	cleanupPlanCmd.Flags().String("out", "plan.json", "file to save the plan to")
+
+	cleanupCmd.AddCommand(cleanupApplyCmd)
+	cleanupApplyCmd.Flags().StringP("output", "o", string(clusterservice.OutputFormatTable), fmt.Sprintf("set output format, one of %v", clusterservice.SupportedOutputFormats()))
+	cleanupApplyCmd.Flags().Duration("timeout", 30*time.Minute, "duration before timing out, in-flight requests are cancelled once it elapses")
+	cleanupApplyCmd.Flags().Duration("phase-timeout", 0, "duration to wait for resources of a phase to be deleted before moving on to resources that depend on them, 0 to not wait")
+	cleanupApplyCmd.Flags().Int("concurrency", awsclusterservice.DefaultConcurrency, "maximum number of resource managers, and resources within each manager, handled at the same time")
+	cleanupApplyCmd.Flags().Bool("continue-on-error", false, "record failures in the report and continue cleaning up other resources, exits non-zero if any failed")
+	addSafetyPolicyFlags(cleanupApplyCmd)
+	addFinalSnapshotFlags(cleanupApplyCmd)
+	addExpectAccountFlag(cleanupApplyCmd)
+}
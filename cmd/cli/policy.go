@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	awsclusterservice "github.com/integr8ly/cluster-service/pkg/aws"
+)
+
+//addSafetyPolicyFlags Add the flags configuring the safety policy evaluated before every deletion
+func addSafetyPolicyFlags(cmd *cobra.Command) {
+	cmd.Flags().String("protection-tag", awsclusterservice.DefaultProtectionTagKey, "resources with this tag, with any value except false, are never deleted, empty to not check a tag")
+	cmd.Flags().StringSlice("allow", []string{}, "only delete resources whose arn or name matches one of these globs, e.g. arn:aws:s3:::my-cluster-*")
+	cmd.Flags().StringSlice("deny", []string{}, "never delete resources whose arn or name matches one of these globs")
+	cmd.Flags().Duration("min-age", 0, "never delete resources created more recently than this, resource types without a creation time, e.g. vpcs, aren't checked")
+}
+
+//addFinalSnapshotFlags Add the flags enabling final snapshots of data stores before they're deleted
+func addFinalSnapshotFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("final-snapshot", false, "take a final snapshot of rds instances and clusters and elasticache replication groups before deleting them")
+	cmd.Flags().Duration("final-snapshot-retention", awsclusterservice.DefaultFinalSnapshotRetention, "duration final snapshots are kept, later cleanups skip them until it elapses")
+}
+
+//addExpectAccountFlag Add the flag setting the account the credentials must belong to
+func addExpectAccountFlag(cmd *cobra.Command) {
+	cmd.Flags().String("expect-account", "", "id of the aws account the credentials must belong to, nothing is deleted when they belong to another account")
+}
+
+var accountIdRegexp = regexp.MustCompile(`^[0-9]{12}$`)
+
+//expectedAccountFromFlags Get the account the credentials must belong to from the flag added by addExpectAccountFlag
+func expectedAccountFromFlags(cmd *cobra.Command) string {
+	expectedAccount, err := cmd.Flags().GetString("expect-account")
+	if err != nil {
+		exitError(fmt.Sprintf("failed to get expected account from flag: %+v", err), exitCodeErrUnknown)
+	}
+	if expectedAccount != "" && !accountIdRegexp.MatchString(expectedAccount) {
+		exitError(fmt.Sprintf("expected account %s is not a 12 digit aws account id", expectedAccount), exitCodeErrKnown)
+	}
+	return expectedAccount
+}
+
+//verifyIdentity Check the credentials belong to the expected account before anything is deleted, logging who the credentials belong to
+func verifyIdentity(ctx context.Context, client *awsclusterservice.Client, logger *logrus.Entry) {
+	identity, err := client.VerifyIdentity(ctx)
+	if err != nil {
+		exitError(fmt.Sprintf("failed to verify aws credentials: %+v", err), exitCodeErrKnown)
+	}
+	if identity != nil {
+		logger.Infof("using credentials of %s in account %s", identity.ARN, identity.Account)
+	}
+}
+
+//safetyPolicyFromFlags Build the safety policy from the flags added by addSafetyPolicyFlags
+func safetyPolicyFromFlags(cmd *cobra.Command) *awsclusterservice.SafetyPolicy {
+	protectionTag, err := cmd.Flags().GetString("protection-tag")
+	if err != nil {
+		exitError(fmt.Sprintf("failed to get protection tag from flag: %+v", err), exitCodeErrUnknown)
+	}
+	allow, err := cmd.Flags().GetStringSlice("allow")
+	if err != nil {
+		exitError(fmt.Sprintf("failed to get allow rules from flag: %+v", err), exitCodeErrUnknown)
+	}
+	deny, err := cmd.Flags().GetStringSlice("deny")
+	if err != nil {
+		exitError(fmt.Sprintf("failed to get deny rules from flag: %+v", err), exitCodeErrUnknown)
+	}
+	minimumAge, err := cmd.Flags().GetDuration("min-age")
+	if err != nil {
+		exitError(fmt.Sprintf("failed to get minimum age from flag: %+v", err), exitCodeErrUnknown)
+	}
+	if minimumAge < 0 {
+		exitError("minimum age must not be negative", exitCodeErrKnown)
+	}
+	return &awsclusterservice.SafetyPolicy{
+		ProtectionTagKey: protectionTag,
+		Allow:            allow,
+		Deny:             deny,
+		MinimumAge:       minimumAge,
+	}
+}
+
+//finalSnapshotPolicyFromFlags Build the final snapshot policy from the flags added by addFinalSnapshotFlags, nil when final snapshots are disabled
+func finalSnapshotPolicyFromFlags(cmd *cobra.Command) *awsclusterservice.FinalSnapshotPolicy {
+	finalSnapshot, err := cmd.Flags().GetBool("final-snapshot")
+	if err != nil {
+		exitError(fmt.Sprintf("failed to get final snapshot from flag: %+v", err), exitCodeErrUnknown)
+	}
+	if !finalSnapshot {
+		return nil
+	}
+	retention, err := cmd.Flags().GetDuration("final-snapshot-retention")
+	if err != nil {
+		exitError(fmt.Sprintf("failed to get final snapshot retention from flag: %+v", err), exitCodeErrUnknown)
+	}
+	if retention <= 0 {
+		exitError("final snapshot retention must be positive", exitCodeErrKnown)
+	}
+	finalSnapshotPolicy := awsclusterservice.NewDefaultFinalSnapshotPolicy()
+	finalSnapshotPolicy.Retention = retention
+	return finalSnapshotPolicy
+}
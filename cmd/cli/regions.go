@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/spf13/cobra"
+
+	awsclusterservice "github.com/integr8ly/cluster-service/pkg/aws"
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+)
+
+//addRegionsFlags Add the flags selecting several regions instead of the single region flag
+func addRegionsFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("regions", []string{}, "regions to run in, overrides --region, e.g. eu-west-1,us-east-1")
+	cmd.Flags().Bool("all-regions", false, "run in every region enabled for the account, found using --region")
+}
+
+//regionsFromFlags Get the regions to run in from the region flag and the flags added by addRegionsFlags
+func regionsFromFlags(ctx context.Context, cmd *cobra.Command) []string {
+	region, err := cmd.Flags().GetString("region")
+	if err != nil {
+		exitError(fmt.Sprintf("failed to get region from flag: %+v", err), exitCodeErrUnknown)
+	}
+	regions, err := cmd.Flags().GetStringSlice("regions")
+	if err != nil {
+		exitError(fmt.Sprintf("failed to get regions from flag: %+v", err), exitCodeErrUnknown)
+	}
+	allRegions, err := cmd.Flags().GetBool("all-regions")
+	if err != nil {
+		exitError(fmt.Sprintf("failed to get all regions from flag: %+v", err), exitCodeErrUnknown)
+	}
+	if allRegions && len(regions) > 0 {
+		exitError("only one of --regions and --all-regions can be set", exitCodeErrKnown)
+	}
+	if allRegions {
+		enabledRegions, err := awsclusterservice.ListEnabledRegions(ctx, ec2.New(newAWSSession(region)))
+		if err != nil {
+			exitError(fmt.Sprintf("failed to find enabled regions: %+v", err), exitCodeErrKnown)
+		}
+		logger.Infof("running in %d enabled regions", len(enabledRegions))
+		return enabledRegions
+	}
+	if len(regions) > 0 {
+		return regions
+	}
+	return []string{region}
+}
+
+//buildRegionalClients Build an aws client for each region
+func buildRegionalClients(regions []string, buildFn func(region string) *awsclusterservice.Client) map[string]*awsclusterservice.Client {
+	clients := map[string]*awsclusterservice.Client{}
+	for _, region := range regions {
+		clients[region] = buildFn(region)
+	}
+	return clients
+}
+
+//combineRegionalClients Combine the clients of each region into a single client merging their results, a single region's client is used as-is
+func combineRegionalClients(clients map[string]*awsclusterservice.Client, continueOnError bool) clusterservice.Client {
+	regionalClients := map[string]clusterservice.Client{}
+	for region, client := range clients {
+		if len(clients) == 1 {
+			return client
+		}
+		regionalClients[region] = client
+	}
+	return clusterservice.NewMultiRegionClient(regionalClients, continueOnError)
+}
+
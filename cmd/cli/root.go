@@ -48,6 +48,7 @@ func init() {
 	// will be global for your application.
 	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "enable verbose logging (default is false)")
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.cli.yaml)")
+	addSessionFlags()
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -57,13 +58,6 @@ func init() {
 	logrus.SetFormatter(&logrus.TextFormatter{})
 }
 
-// initConfig reads in config file and ENV variables if set.
-func initConfig() {
-	if verbose {
-		logrus.SetLevel(logrus.DebugLevel)
-	}
-}
-
 func exitSuccess(message string) {
 	fmt.Fprintf(os.Stdout, message)
 	os.Exit(0)
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+
+	awsclusterservice "github.com/integr8ly/cluster-service/pkg/aws"
+)
+
+//sessionOptions Where aws credentials come from, set by the persistent credential flags
+var sessionOptions awsclusterservice.SessionOptions
+
+//addSessionFlags Add the flags choosing where aws credentials come from
+func addSessionFlags() {
+	rootCmd.PersistentFlags().StringVar(&sessionOptions.Profile, "profile", "", "shared config profile to use, defaults to the AWS_PROFILE env var or the default profile")
+	rootCmd.PersistentFlags().StringVar(&sessionOptions.RoleARN, "role-arn", "", "arn of a role to assume with the credentials found, e.g. a role in a customer account")
+	rootCmd.PersistentFlags().StringVar(&sessionOptions.ExternalID, "external-id", "", "external id required by the trust policy of the assumed role")
+	rootCmd.PersistentFlags().StringVar(&sessionOptions.SessionName, "session-name", "", fmt.Sprintf("name of the assumed role session, shown in cloudtrail (default %s)", awsclusterservice.DefaultSessionName))
+}
+
+//newAWSSession Build an aws session for a region using the standard credential chain and the credential flags, exits if it can't be built
+func newAWSSession(region string) *session.Session {
+	opts := sessionOptions
+	opts.Region = region
+	awsSession, err := awsclusterservice.NewSession(opts)
+	if err != nil {
+		exitError(fmt.Sprintf("failed to create aws session: %+v", err), exitCodeErrKnown)
+	}
+	return awsSession
+}
+
+//newAccountAWSSession Build an aws session for a region of another account, assuming the role of the account instead of the role flag when it has one
+func newAccountAWSSession(region string, roleARN string, externalID string) (*session.Session, error) {
+	opts := sessionOptions
+	opts.Region = region
+	if roleARN != "" {
+		opts.RoleARN = roleARN
+		opts.ExternalID = externalID
+	}
+	return awsclusterservice.NewSession(opts)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	awsclusterservice "github.com/integr8ly/cluster-service/pkg/aws"
+)
+
+// typesCmd represents the types command
+var typesCmd = &cobra.Command{
+	Use:   "types",
+	Short: "list the resource types which can be selected with --types and --exclude-types",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(writer, "TYPE\tMANAGER\tDESCRIPTION")
+		for _, manager := range awsclusterservice.RegisteredManagers() {
+			fmt.Fprintf(writer, "%s\t%s\t%s\n", manager.Name, manager.Type, manager.Description)
+		}
+		if err := writer.Flush(); err != nil {
+			exitError(fmt.Sprintf("failed to print types: %+v", err), exitCodeErrUnknown)
+		}
+	},
+}
+
+//addTypesFlags Add the flags selecting the resource types a command acts on
+func addTypesFlags(cmd *cobra.Command, action string) {
+	cmd.Flags().StringSliceP("types", "t", []string{}, fmt.Sprintf("resource types to %s, globs such as ec2:* are supported, every type when empty", action))
+	cmd.Flags().StringSlice("exclude-types", []string{}, fmt.Sprintf("resource types not to %s, globs such as ec2:* are supported", action))
+}
+
+//managersFromFlags Get the resource managers selected by the flags added by addTypesFlags, an unknown type is an error
+func managersFromFlags(cmd *cobra.Command) []*awsclusterservice.RegisteredManager {
+	types, err := cmd.Flags().GetStringSlice("types")
+	if err != nil {
+		exitError(fmt.Sprintf("failed to get types from flag: %+v", err), exitCodeErrUnknown)
+	}
+	excludeTypes, err := cmd.Flags().GetStringSlice("exclude-types")
+	if err != nil {
+		exitError(fmt.Sprintf("failed to get exclude types from flag: %+v", err), exitCodeErrUnknown)
+	}
+	managers, err := awsclusterservice.SelectManagers(types, excludeTypes)
+	if err != nil {
+		exitError(fmt.Sprintf("%s, run the types command to list the known types", err), exitCodeErrKnown)
+	}
+	return managers
+}
+
+func init() {
+	rootCmd.AddCommand(typesCmd)
+}
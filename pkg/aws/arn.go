@@ -0,0 +1,21 @@
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+//accountFromARN Get the account id from an arn, empty if the arn is invalid or has no account e.g. s3 buckets
+func accountFromARN(resourceARN string) string {
+	parsedARN, err := arn.Parse(resourceARN)
+	if err != nil {
+		return ""
+	}
+	return parsedARN.AccountID
+}
+
+//regionFromSession Get the region a session is configured to use
+func regionFromSession(session *session.Session) string {
+	return aws.StringValue(session.Config.Region)
+}
@@ -1,12 +1,28 @@
 package aws
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi/resourcegroupstaggingapiiface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	"github.com/integr8ly/cluster-service/pkg/clusterservice"
 	"github.com/integr8ly/cluster-service/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	//DefaultPhaseTimeout Time a watched cleanup waits for the items of a phase to complete, the default client doesn't wait so a single cleanup returns at once
+	DefaultPhaseTimeout = 5 * time.Minute
+	//DefaultPhaseInterval Time the default client waits between runs of a phase
+	DefaultPhaseInterval = 30 * time.Second
+	//DefaultConcurrency Number of engines, and items within each engine, the default client handles at the same time
+	DefaultConcurrency = 5
 )
 
 var _ clusterservice.Client = &Client{}
@@ -14,40 +30,381 @@ var _ clusterservice.Client = &Client{}
 type Client struct {
 	ResourceManagers []ClusterResourceManager
 	Logger           *logrus.Entry
+	//Region Region the resource managers act in, recorded on the report items of failed managers
+	Region string
+	//ContinueOnError Record manager failures in the report and run the remaining managers, instead of returning on the first failure
+	ContinueOnError bool
+	//PhaseTimeout How long to wait for the items of a phase to complete before moving on to the next phase, zero disables waiting
+	PhaseTimeout time.Duration
+	//PhaseInterval How often the managers of a phase are run while waiting for their items to complete
+	PhaseInterval time.Duration
+	//Concurrency Maximum number of engines run at the same time within a phase, also passed to engines to bound the items they delete at the same time
+	Concurrency int
+	//ManagerTimeout Deadline for a single run of an engine, zero disables the deadline
+	ManagerTimeout time.Duration
+	//ManagerSettings Settings overriding the concurrency and manager timeout for single engines, keyed by engine type
+	ManagerSettings map[ResourceManagerType]ManagerSettings
+	//TaggingClient Used to find the clusters in an account
+	TaggingClient resourcegroupstaggingapiiface.ResourceGroupsTaggingAPIAPI
+	//StateStore Persists pending resources and the merged report between runs, so a cleanup can resume where a previous one stopped
+	StateStore clusterservice.StateStore
+	//SafetyPolicy Evaluated by engines before every deletion, nothing is protected when nil
+	SafetyPolicy *SafetyPolicy
+	//FinalSnapshot Passed to engines of data stores to snapshot them before deleting them, no snapshots are taken when nil
+	FinalSnapshot *FinalSnapshotPolicy
+	//STSClient Used to verify the identity of the credentials before deleting resources, nothing is verified when nil
+	STSClient stsiface.STSAPI
+	//ExpectedAccount Account the credentials must belong to before resources are deleted, any account when empty
+	ExpectedAccount string
+
+	//targets Resources engines may act on while a plan is applied
+	targets map[string]bool
+	//identity Verified identity of the credentials
+	identity *clusterservice.CallerIdentity
 }
 
+//NewDefaultClient Build a client with the default settings running every registered resource manager
 func NewDefaultClient(awsSession *session.Session, logger *logrus.Entry) *Client {
-	log := logger.WithField("cluster_service_provider", "aws")
-	rdsManager := NewDefaultRDSInstanceManager(awsSession, logger)
-	rdsSnapshotManager := NewDefaultRDSSnapshotManager(awsSession, logger)
-	rdsSubnetGroupManager := NewDefaultRDSSubnetGroupManager(awsSession, logger)
-	s3Manager := NewDefaultS3Engine(awsSession, logger)
-	elasticacheManager := NewDefaultElasticacheManager(awsSession, logger)
-	elasticacheSnapshotManager := NewDefaultElasticacheSnapshotManager(awsSession, logger)
-	vpcPeeringManager := NewDefaultVpcPeeringManager(awsSession, logger)
-	subnetManager := NewDefaultSubnetManager(awsSession, logger)
-	securityGroupManager := NewDefaultSecurityGroupManager(awsSession, logger)
-	routeTableManager := NewDefaultRouteTableManager(awsSession, logger)
-	vpcManager := NewDefaultVpcManager(awsSession, logger)
+	return NewClientForManagers(awsSession, logger, managerRegistry)
+}
+
+//NewClientForManagers Build a client with the default settings running the provided resource managers
+func NewClientForManagers(awsSession *session.Session, logger *logrus.Entry, managers []*RegisteredManager) *Client {
+	resourceManagers := make([]ClusterResourceManager, 0, len(managers))
+	for _, manager := range managers {
+		resourceManagers = append(resourceManagers, manager.New(awsSession, logger))
+	}
 	return &Client{
-		ResourceManagers: []ClusterResourceManager{rdsManager, rdsSubnetGroupManager, elasticacheManager, s3Manager, rdsSnapshotManager, elasticacheSnapshotManager, vpcPeeringManager, subnetManager, securityGroupManager, routeTableManager, vpcManager},
-		Logger:           log,
+		ResourceManagers: resourceManagers,
+		Logger:           logger.WithField("cluster_service_provider", "aws"),
+		Region:           regionFromSession(awsSession),
+		PhaseInterval:    DefaultPhaseInterval,
+		Concurrency:      DefaultConcurrency,
+		TaggingClient:    resourcegroupstaggingapi.New(awsSession),
+		SafetyPolicy:     NewDefaultSafetyPolicy(),
+		STSClient:        sts.New(awsSession),
 	}
 }
 
 //DeleteResourcesForCluster Delete AWS resources based on tags using provided action engines
-func (c *Client) DeleteResourcesForCluster(clusterId string, tags map[string]string, dryRun bool) (*clusterservice.Report, error) {
+//Engines are run in phases ordered by their dependencies, a phase only starts once the items of earlier phases are no longer in progress or the phase timeout elapses
+//If the context is cancelled the report of the work done so far is returned along with the error
+//When a state store is set, the state is saved after every phase and a completed run returns the report merged with the reports of earlier runs
+func (c *Client) DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) (*clusterservice.Report, error) {
 	logger := c.Logger.WithFields(logrus.Fields{loggingKeyClusterID: clusterId, loggingKeyDryRun: dryRun})
 	logger.Debugf("deleting resources for cluster")
+	phases, err := buildPhases(c.ResourceManagers)
+	if err != nil {
+		return nil, errors.WrapLog(err, "failed to order resource managers", logger)
+	}
+	//verify the credentials before anything is discovered, so nothing is deleted in an unexpected account
+	identity, err := c.VerifyIdentity(ctx)
+	if err != nil {
+		return nil, errors.WrapLog(err, "failed to verify identity", logger)
+	}
+	c.configureEngines()
+	state, err := c.loadState(ctx, clusterId, logger)
+	if err != nil {
+		return nil, err
+	}
+	report := &clusterservice.Report{
+		Identity: identity,
+	}
+	var engineErrors []error
+	for i, phase := range phases {
+		if ctx.Err() != nil {
+			break
+		}
+		phaseLogger := logger.WithField(loggingKeyPhase, i+1)
+		phaseLogger.Debugf("running phase with %d engines", len(phase))
+		phaseReport, phaseErrors, err := c.runPhase(ctx, phase, clusterId, tags, dryRun, phaseLogger)
+		if err != nil {
+			return nil, err
+		}
+		report.Items = append(report.Items, phaseReport.Items...)
+		engineErrors = append(engineErrors, phaseErrors...)
+		//dry runs delete nothing, so there is no progress to save
+		if !dryRun {
+			if err := c.saveState(ctx, state, nil, logger); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if ctx.Err() != nil {
+		engineErrors = append(engineErrors, errors.WrapLog(ctx.Err(), "cleanup interrupted", logger))
+	}
+	//items missing from an incomplete run may not have been deleted, so only complete runs are merged into the saved report
+	if state != nil && !dryRun && ctx.Err() == nil {
+		if state.Report != nil {
+			state.Report.MergeForward(report)
+			report = state.Report
+		}
+		if err := c.saveState(ctx, state, report, logger); err != nil {
+			return nil, err
+		}
+	}
+	return report, errors.Aggregate(engineErrors)
+}
+
+//ApplyPlan Delete only the resources recorded in the plan
+//The current resources are listed first, planned resources that changed since the plan was made are skipped and those that no longer exist are reported complete
+//Listing must succeed for every engine, otherwise resources that failed to be listed could be mistaken for deleted ones
+func (c *Client) ApplyPlan(ctx context.Context, plan *clusterservice.Plan) (*clusterservice.Report, error) {
+	logger := c.Logger.WithField(loggingKeyClusterID, plan.ClusterID)
+	logger.Debugf("applying plan with %d resources", len(plan.Resources))
+	//when continuing on error a partial inventory is returned along with the error, it is refused all the same
+	inventory, err := c.ListResourcesForCluster(ctx, plan.ClusterID, plan.Tags)
+	if err != nil {
+		return nil, errors.WrapLog(err, "failed to list resources to compare with the plan", logger)
+	}
+	targets, refusedItems := plan.Compare(inventory)
+	for _, item := range refusedItems {
+		logger.Debugf("refusing planned resource %s: %s", item.ID, item.StatusReason)
+	}
+	c.targets = targets
+	defer func() {
+		c.targets = nil
+	}()
+	report, err := c.DeleteResourcesForCluster(ctx, plan.ClusterID, plan.Tags, false)
+	if report != nil {
+		report.Items = append(report.Items, refusedItems...)
+	}
+	return report, err
+}
+
+//ListResourcesForCluster List AWS resources based on tags using provided action engines, no resources are modified
+//Engines only read resources, so they're run without waiting on their dependencies
+func (c *Client) ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) (*clusterservice.Inventory, error) {
+	logger := c.Logger.WithField(loggingKeyClusterID, clusterId)
+	logger.Debugf("listing resources for cluster")
+	c.configureEngines()
+	engineResources := make([][]*clusterservice.Resource, len(c.ResourceManagers))
+	engineErrors := make([]error, len(c.ResourceManagers))
+	enginePool := newWorkerPool(c.Concurrency)
+	for i, engine := range c.ResourceManagers {
+		i, engine := i, engine
+		enginePool.Go(func() error {
+			engineLogger := logger.WithField(loggingKeyManager, engine.GetName())
+			engineCtx, cancel := c.engineContext(ctx, engine)
+			defer cancel()
+			resources, err := engine.ListResourcesForCluster(engineCtx, clusterId, tags)
+			engineResources[i] = resources
+			if err != nil {
+				engineErrors[i] = errors.WrapLog(err, fmt.Sprintf("failed to list resources with engine %s", engine.GetName()), engineLogger)
+			}
+			return nil
+		})
+	}
+	enginePool.Wait()
+	inventory := &clusterservice.Inventory{}
+	var failedEngineErrors []error
+	for i := range c.ResourceManagers {
+		if engineErrors[i] != nil {
+			if !c.ContinueOnError {
+				return nil, engineErrors[i]
+			}
+			failedEngineErrors = append(failedEngineErrors, engineErrors[i])
+		}
+		inventory.Resources = append(inventory.Resources, engineResources[i]...)
+	}
+	return inventory, errors.Aggregate(failedEngineErrors)
+}
+
+//runPhase Run the engines of a phase until none of their items are in progress or the phase timeout elapses
+func (c *Client) runPhase(ctx context.Context, phase []ClusterResourceManager, clusterId string, tags map[string]string, dryRun bool, logger *logrus.Entry) (*clusterservice.Report, []error, error) {
+	phaseReport, phaseErrors, err := c.runEngines(ctx, phase, clusterId, tags, dryRun, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	//dry runs never progress, so there is nothing to wait for
+	if dryRun || c.PhaseTimeout <= 0 || !containsItemInProgress(phaseReport.Items) {
+		return phaseReport, phaseErrors, nil
+	}
+	interval := c.PhaseInterval
+	if interval <= 0 {
+		interval = DefaultPhaseInterval
+	}
+	logger.Debugf("waiting up to %s for items in progress to complete", c.PhaseTimeout)
+	err = wait.PollWithContext(ctx, interval, c.PhaseTimeout, func(ctx context.Context) (bool, error) {
+		nextReport, nextErrors, err := c.runEngines(ctx, phase, clusterId, tags, dryRun, logger)
+		if err != nil {
+			return false, err
+		}
+		phaseReport.MergeForward(nextReport)
+		phaseErrors = nextErrors
+		return !containsItemInProgress(phaseReport.Items), nil
+	})
+	if err == wait.ErrWaitTimeout {
+		logger.Debugf("items still in progress after %s, continuing with the next phase", c.PhaseTimeout)
+		return phaseReport, phaseErrors, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return phaseReport, phaseErrors, nil
+}
+
+//runEngines Run each engine once, at most Concurrency at the same time, and collect their report items in the order of the engines
+//The returned error is only set when an engine fails and failures should not be recorded in the report
+//Failures after the context is cancelled are always recorded, so the work done so far can be reported
+func (c *Client) runEngines(ctx context.Context, engines []ClusterResourceManager, clusterId string, tags map[string]string, dryRun bool, logger *logrus.Entry) (*clusterservice.Report, []error, error) {
+	engineReportItems := make([][]*clusterservice.ReportItem, len(engines))
+	engineErrors := make([]error, len(engines))
+	enginePool := newWorkerPool(c.Concurrency)
+	for i, engine := range engines {
+		i, engine := i, engine
+		enginePool.Go(func() error {
+			engineLogger := logger.WithField(loggingKeyManager, engine.GetName())
+			engineLogger.Debugf("found Logger")
+			engineCtx, cancel := c.engineContext(ctx, engine)
+			defer cancel()
+			reportItems, err := engine.DeleteResourcesForCluster(engineCtx, clusterId, tags, dryRun)
+			engineReportItems[i] = reportItems
+			if err != nil {
+				engineErrors[i] = errors.WrapLog(err, fmt.Sprintf("failed to run engine %s", engine.GetName()), engineLogger)
+				//failures before any items were found, e.g. during discovery, are recorded against the manager itself
+				if !containsFailedItem(reportItems) {
+					engineReportItems[i] = append(engineReportItems[i], buildManagerFailureReportItem(engine, c.Region, err))
+				}
+			}
+			return nil
+		})
+	}
+	enginePool.Wait()
 	report := &clusterservice.Report{}
+	var failedEngineErrors []error
+	for i := range engines {
+		if engineErrors[i] != nil {
+			if !c.ContinueOnError && ctx.Err() == nil {
+				return nil, nil, engineErrors[i]
+			}
+			failedEngineErrors = append(failedEngineErrors, engineErrors[i])
+		}
+		report.Items = append(report.Items, engineReportItems[i]...)
+	}
+	return report, failedEngineErrors, nil
+}
+
+//loadState Load the state of the cluster and restore the pending resources of every engine, nil when no state store is set
+func (c *Client) loadState(ctx context.Context, clusterId string, logger *logrus.Entry) (*clusterservice.State, error) {
+	if c.StateStore == nil {
+		return nil, nil
+	}
+	state, err := c.StateStore.Load(ctx)
+	if err != nil {
+		return nil, errors.WrapLog(err, "failed to load state", logger)
+	}
+	if state == nil {
+		return &clusterservice.State{ClusterID: clusterId}, nil
+	}
+	if state.ClusterID != clusterId {
+		return nil, errors.WrapLog(fmt.Errorf("state belongs to cluster %s", state.ClusterID), "failed to load state", logger)
+	}
 	for _, engine := range c.ResourceManagers {
-		engineLogger := logger.WithField(loggingKeyManager, engine.GetName())
-		engineLogger.Debugf("found Logger")
-		reportItems, err := engine.DeleteResourcesForCluster(clusterId, tags, dryRun)
-		if err != nil {
-			return nil, errors.WrapLog(err, fmt.Sprintf("failed to run engine %s", engine.GetName()), engineLogger)
+		if stateful, ok := engine.(StatefulClusterResourceManager); ok {
+			stateful.RestorePendingResources(state.Pending[string(stateful.GetType())])
+		}
+	}
+	return state, nil
+}
+
+//saveState Save the pending resources of every engine, along with the report when provided
+func (c *Client) saveState(ctx context.Context, state *clusterservice.State, report *clusterservice.Report, logger *logrus.Entry) error {
+	if state == nil {
+		return nil
+	}
+	state.Pending = map[string][]string{}
+	for _, engine := range c.ResourceManagers {
+		if stateful, ok := engine.(StatefulClusterResourceManager); ok {
+			if pending := stateful.GetPendingResources(); len(pending) > 0 {
+				state.Pending[string(stateful.GetType())] = pending
+			}
+		}
+	}
+	if report != nil {
+		state.Report = report
+	}
+	if err := c.StateStore.Save(ctx, state); err != nil {
+		return errors.WrapLog(err, "failed to save state", logger)
+	}
+	return nil
+}
+
+//configureEngines Pass the client settings to every engine that accepts them
+func (c *Client) configureEngines() {
+	for _, engine := range c.ResourceManagers {
+		if configurable, ok := engine.(ConfigurableClusterResourceManager); ok {
+			options := ManagerOptions{
+				Concurrency:   c.Concurrency,
+				Targets:       c.targets,
+				Policy:        c.SafetyPolicy,
+				FinalSnapshot: c.FinalSnapshot,
+			}
+			if settings := c.engineSettings(engine); settings.Concurrency > 0 {
+				options.Concurrency = settings.Concurrency
+			}
+			configurable.Configure(options)
 		}
-		report.Items = append(report.Items, reportItems...)
 	}
-	return report, nil
+}
+
+//engineSettings Settings of a single engine, empty when the engine has no type or no settings
+func (c *Client) engineSettings(engine ClusterResourceManager) ManagerSettings {
+	typedEngine, ok := engine.(interface{ GetType() ResourceManagerType })
+	if !ok {
+		return ManagerSettings{}
+	}
+	return c.ManagerSettings[typedEngine.GetType()]
+}
+
+//engineManager Manager of the report items of an engine, the same value engines set on their own items, the name of engines without a type
+func engineManager(engine ClusterResourceManager) string {
+	typedEngine, ok := engine.(interface{ GetType() ResourceManagerType })
+	if !ok {
+		return engine.GetName()
+	}
+	return string(typedEngine.GetType())
+}
+
+//engineContext Context for a single run of an engine, bounded by the timeout of the engine or the manager timeout
+func (c *Client) engineContext(ctx context.Context, engine ClusterResourceManager) (context.Context, context.CancelFunc) {
+	timeout := c.ManagerTimeout
+	if settings := c.engineSettings(engine); settings.Timeout > 0 {
+		timeout = settings.Timeout
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+func containsItemInProgress(reportItems []*clusterservice.ReportItem) bool {
+	for _, reportItem := range reportItems {
+		if reportItem.ActionStatus == clusterservice.ActionStatusInProgress {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFailedItem(reportItems []*clusterservice.ReportItem) bool {
+	for _, reportItem := range reportItems {
+		if reportItem.ActionStatus == clusterservice.ActionStatusFailed {
+			return true
+		}
+	}
+	return false
+}
+
+func buildManagerFailureReportItem(engine ClusterResourceManager, region string, err error) *clusterservice.ReportItem {
+	return &clusterservice.ReportItem{
+		//managers run in every region, so their name alone would collide when the reports of regions are merged
+		ID:           fmt.Sprintf("%s:%s", region, engine.GetName()),
+		Name:         engine.GetName(),
+		Region:       region,
+		Manager:      engineManager(engine),
+		Action:       clusterservice.ActionDelete,
+		ActionStatus: clusterservice.ActionStatusFailed,
+		StatusReason: err.Error(),
+	}
 }
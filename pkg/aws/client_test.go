@@ -1,10 +1,15 @@
 package aws
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/integr8ly/cluster-service/pkg/clusterservice"
 	"github.com/sirupsen/logrus"
 )
@@ -18,8 +23,11 @@ func TestClient_DeleteResourcesForCluster(t *testing.T) {
 	}
 
 	type fields struct {
-		actionEngines func() []ClusterResourceManager
-		logger        *logrus.Entry
+		actionEngines   func() []ClusterResourceManager
+		logger          *logrus.Entry
+		continueOnError bool
+		phaseTimeout    time.Duration
+		concurrency     int
 	}
 	type args struct {
 		clusterId string
@@ -38,7 +46,7 @@ func TestClient_DeleteResourcesForCluster(t *testing.T) {
 			fields: fields{
 				actionEngines: func() []ClusterResourceManager {
 					fakeEngine, err := fakeClusterManager(func(e *ClusterResourceManagerMock) error {
-						e.DeleteResourcesForClusterFunc = func(clusterId string, tags map[string]string, dryRun bool) (items []*clusterservice.ReportItem, e error) {
+						e.DeleteResourcesForClusterFunc = func(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) (items []*clusterservice.ReportItem, e error) {
 							return nil, errors.New("")
 						}
 						return nil
@@ -57,6 +65,257 @@ func TestClient_DeleteResourcesForCluster(t *testing.T) {
 			},
 			wantErr: "failed to run engine Fake Action Engine: ",
 		},
+		{
+			name: "failures are recorded and remaining engines run when continue on error is enabled",
+			fields: fields{
+				actionEngines: func() []ClusterResourceManager {
+					fakeFailedDiscoveryEngine, err := fakePhasedClusterManager(managerS3, nil, func(e *ClusterResourceManagerMock) error {
+						e.DeleteResourcesForClusterFunc = func(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) (items []*clusterservice.ReportItem, e error) {
+							return nil, errors.New("discovery error")
+						}
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					fakeFailedItemEngine, err := fakeClusterManager(func(e *ClusterResourceManagerMock) error {
+						e.DeleteResourcesForClusterFunc = func(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) (items []*clusterservice.ReportItem, e error) {
+							return []*clusterservice.ReportItem{
+								mockReportItem(func(item *clusterservice.ReportItem) {
+									item.ID = fakeARN
+									item.Name = fakeResourceIdentifier
+									item.Action = clusterservice.ActionDelete
+									item.ActionStatus = clusterservice.ActionStatusFailed
+									item.StatusReason = "delete error"
+								}),
+							}, errors.New("delete error")
+						}
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					fakeEngine, err := fakeClusterManager(func(e *ClusterResourceManagerMock) error {
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return []ClusterResourceManager{fakeFailedDiscoveryEngine, fakeFailedItemEngine, fakeEngine}
+				},
+				logger:          fakeLogger,
+				continueOnError: true,
+			},
+			args: args{
+				clusterId: fakeRDSClientTagVal,
+				tags:      map[string]string{},
+				dryRun:    false,
+			},
+			want: &clusterservice.Report{
+				Items: []*clusterservice.ReportItem{
+					mockReportItem(func(item *clusterservice.ReportItem) {
+						item.ID = fakeRegion + ":" + fakeResourceManagerName
+						item.Name = fakeResourceManagerName
+						item.Region = fakeRegion
+						item.Manager = string(managerS3)
+						item.Action = clusterservice.ActionDelete
+						item.ActionStatus = clusterservice.ActionStatusFailed
+						item.StatusReason = "discovery error"
+					}),
+					mockReportItem(func(item *clusterservice.ReportItem) {
+						item.ID = fakeARN
+						item.Name = fakeResourceIdentifier
+						item.Action = clusterservice.ActionDelete
+						item.ActionStatus = clusterservice.ActionStatusFailed
+						item.StatusReason = "delete error"
+					}),
+					mockReportItem(func(item *clusterservice.ReportItem) {
+						item.ID = fakeARN
+						item.Name = fakeResourceIdentifier
+						item.Action = clusterservice.ActionDelete
+						item.ActionStatus = clusterservice.ActionStatusInProgress
+					}),
+				},
+			},
+			wantErr: "2 errors occurred: failed to run engine Fake Action Engine: discovery error; failed to run engine Fake Action Engine: delete error",
+		},
+		{
+			name: "dependent engines run after the items of their dependencies complete",
+			fields: fields{
+				actionEngines: func() []ClusterResourceManager {
+					fakeSubnetGroupEngine, err := fakePhasedClusterManager(managerRDSSubnetGroup, []ResourceManagerType{managerRDS}, func(e *ClusterResourceManagerMock) error {
+						e.DeleteResourcesForClusterFunc = func(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) (items []*clusterservice.ReportItem, e error) {
+							return []*clusterservice.ReportItem{
+								mockReportItem(func(item *clusterservice.ReportItem) {
+									item.ID = fakeRDSClientDBSubnetGroupARN
+									item.Name = fakeResourceIdentifier
+									item.Action = clusterservice.ActionDelete
+									item.ActionStatus = clusterservice.ActionStatusComplete
+								}),
+							}, nil
+						}
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					rdsCalls := 0
+					fakeRDSEngine, err := fakePhasedClusterManager(managerRDS, nil, func(e *ClusterResourceManagerMock) error {
+						e.DeleteResourcesForClusterFunc = func(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) (items []*clusterservice.ReportItem, e error) {
+							rdsCalls++
+							//the instance is gone once the second run happens
+							if rdsCalls > 1 {
+								return nil, nil
+							}
+							return []*clusterservice.ReportItem{
+								mockReportItem(func(item *clusterservice.ReportItem) {
+									item.ID = fakeRDSClientInstanceARN
+									item.Name = fakeResourceIdentifier
+									item.Action = clusterservice.ActionDelete
+									item.ActionStatus = clusterservice.ActionStatusInProgress
+								}),
+							}, nil
+						}
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return []ClusterResourceManager{fakeSubnetGroupEngine, fakeRDSEngine}
+				},
+				logger:       fakeLogger,
+				phaseTimeout: time.Second,
+			},
+			args: args{
+				clusterId: fakeRDSClientTagVal,
+				tags:      map[string]string{},
+				dryRun:    false,
+			},
+			want: &clusterservice.Report{
+				Items: []*clusterservice.ReportItem{
+					mockReportItem(func(item *clusterservice.ReportItem) {
+						item.ID = fakeRDSClientInstanceARN
+						item.Name = fakeResourceIdentifier
+						item.Action = clusterservice.ActionDelete
+						item.ActionStatus = clusterservice.ActionStatusComplete
+					}),
+					mockReportItem(func(item *clusterservice.ReportItem) {
+						item.ID = fakeRDSClientDBSubnetGroupARN
+						item.Name = fakeResourceIdentifier
+						item.Action = clusterservice.ActionDelete
+						item.ActionStatus = clusterservice.ActionStatusComplete
+					}),
+				},
+			},
+		},
+		{
+			name: "next phase runs when the phase timeout elapses",
+			fields: fields{
+				actionEngines: func() []ClusterResourceManager {
+					fakeSubnetGroupEngine, err := fakePhasedClusterManager(managerRDSSubnetGroup, []ResourceManagerType{managerRDS}, func(e *ClusterResourceManagerMock) error {
+						e.DeleteResourcesForClusterFunc = func(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) (items []*clusterservice.ReportItem, e error) {
+							return []*clusterservice.ReportItem{
+								mockReportItem(func(item *clusterservice.ReportItem) {
+									item.ID = fakeRDSClientDBSubnetGroupARN
+									item.Name = fakeResourceIdentifier
+									item.Action = clusterservice.ActionDelete
+									item.ActionStatus = clusterservice.ActionStatusSkipped
+								}),
+							}, nil
+						}
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					fakeRDSEngine, err := fakePhasedClusterManager(managerRDS, nil, func(e *ClusterResourceManagerMock) error {
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return []ClusterResourceManager{fakeSubnetGroupEngine, fakeRDSEngine}
+				},
+				logger:       fakeLogger,
+				phaseTimeout: 10 * time.Millisecond,
+			},
+			args: args{
+				clusterId: fakeRDSClientTagVal,
+				tags:      map[string]string{},
+				dryRun:    false,
+			},
+			want: &clusterservice.Report{
+				Items: []*clusterservice.ReportItem{
+					mockReportItem(func(item *clusterservice.ReportItem) {
+						item.ID = fakeARN
+						item.Name = fakeResourceIdentifier
+						item.Action = clusterservice.ActionDelete
+						item.ActionStatus = clusterservice.ActionStatusInProgress
+					}),
+					mockReportItem(func(item *clusterservice.ReportItem) {
+						item.ID = fakeRDSClientDBSubnetGroupARN
+						item.Name = fakeResourceIdentifier
+						item.Action = clusterservice.ActionDelete
+						item.ActionStatus = clusterservice.ActionStatusSkipped
+					}),
+				},
+			},
+		},
+		{
+			name: "engines in the same phase run concurrently and items keep the engine order",
+			fields: fields{
+				actionEngines: func() []ClusterResourceManager {
+					//each engine waits for the other to start, which only happens when they run at the same time
+					started := &sync.WaitGroup{}
+					started.Add(2)
+					blockingEngine := func(id string) ClusterResourceManager {
+						fakeEngine, err := fakeClusterManager(func(e *ClusterResourceManagerMock) error {
+							e.DeleteResourcesForClusterFunc = func(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) (items []*clusterservice.ReportItem, e error) {
+								started.Done()
+								started.Wait()
+								return []*clusterservice.ReportItem{
+									mockReportItem(func(item *clusterservice.ReportItem) {
+										item.ID = id
+										item.Name = fakeResourceIdentifier
+										item.Action = clusterservice.ActionDelete
+										item.ActionStatus = clusterservice.ActionStatusDryRun
+									}),
+								}, nil
+							}
+							return nil
+						})
+						if err != nil {
+							t.Fatal(err)
+						}
+						return fakeEngine
+					}
+					return []ClusterResourceManager{blockingEngine("first"), blockingEngine("second")}
+				},
+				logger:      fakeLogger,
+				concurrency: 2,
+			},
+			args: args{
+				clusterId: fakeRDSClientTagVal,
+				tags:      map[string]string{},
+				dryRun:    true,
+			},
+			want: &clusterservice.Report{
+				Items: []*clusterservice.ReportItem{
+					mockReportItem(func(item *clusterservice.ReportItem) {
+						item.ID = "first"
+						item.Name = fakeResourceIdentifier
+						item.Action = clusterservice.ActionDelete
+						item.ActionStatus = clusterservice.ActionStatusDryRun
+					}),
+					mockReportItem(func(item *clusterservice.ReportItem) {
+						item.ID = "second"
+						item.Name = fakeResourceIdentifier
+						item.Action = clusterservice.ActionDelete
+						item.ActionStatus = clusterservice.ActionStatusDryRun
+					}),
+				},
+			},
+		},
 		{
 			name: "multiple engine report items are appended",
 			fields: fields{
@@ -68,7 +327,7 @@ func TestClient_DeleteResourcesForCluster(t *testing.T) {
 						t.Fatal(err)
 					}
 					fakeDryRunEngine, err := fakeClusterManager(func(e *ClusterResourceManagerMock) error {
-						e.DeleteResourcesForClusterFunc = func(clusterId string, tags map[string]string, dryRun bool) (items []*clusterservice.ReportItem, e error) {
+						e.DeleteResourcesForClusterFunc = func(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) (items []*clusterservice.ReportItem, e error) {
 							return []*clusterservice.ReportItem{
 								mockReportItem(func(item *clusterservice.ReportItem) {
 									item.ID = fakeRDSClientInstanceARN
@@ -115,8 +374,13 @@ func TestClient_DeleteResourcesForCluster(t *testing.T) {
 			c := &Client{
 				ResourceManagers: tt.fields.actionEngines(),
 				Logger:           tt.fields.logger,
+				Region:           fakeRegion,
+				ContinueOnError:  tt.fields.continueOnError,
+				PhaseTimeout:     tt.fields.phaseTimeout,
+				PhaseInterval:    time.Millisecond,
+				Concurrency:      tt.fields.concurrency,
 			}
-			got, err := c.DeleteResourcesForCluster(tt.args.clusterId, tt.args.tags, tt.args.dryRun)
+			got, err := c.DeleteResourcesForCluster(context.TODO(), tt.args.clusterId, tt.args.tags, tt.args.dryRun)
 			if err != nil && err.Error() != tt.wantErr {
 				t.Errorf("DeleteResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -127,3 +391,567 @@ func TestClient_DeleteResourcesForCluster(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_DeleteResourcesForCluster_Context(t *testing.T) {
+	fakeLogger, err := fakeLogger(func(l *logrus.Entry) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name           string
+		actionEngines  func(cancel context.CancelFunc) []ClusterResourceManager
+		managerTimeout time.Duration
+		want           *clusterservice.Report
+		wantErr        string
+	}{
+		{
+			name: "partial report is returned and later phases are not run when the context is cancelled",
+			actionEngines: func(cancel context.CancelFunc) []ClusterResourceManager {
+				fakeRDSEngine, err := fakePhasedClusterManager(managerRDS, nil, func(e *ClusterResourceManagerMock) error {
+					e.DeleteResourcesForClusterFunc = func(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
+						cancel()
+						return []*clusterservice.ReportItem{
+							mockReportItem(func(item *clusterservice.ReportItem) {
+								item.ID = fakeRDSClientInstanceARN
+								item.Name = fakeResourceIdentifier
+								item.Action = clusterservice.ActionDelete
+								item.ActionStatus = clusterservice.ActionStatusInProgress
+							}),
+						}, nil
+					}
+					return nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				fakeSubnetGroupEngine, err := fakePhasedClusterManager(managerRDSSubnetGroup, []ResourceManagerType{managerRDS}, func(e *ClusterResourceManagerMock) error {
+					e.DeleteResourcesForClusterFunc = func(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
+						t.Error("engines in later phases should not run once the context is cancelled")
+						return nil, nil
+					}
+					return nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				return []ClusterResourceManager{fakeRDSEngine, fakeSubnetGroupEngine}
+			},
+			want: &clusterservice.Report{
+				Items: []*clusterservice.ReportItem{
+					mockReportItem(func(item *clusterservice.ReportItem) {
+						item.ID = fakeRDSClientInstanceARN
+						item.Name = fakeResourceIdentifier
+						item.Action = clusterservice.ActionDelete
+						item.ActionStatus = clusterservice.ActionStatusInProgress
+					}),
+				},
+			},
+			wantErr: "cleanup interrupted: context canceled",
+		},
+		{
+			name: "engines exceeding the manager timeout are stopped",
+			actionEngines: func(cancel context.CancelFunc) []ClusterResourceManager {
+				fakeEngine, err := fakeClusterManager(func(e *ClusterResourceManagerMock) error {
+					e.DeleteResourcesForClusterFunc = func(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
+						<-ctx.Done()
+						return nil, ctx.Err()
+					}
+					return nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				return []ClusterResourceManager{fakeEngine}
+			},
+			managerTimeout: 10 * time.Millisecond,
+			wantErr:        "failed to run engine Fake Action Engine: context deadline exceeded",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			c := &Client{
+				ResourceManagers: tt.actionEngines(cancel),
+				Logger:           fakeLogger,
+				ManagerTimeout:   tt.managerTimeout,
+			}
+			got, err := c.DeleteResourcesForCluster(ctx, fakeClusterId, map[string]string{}, false)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("DeleteResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DeleteResourcesForCluster() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_ListResourcesForCluster(t *testing.T) {
+	fakeLogger, err := fakeLogger(func(l *logrus.Entry) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeEngine := func(name string, err error) ClusterResourceManager {
+		engine, fakeErr := fakeClusterManager(func(e *ClusterResourceManagerMock) error {
+			e.GetNameFunc = func() string {
+				return name
+			}
+			e.DeleteResourcesForClusterFunc = func(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
+				t.Error("resources should not be deleted when listing")
+				return nil, nil
+			}
+			e.ListResourcesForClusterFunc = func(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+				if err != nil {
+					return nil, err
+				}
+				return []*clusterservice.Resource{{ID: name, Name: name}}, nil
+			}
+			return nil
+		})
+		if fakeErr != nil {
+			t.Fatal(fakeErr)
+		}
+		return engine
+	}
+	tests := []struct {
+		name            string
+		actionEngines   []ClusterResourceManager
+		continueOnError bool
+		want            *clusterservice.Inventory
+		wantErr         string
+	}{
+		{
+			name:          "resources of every engine are listed in the order of the engines",
+			actionEngines: []ClusterResourceManager{fakeEngine("first", nil), fakeEngine("second", nil)},
+			want: &clusterservice.Inventory{
+				Resources: []*clusterservice.Resource{
+					{ID: "first", Name: "first"},
+					{ID: "second", Name: "second"},
+				},
+			},
+		},
+		{
+			name:          "error when an engine fails to list resources",
+			actionEngines: []ClusterResourceManager{fakeEngine("first", nil), fakeEngine("second", errors.New("list error"))},
+			wantErr:       "failed to list resources with engine second: list error",
+		},
+		{
+			name:            "partial inventory is returned with the error when continuing on error",
+			actionEngines:   []ClusterResourceManager{fakeEngine("first", errors.New("list error")), fakeEngine("second", nil)},
+			continueOnError: true,
+			want: &clusterservice.Inventory{
+				Resources: []*clusterservice.Resource{
+					{ID: "second", Name: "second"},
+				},
+			},
+			wantErr: "failed to list resources with engine first: list error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{
+				ResourceManagers: tt.actionEngines,
+				Logger:           fakeLogger,
+				ContinueOnError:  tt.continueOnError,
+			}
+			got, err := c.ListResourcesForCluster(context.TODO(), fakeClusterId, map[string]string{})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ListResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("ListResourcesForCluster() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListResourcesForCluster() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteResourcesForCluster_State(t *testing.T) {
+	fakeLogger, err := fakeLogger(func(l *logrus.Entry) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeInProgressItem := func(id string) *clusterservice.ReportItem {
+		return mockReportItem(func(item *clusterservice.ReportItem) {
+			item.ID = id
+			item.Name = fakeResourceIdentifier
+			item.Action = clusterservice.ActionDelete
+			item.ActionStatus = clusterservice.ActionStatusInProgress
+		})
+	}
+	tests := []struct {
+		name        string
+		state       *clusterservice.State
+		pending     []string
+		dryRun      bool
+		want        *clusterservice.Report
+		wantState   *clusterservice.State
+		wantPending []string
+		wantErr     string
+	}{
+		{
+			name:    "pending resources are saved along with the report",
+			pending: []string{"subnet-group"},
+			want: &clusterservice.Report{
+				Items: []*clusterservice.ReportItem{fakeInProgressItem(fakeARN)},
+			},
+			wantState: &clusterservice.State{
+				ClusterID: fakeClusterId,
+				Report: &clusterservice.Report{
+					Items: []*clusterservice.ReportItem{fakeInProgressItem(fakeARN)},
+				},
+				Pending: map[string][]string{string(managerElasticache): {"subnet-group"}},
+			},
+			wantPending: []string{"subnet-group"},
+		},
+		{
+			name: "pending resources of a previous run are restored and the report is merged",
+			state: &clusterservice.State{
+				ClusterID: fakeClusterId,
+				Report: &clusterservice.Report{
+					Items: []*clusterservice.ReportItem{fakeInProgressItem("deleted")},
+				},
+				Pending: map[string][]string{string(managerElasticache): {"subnet-group"}},
+			},
+			want: &clusterservice.Report{
+				Items: []*clusterservice.ReportItem{
+					mockReportItem(func(item *clusterservice.ReportItem) {
+						item.ID = "deleted"
+						item.Name = fakeResourceIdentifier
+						item.Action = clusterservice.ActionDelete
+						item.ActionStatus = clusterservice.ActionStatusComplete
+					}),
+					fakeInProgressItem(fakeARN),
+				},
+			},
+			wantState: &clusterservice.State{
+				ClusterID: fakeClusterId,
+				Report: &clusterservice.Report{
+					Items: []*clusterservice.ReportItem{
+						mockReportItem(func(item *clusterservice.ReportItem) {
+							item.ID = "deleted"
+							item.Name = fakeResourceIdentifier
+							item.Action = clusterservice.ActionDelete
+							item.ActionStatus = clusterservice.ActionStatusComplete
+						}),
+						fakeInProgressItem(fakeARN),
+					},
+				},
+				Pending: map[string][]string{string(managerElasticache): {"subnet-group"}},
+			},
+			wantPending: []string{"subnet-group"},
+		},
+		{
+			name: "dry runs restore pending resources without saving",
+			state: &clusterservice.State{
+				ClusterID: fakeClusterId,
+				Pending:   map[string][]string{string(managerElasticache): {"subnet-group"}},
+			},
+			dryRun: true,
+			want: &clusterservice.Report{
+				Items: []*clusterservice.ReportItem{fakeInProgressItem(fakeARN)},
+			},
+			wantState: &clusterservice.State{
+				ClusterID: fakeClusterId,
+				Pending:   map[string][]string{string(managerElasticache): {"subnet-group"}},
+			},
+			wantPending: []string{"subnet-group"},
+		},
+		{
+			name: "error when the state belongs to another cluster",
+			state: &clusterservice.State{
+				ClusterID: "other",
+			},
+			wantState: &clusterservice.State{
+				ClusterID: "other",
+			},
+			wantErr: "failed to load state: state belongs to cluster other",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phasedEngine, err := fakePhasedClusterManager(managerElasticache, nil, func(e *ClusterResourceManagerMock) error {
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			engine := &fakeStatefulManager{fakePhasedManager: phasedEngine, pending: tt.pending}
+			stateStore := &fakeStateStore{state: tt.state}
+			c := &Client{
+				ResourceManagers: []ClusterResourceManager{engine},
+				Logger:           fakeLogger,
+				StateStore:       stateStore,
+			}
+			got, err := c.DeleteResourcesForCluster(context.TODO(), fakeClusterId, map[string]string{}, tt.dryRun)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("DeleteResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("DeleteResourcesForCluster() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DeleteResourcesForCluster() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(stateStore.state, tt.wantState) {
+				t.Errorf("saved state got = %+v, want %+v", stateStore.state, tt.wantState)
+			}
+			if !reflect.DeepEqual(engine.pending, tt.wantPending) {
+				t.Errorf("pending resources got = %v, want %v", engine.pending, tt.wantPending)
+			}
+			if tt.dryRun && stateStore.saves != 0 {
+				t.Errorf("state saved %d times during a dry run", stateStore.saves)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteResourcesForCluster_Identity(t *testing.T) {
+	fakeLogger, err := fakeLogger(func(l *logrus.Entry) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name            string
+		stsClient       *mockSTSClient
+		expectedAccount string
+		wantIdentity    *clusterservice.CallerIdentity
+		wantDeleted     bool
+		wantErr         string
+	}{
+		{
+			name:         "identity is recorded in the report",
+			stsClient:    buildMockSTSClient(nil),
+			wantIdentity: &clusterservice.CallerIdentity{Account: fakeAccountId, ARN: fakeCallerARN},
+			wantDeleted:  true,
+		},
+		{
+			name:            "resources are deleted when the credentials belong to the expected account",
+			stsClient:       buildMockSTSClient(nil),
+			expectedAccount: fakeAccountId,
+			wantIdentity:    &clusterservice.CallerIdentity{Account: fakeAccountId, ARN: fakeCallerARN},
+			wantDeleted:     true,
+		},
+		{
+			name:        "identity is not verified without an sts client",
+			wantDeleted: true,
+		},
+		{
+			name:            "nothing is deleted when the credentials belong to another account",
+			stsClient:       buildMockSTSClient(nil),
+			expectedAccount: "210987654321",
+			wantErr:         fmt.Sprintf("failed to verify identity: credentials of %s belong to account %s, expected account 210987654321", fakeCallerARN, fakeAccountId),
+		},
+		{
+			name:            "nothing is deleted when the account can't be verified",
+			expectedAccount: fakeAccountId,
+			wantErr:         fmt.Sprintf("failed to verify identity: cannot verify the credentials belong to account %s without an sts client", fakeAccountId),
+		},
+		{
+			name: "nothing is deleted when the identity can't be looked up",
+			stsClient: buildMockSTSClient(func(c *mockSTSClient) {
+				c.getCallerIdentityFn = func(input *sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
+					return nil, fmt.Errorf("expired token")
+				}
+			}),
+			wantErr: "failed to verify identity: failed to get caller identity: expired token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, err := fakeClusterManager(func(e *ClusterResourceManagerMock) error {
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := &Client{
+				ResourceManagers: []ClusterResourceManager{engine},
+				Logger:           fakeLogger,
+				ExpectedAccount:  tt.expectedAccount,
+			}
+			//a nil mock must not be stored in the interface, or the client would try to use it
+			if tt.stsClient != nil {
+				c.STSClient = tt.stsClient
+			}
+			got, err := c.DeleteResourcesForCluster(context.TODO(), fakeClusterId, map[string]string{}, false)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("DeleteResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("DeleteResourcesForCluster() unexpected error = %v", err)
+			}
+			if got != nil && !reflect.DeepEqual(got.Identity, tt.wantIdentity) {
+				t.Errorf("DeleteResourcesForCluster() identity = %+v, want %+v", got.Identity, tt.wantIdentity)
+			}
+			if deleted := len(engine.DeleteResourcesForClusterCalls()) > 0; deleted != tt.wantDeleted {
+				t.Errorf("DeleteResourcesForCluster() deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteResourcesForCluster_ManagerSettings(t *testing.T) {
+	fakeLogger, err := fakeLogger(func(l *logrus.Entry) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	type engineRun struct {
+		concurrency int
+		hasDeadline bool
+	}
+	runs := map[ResourceManagerType]*engineRun{}
+	mu := sync.Mutex{}
+	buildEngine := func(managerType ResourceManagerType) *fakeTypedConfigurableManager {
+		engine := &fakeTypedConfigurableManager{}
+		phasedEngine, err := fakePhasedClusterManager(managerType, nil, func(e *ClusterResourceManagerMock) error {
+			e.DeleteResourcesForClusterFunc = func(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
+				_, hasDeadline := ctx.Deadline()
+				mu.Lock()
+				defer mu.Unlock()
+				runs[managerType] = &engineRun{concurrency: engine.options.Concurrency, hasDeadline: hasDeadline}
+				return nil, nil
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		engine.fakePhasedManager = phasedEngine
+		return engine
+	}
+	c := &Client{
+		ResourceManagers: []ClusterResourceManager{buildEngine(managerRDS), buildEngine(managerS3)},
+		Logger:           fakeLogger,
+		Concurrency:      DefaultConcurrency,
+		ManagerSettings: map[ResourceManagerType]ManagerSettings{
+			managerRDS: {Concurrency: 2, Timeout: time.Minute},
+		},
+	}
+	if _, err := c.DeleteResourcesForCluster(context.TODO(), fakeClusterId, map[string]string{}, true); err != nil {
+		t.Fatalf("DeleteResourcesForCluster() unexpected error = %v", err)
+	}
+	want := map[ResourceManagerType]*engineRun{
+		managerRDS: {concurrency: 2, hasDeadline: true},
+		managerS3:  {concurrency: DefaultConcurrency, hasDeadline: false},
+	}
+	if !reflect.DeepEqual(runs, want) {
+		t.Errorf("DeleteResourcesForCluster() engine runs = %+v, want %+v", runs, want)
+	}
+}
+
+func TestClient_ApplyPlan(t *testing.T) {
+	fakeLogger, err := fakeLogger(func(l *logrus.Entry) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeResource := func(id string, state string) *clusterservice.Resource {
+		return &clusterservice.Resource{
+			ID:    id,
+			Name:  id,
+			State: state,
+			Tags:  map[string]string{tagKeyClusterId: fakeClusterId},
+		}
+	}
+	fakePlan := clusterservice.NewPlan(fakeClusterId, "eu-west-1", map[string]string{}, &clusterservice.Inventory{
+		Resources: []*clusterservice.Resource{
+			fakeResource("unchanged", "available"),
+			fakeResource("changed", "available"),
+			fakeResource("deleted", "available"),
+		},
+	}, time.Now())
+	tests := []struct {
+		name        string
+		listErr     error
+		want        *clusterservice.Report
+		wantDeletes int
+		wantErr     string
+	}{
+		{
+			name: "only unchanged planned resources are targeted",
+			want: &clusterservice.Report{
+				Items: []*clusterservice.ReportItem{
+					{ID: "unchanged", Name: "unchanged", Action: clusterservice.ActionDelete, ActionStatus: clusterservice.ActionStatusInProgress},
+					{ID: "changed", Name: "changed", Tags: map[string]string{tagKeyClusterId: fakeClusterId}, Action: clusterservice.ActionDelete, ActionStatus: clusterservice.ActionStatusSkipped, StatusReason: "resource changed since the plan was made"},
+					{ID: "deleted", Name: "deleted", Tags: map[string]string{tagKeyClusterId: fakeClusterId}, Action: clusterservice.ActionDelete, ActionStatus: clusterservice.ActionStatusComplete, StatusReason: "resource no longer exists"},
+				},
+			},
+			wantDeletes: 1,
+		},
+		{
+			name:    "nothing is deleted when listing fails",
+			listErr: errors.New("list error"),
+			wantErr: "failed to list resources to compare with the plan: failed to list resources with engine Fake Action Engine: list error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := &fakeConfigurableManager{}
+			mock, err := fakeClusterManager(func(e *ClusterResourceManagerMock) error {
+				e.ListResourcesForClusterFunc = func(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+					if tt.listErr != nil {
+						return nil, tt.listErr
+					}
+					//an interrupted apply leaves resources deleting, they're still the planned resources
+					changed := fakeResource("changed", "available")
+					changed.Tags["owner"] = "other"
+					return []*clusterservice.Resource{fakeResource("unchanged", "deleting"), changed, fakeResource("unplanned", "available")}, nil
+				}
+				e.DeleteResourcesForClusterFunc = func(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
+					var reportItems []*clusterservice.ReportItem
+					for _, id := range []string{"unchanged", "changed", "unplanned"} {
+						if engine.isTarget(id) {
+							reportItems = append(reportItems, &clusterservice.ReportItem{ID: id, Name: id, Action: clusterservice.ActionDelete, ActionStatus: clusterservice.ActionStatusInProgress})
+						}
+					}
+					return reportItems, nil
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			engine.ClusterResourceManagerMock = mock
+			c := &Client{
+				ResourceManagers: []ClusterResourceManager{engine},
+				Logger:           fakeLogger,
+			}
+			got, err := c.ApplyPlan(context.TODO(), fakePlan)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ApplyPlan() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("ApplyPlan() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyPlan() got = %v, want %v", got, tt.want)
+			}
+			if len(mock.DeleteResourcesForClusterCalls()) != tt.wantDeletes {
+				t.Errorf("ApplyPlan() deleted %d times, want %d", len(mock.DeleteResourcesForClusterCalls()), tt.wantDeletes)
+			}
+			if c.targets != nil {
+				t.Error("ApplyPlan() targets should be cleared once the plan is applied")
+			}
+		})
+	}
+}
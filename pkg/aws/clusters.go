@@ -0,0 +1,73 @@
+package aws
+
+import (
+	"context"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+	"github.com/integr8ly/cluster-service/pkg/errors"
+)
+
+//clusterResourceTypes Resource types of the registered managers searched for the cluster id tag when finding the clusters in an account
+func clusterResourceTypes() []string {
+	var resourceTypes []string
+	for _, manager := range RegisteredManagers() {
+		for _, resourceType := range manager.ResourceTypes {
+			resourceTypes = appendIfUnique(resourceTypes, resourceType)
+		}
+	}
+	return resourceTypes
+}
+
+//ListClusters Find every cluster id tagged on resources in the account and summarise the resources of each cluster
+//Cluster ids are found using the resource tagging api, the resources of each cluster are then listed using the resource managers
+func (c *Client) ListClusters(ctx context.Context) (*clusterservice.ClusterList, error) {
+	c.Logger.Debug("listing clusters in account")
+	clusterIds, err := c.findClusterIds(ctx)
+	if err != nil {
+		return nil, errors.WrapLog(err, "failed to find cluster ids", c.Logger)
+	}
+	c.Logger.Debugf("found %d clusters in account", len(clusterIds))
+	clusterList := &clusterservice.ClusterList{}
+	var listErrors []error
+	for _, clusterId := range clusterIds {
+		inventory, err := c.ListResourcesForCluster(ctx, clusterId, map[string]string{})
+		if err != nil {
+			//a partial inventory is only returned when continuing on error
+			if inventory == nil {
+				return nil, err
+			}
+			listErrors = append(listErrors, err)
+		}
+		clusterList.Clusters = append(clusterList.Clusters, clusterservice.NewClusterSummary(clusterId, inventory))
+	}
+	return clusterList, errors.Aggregate(listErrors)
+}
+
+//findClusterIds Get the sorted, unique values of the cluster id tag on every supported resource type
+func (c *Client) findClusterIds(ctx context.Context) ([]string, error) {
+	resourceTagMappings, err := getTaggedResources(ctx, c.TaggingClient, &resourcegroupstaggingapi.GetResourcesInput{
+		ResourceTypeFilters: aws.StringSlice(clusterResourceTypes()),
+		//a tag filter without values matches any value of the tag
+		TagFilters: []*resourcegroupstaggingapi.TagFilter{
+			{
+				Key: aws.String(tagKeyClusterId),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var clusterIds []string
+	for _, resourceTagMapping := range resourceTagMappings {
+		clusterId, ok := convertAWSTagsToMap(resourceTagMapping.Tags)[tagKeyClusterId]
+		if !ok || clusterId == "" {
+			continue
+		}
+		clusterIds = appendIfUnique(clusterIds, clusterId)
+	}
+	sort.Strings(clusterIds)
+	return clusterIds, nil
+}
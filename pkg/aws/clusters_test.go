@@ -0,0 +1,168 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+	"github.com/sirupsen/logrus"
+)
+
+func TestClient_ListClusters(t *testing.T) {
+	fakeLogger, err := fakeLogger(func(l *logrus.Entry) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeClusterTagMapping := func(clusterId string) *resourcegroupstaggingapi.ResourceTagMapping {
+		return fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
+			mapping.Tags = []*resourcegroupstaggingapi.Tag{
+				{Key: aws.String(tagKeyClusterId), Value: aws.String(clusterId)},
+			}
+		})
+	}
+	fakeTaggingClientWithClusters := func(clusterIds ...string) *taggingClientMock {
+		client, err := fakeTaggingClient(func(c *taggingClientMock) error {
+			c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+				var mappings []*resourcegroupstaggingapi.ResourceTagMapping
+				for _, clusterId := range clusterIds {
+					mappings = append(mappings, fakeClusterTagMapping(clusterId))
+				}
+				return &resourcegroupstaggingapi.GetResourcesOutput{ResourceTagMappingList: mappings}, nil
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return client
+	}
+	fakeEngine := func(listFn func(clusterId string) ([]*clusterservice.Resource, error)) ClusterResourceManager {
+		engine, err := fakeClusterManager(func(e *ClusterResourceManagerMock) error {
+			e.ListResourcesForClusterFunc = func(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+				return listFn(clusterId)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return engine
+	}
+	listOneS3Bucket := func(clusterId string) ([]*clusterservice.Resource, error) {
+		return []*clusterservice.Resource{{ID: clusterId, ResourceType: resourceTypeS3}}, nil
+	}
+
+	tests := []struct {
+		name            string
+		taggingClient   func() *taggingClientMock
+		actionEngines   []ClusterResourceManager
+		continueOnError bool
+		want            *clusterservice.ClusterList
+		wantFn          func(mock *taggingClientMock) error
+		wantErr         string
+	}{
+		{
+			name: "error when finding cluster ids fails",
+			taggingClient: func() *taggingClientMock {
+				client, err := fakeTaggingClient(func(c *taggingClientMock) error {
+					c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+						return nil, errors.New("tagging error")
+					}
+					return nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				return client
+			},
+			wantErr: "failed to find cluster ids: tagging error",
+		},
+		{
+			name: "every tagged cluster is summarised once, ordered by cluster id",
+			taggingClient: func() *taggingClientMock {
+				return fakeTaggingClientWithClusters("second", "first", "second")
+			},
+			actionEngines: []ClusterResourceManager{fakeEngine(listOneS3Bucket)},
+			want: &clusterservice.ClusterList{
+				Clusters: []*clusterservice.ClusterSummary{
+					{ClusterID: "first", ResourceCount: 1, ResourceCounts: map[string]int{resourceTypeS3: 1}},
+					{ClusterID: "second", ResourceCount: 1, ResourceCounts: map[string]int{resourceTypeS3: 1}},
+				},
+			},
+			wantFn: func(mock *taggingClientMock) error {
+				input := mock.GetResourcesWithContextCalls()[0].GetResourcesInput
+				if len(input.TagFilters) != 1 || aws.StringValue(input.TagFilters[0].Key) != tagKeyClusterId || len(input.TagFilters[0].Values) != 0 {
+					return errors.New("resources should be filtered by the cluster id tag with any value")
+				}
+				if !reflect.DeepEqual(aws.StringValueSlice(input.ResourceTypeFilters), clusterResourceTypes()) {
+					return errors.New("every supported resource type should be searched")
+				}
+				return nil
+			},
+		},
+		{
+			name: "error when listing the resources of a cluster fails",
+			taggingClient: func() *taggingClientMock {
+				return fakeTaggingClientWithClusters("first")
+			},
+			actionEngines: []ClusterResourceManager{fakeEngine(func(clusterId string) ([]*clusterservice.Resource, error) {
+				return nil, errors.New("list error")
+			})},
+			wantErr: "failed to list resources with engine Fake Action Engine: list error",
+		},
+		{
+			name: "clusters with failed engines are still summarised when continuing on error",
+			taggingClient: func() *taggingClientMock {
+				return fakeTaggingClientWithClusters("first", "second")
+			},
+			actionEngines: []ClusterResourceManager{fakeEngine(func(clusterId string) ([]*clusterservice.Resource, error) {
+				if clusterId == "first" {
+					return nil, errors.New("list error")
+				}
+				return listOneS3Bucket(clusterId)
+			})},
+			continueOnError: true,
+			want: &clusterservice.ClusterList{
+				Clusters: []*clusterservice.ClusterSummary{
+					{ClusterID: "first", ResourceCounts: map[string]int{}},
+					{ClusterID: "second", ResourceCount: 1, ResourceCounts: map[string]int{resourceTypeS3: 1}},
+				},
+			},
+			wantErr: "failed to list resources with engine Fake Action Engine: list error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := tt.taggingClient()
+			c := &Client{
+				ResourceManagers: tt.actionEngines,
+				Logger:           fakeLogger,
+				ContinueOnError:  tt.continueOnError,
+				TaggingClient:    fakeClient,
+			}
+			got, err := c.ListClusters(context.TODO())
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ListClusters() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("ListClusters() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListClusters() got = %v, want %v", got, tt.want)
+			}
+			if tt.wantFn != nil {
+				if err := tt.wantFn(fakeClient); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
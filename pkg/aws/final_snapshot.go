@@ -0,0 +1,110 @@
+package aws
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+)
+
+const (
+	//TagKeyRetainUntil Tag of final snapshots holding the time, in RFC3339, until which snapshot managers keep them
+	TagKeyRetainUntil = "integreatly.org/retain-until"
+	//DefaultFinalSnapshotRetention How long final snapshots are kept by default
+	DefaultFinalSnapshotRetention = 30 * 24 * time.Hour
+
+	//finalSnapshotTimeFormat Format of the creation time of a resource in the identifier of its final snapshot, identifiers only allow letters, digits and hyphens
+	finalSnapshotTimeFormat = "20060102150405"
+)
+
+//FinalSnapshotPolicy Take a snapshot of data stores before deleting them, tagged so they're kept until the retention elapses
+type FinalSnapshotPolicy struct {
+	//Retention How long final snapshots are kept before a later cleanup may delete them
+	Retention time.Duration
+	//now Current time, overridden in tests
+	now func() time.Time
+}
+
+//NewDefaultFinalSnapshotPolicy Build a final snapshot policy keeping snapshots for the default retention
+func NewDefaultFinalSnapshotPolicy() *FinalSnapshotPolicy {
+	return &FinalSnapshotPolicy{
+		Retention: DefaultFinalSnapshotRetention,
+	}
+}
+
+//RetainUntil Value of the retain until tag of a final snapshot taken now
+func (p *FinalSnapshotPolicy) RetainUntil() string {
+	now := time.Now
+	if p.now != nil {
+		now = p.now
+	}
+	return now().UTC().Add(p.Retention).Format(time.RFC3339)
+}
+
+//finalSnapshotIdentifier Identifier of the final snapshot of a resource, the creation time keeps it unique when a resource of the same name is recreated
+//The identifier is the same on every run, so a snapshot started by an earlier run is found again
+func finalSnapshotIdentifier(resourceId string, creationTime *time.Time) string {
+	if creationTime == nil {
+		return fmt.Sprintf("%s-final", resourceId)
+	}
+	return fmt.Sprintf("%s-final-%s", resourceId, creationTime.UTC().Format(finalSnapshotTimeFormat))
+}
+
+//finalSnapshotRDSTags Tags of the final snapshot of an rds resource, the tags of the resource so later runs find the snapshot along with the retain until tag
+func (p *FinalSnapshotPolicy) finalSnapshotRDSTags(resourceTags map[string]string) []*rds.Tag {
+	tags := []*rds.Tag{
+		{
+			Key:   aws.String(TagKeyRetainUntil),
+			Value: aws.String(p.RetainUntil()),
+		},
+	}
+	for key, value := range resourceTags {
+		if key == TagKeyRetainUntil {
+			continue
+		}
+		tags = append(tags, &rds.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return tags
+}
+
+//finalSnapshotElasticacheTags Tags of the final snapshot of an elasticache replication group, see finalSnapshotRDSTags
+//The snapshot is tagged by a later run once it exists, so the retain until time is the one recorded when the deletion started
+func finalSnapshotElasticacheTags(retainUntil string, resourceTags map[string]string) []*elasticache.Tag {
+	tags := []*elasticache.Tag{
+		{
+			Key:   aws.String(TagKeyRetainUntil),
+			Value: aws.String(retainUntil),
+		},
+	}
+	for key, value := range resourceTags {
+		if key == TagKeyRetainUntil {
+			continue
+		}
+		tags = append(tags, &elasticache.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return tags
+}
+
+//isRetained Whether the retain until tag of the resource of a report item is still in the future, the item is then marked retained
+//A retain until tag which can't be parsed retains the resource, so a typo never causes a snapshot to be lost
+func (c *configurable) isRetained(reportItem *clusterservice.ReportItem) bool {
+	value, ok := reportItem.Tags[TagKeyRetainUntil]
+	if !ok {
+		return false
+	}
+	retainUntil, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		reportItem.ActionStatus = clusterservice.ActionStatusRetained
+		reportItem.StatusReason = fmt.Sprintf("invalid %s tag %s", TagKeyRetainUntil, value)
+		return true
+	}
+	if time.Now().Before(retainUntil) {
+		reportItem.ActionStatus = clusterservice.ActionStatusRetained
+		reportItem.StatusReason = fmt.Sprintf("retained until %s", value)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+)
+
+func TestFinalSnapshotPolicy_RetainUntil(t *testing.T) {
+	now := time.Date(2021, 3, 5, 12, 0, 0, 0, time.UTC)
+	policy := &FinalSnapshotPolicy{Retention: 7 * 24 * time.Hour, now: func() time.Time { return now }}
+	if got := policy.RetainUntil(); got != "2021-03-12T12:00:00Z" {
+		t.Errorf("RetainUntil() = %s, want 2021-03-12T12:00:00Z", got)
+	}
+	tags := policy.finalSnapshotRDSTags(map[string]string{tagKeyClusterId: "test", TagKeyRetainUntil: "2020-01-01T00:00:00Z"})
+	if len(tags) != 2 || aws.StringValue(tags[0].Key) != TagKeyRetainUntil || aws.StringValue(tags[0].Value) != "2021-03-12T12:00:00Z" {
+		t.Errorf("finalSnapshotRDSTags() = %v, want the cluster tag and a single retain until tag", tags)
+	}
+}
+
+func Test_finalSnapshotIdentifier(t *testing.T) {
+	creationTime := time.Date(2021, 3, 5, 12, 30, 15, 0, time.UTC)
+	if got := finalSnapshotIdentifier("test-db", &creationTime); got != "test-db-final-20210305123015" {
+		t.Errorf("finalSnapshotIdentifier() = %s, want test-db-final-20210305123015", got)
+	}
+	if got := finalSnapshotIdentifier("test-db", nil); got != "test-db-final" {
+		t.Errorf("finalSnapshotIdentifier() = %s, want test-db-final", got)
+	}
+}
+
+func TestConfigurable_isRetained(t *testing.T) {
+	tests := []struct {
+		name         string
+		tags         map[string]string
+		wantRetained bool
+		wantReason   string
+	}{
+		{
+			name: "resources without the retain until tag are not retained",
+			tags: map[string]string{tagKeyClusterId: "test"},
+		},
+		{
+			name:         "resources are retained until the time of the tag",
+			tags:         map[string]string{TagKeyRetainUntil: "2999-01-01T00:00:00Z"},
+			wantRetained: true,
+			wantReason:   "retained until 2999-01-01T00:00:00Z",
+		},
+		{
+			name: "resources are not retained once the time of the tag has passed",
+			tags: map[string]string{TagKeyRetainUntil: "2001-01-01T00:00:00Z"},
+		},
+		{
+			name:         "resources with an invalid retain until tag are retained",
+			tags:         map[string]string{TagKeyRetainUntil: "next week"},
+			wantRetained: true,
+			wantReason:   "invalid integreatly.org/retain-until tag next week",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := &clusterservice.ReportItem{Tags: tt.tags, ActionStatus: clusterservice.ActionStatusInProgress}
+			c := &configurable{}
+			if got := c.isRetained(item); got != tt.wantRetained {
+				t.Fatalf("isRetained() = %v, want %v", got, tt.wantRetained)
+			}
+			if tt.wantRetained && (item.ActionStatus != clusterservice.ActionStatusRetained || item.StatusReason != tt.wantReason) {
+				t.Errorf("isRetained() item = %s %s, want retained %s", item.ActionStatus, item.StatusReason, tt.wantReason)
+			}
+		})
+	}
+}
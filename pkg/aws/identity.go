@@ -0,0 +1,37 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+)
+
+//VerifyIdentity Look up the account and principal of the credentials, failing when they belong to an account other than the expected one
+//The identity is only looked up once, later calls return the same identity
+func (c *Client) VerifyIdentity(ctx context.Context) (*clusterservice.CallerIdentity, error) {
+	if c.identity != nil {
+		return c.identity, nil
+	}
+	if c.STSClient == nil {
+		if c.ExpectedAccount != "" {
+			return nil, fmt.Errorf("cannot verify the credentials belong to account %s without an sts client", c.ExpectedAccount)
+		}
+		return nil, nil
+	}
+	output, err := c.STSClient.GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get caller identity: %w", err)
+	}
+	identity := &clusterservice.CallerIdentity{
+		Account: aws.StringValue(output.Account),
+		ARN:     aws.StringValue(output.Arn),
+	}
+	if c.ExpectedAccount != "" && identity.Account != c.ExpectedAccount {
+		return nil, fmt.Errorf("credentials of %s belong to account %s, expected account %s", identity.ARN, identity.Account, c.ExpectedAccount)
+	}
+	c.identity = identity
+	return identity, nil
+}
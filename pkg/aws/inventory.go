@@ -0,0 +1,33 @@
+package aws
+
+import (
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+)
+
+const (
+	//bytesPerGiB Size of the gibibytes rds uses to report allocated storage
+	bytesPerGiB int64 = 1024 * 1024 * 1024
+)
+
+//newInventoryResource Build an inventory entry for a resource found using the resource tagging api
+//Fields the tagging api doesn't provide, like state or creation time, are left for the caller to set
+func newInventoryResource(resource *basicResource, resourceType string, region string, manager ResourceManagerType) *clusterservice.Resource {
+	return &clusterservice.Resource{
+		ID:           resource.ARN,
+		Name:         resource.Name,
+		ResourceType: resourceType,
+		Region:       region,
+		Account:      accountFromARN(resource.ARN),
+		Manager:      string(manager),
+		Tags:         resource.Tags,
+	}
+}
+
+//gibToBytes Convert a size in gibibytes as reported by rds to bytes
+func gibToBytes(sizeGiB *int64) *int64 {
+	if sizeGiB == nil {
+		return nil
+	}
+	sizeBytes := *sizeGiB * bytesPerGiB
+	return &sizeBytes
+}
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -21,12 +22,15 @@ const (
 	resourceTypeRouteTable = "ec2:route-table"
 )
 
-var _ ClusterResourceManager = &RouteTableManager{}
+var _ PhasedClusterResourceManager = &RouteTableManager{}
+var _ ConfigurableClusterResourceManager = &RouteTableManager{}
 
 type RouteTableManager struct {
+	configurable
 	ec2Client     ec2Client
 	taggingClient taggingClient
 	logger        *logrus.Entry
+	region        string
 }
 
 func NewDefaultRouteTableManager(session *session.Session, logger *logrus.Entry) *RouteTableManager {
@@ -34,6 +38,7 @@ func NewDefaultRouteTableManager(session *session.Session, logger *logrus.Entry)
 		ec2Client:     ec2.New(session),
 		taggingClient: resourcegroupstaggingapi.New(session),
 		logger:        logger.WithField(loggingKeyManager, managerRouteTable),
+		region:        regionFromSession(session),
 	}
 }
 
@@ -41,65 +46,116 @@ func (r *RouteTableManager) GetName() string {
 	return "AWS EC2 RouteTable Manager"
 }
 
-func (r *RouteTableManager) DeleteResourcesForCluster(clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
+func (r *RouteTableManager) GetType() ResourceManagerType {
+	return managerRouteTable
+}
+
+func (r *RouteTableManager) GetDependencies() []ResourceManagerType {
+	return []ResourceManagerType{managerSubnet}
+}
+
+func (r *RouteTableManager) DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
 	r.logger.Debug("delete route table resources for cluster")
-	resourceInput := &resourcegroupstaggingapi.GetResourcesInput{
-		ResourceTypeFilters: aws.StringSlice([]string{resourceTypeRouteTable}),
-		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
-	}
-	resourceOutput, err := r.taggingClient.GetResources(resourceInput)
+	routeTablesToDelete, err := r.getRouteTablesForCluster(ctx, clusterId, tags)
 	if err != nil {
-		return nil, errors.WrapLog(err, "failed to filter route tables", r.logger)
-	}
-	var routeTablesToDelete []*basicResource
-	for _, resourceTagMapping := range resourceOutput.ResourceTagMappingList {
-		arn := aws.StringValue(resourceTagMapping.ResourceARN)
-		arnElements := strings.Split(arn, "/")
-		routeTableId := arnElements[len(arnElements)-1]
-		if routeTableId == "" {
-			return nil, errors.WrapLog(err, fmt.Sprintf("invalid route table name from arn, %s", routeTableId), r.logger)
-		}
-		routeTablesToDelete = append(routeTablesToDelete, &basicResource{
-			Name: routeTableId,
-			ARN:  arn,
-		})
+		return nil, err
 	}
-	r.logger.Debugf("found list of %d route tables to delete", len(routeTablesToDelete))
 	//delete resources
 	var reportItems []*clusterservice.ReportItem
+	deletePool := newWorkerPool(r.options.Concurrency)
 	for _, routeTable := range routeTablesToDelete {
+		routeTable := routeTable
 		routeTableLogger := r.logger.WithField(loggingKeyRouteTable, routeTable.Name)
+		if !r.isTarget(routeTable.ARN) {
+			routeTableLogger.Debug("resource is not targeted, skipping")
+			continue
+		}
 		reportItem := &clusterservice.ReportItem{
 			ID:           routeTable.ARN,
 			Name:         routeTable.Name,
+			ResourceType: resourceTypeRouteTable,
+			Region:       r.region,
+			Account:      accountFromARN(routeTable.ARN),
+			Manager:      string(managerRouteTable),
+			Tags:         routeTable.Tags,
 			Action:       clusterservice.ActionDelete,
 			ActionStatus: clusterservice.ActionStatusInProgress,
 		}
 		reportItems = append(reportItems, reportItem)
+		if r.isProtectedWithoutCreationTime(reportItem) {
+			routeTableLogger.Debug("resource is protected by the safety policy, skipping")
+			continue
+		}
 		if dryRun {
 			routeTableLogger.Debugf("dry run is enabled, skipping deletion")
 			reportItem.ActionStatus = clusterservice.ActionStatusDryRun
 			continue
 		}
-		routeTableLogger.Debugf("performing route table deletion")
-		deleteRouteTableInput := &ec2.DeleteRouteTableInput{
-			RouteTableId: aws.String(routeTable.Name),
-		}
-		if _, err := r.ec2Client.DeleteRouteTable(deleteRouteTableInput); err != nil {
-			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "DependencyViolation" {
-				routeTableLogger.Debug("route table has existing dependencies which have not been deleted, skipping")
-				reportItem.ActionStatus = clusterservice.ActionStatusSkipped
-				continue
+		deletePool.Go(func() error {
+			routeTableLogger.Debugf("performing route table deletion")
+			deleteRouteTableInput := &ec2.DeleteRouteTableInput{
+				RouteTableId: aws.String(routeTable.Name),
 			}
+			if _, err := r.ec2Client.DeleteRouteTableWithContext(ctx, deleteRouteTableInput); err != nil {
+				if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "DependencyViolation" {
+					routeTableLogger.Debug("route table has existing dependencies which have not been deleted, skipping")
+					reportItem.ActionStatus = clusterservice.ActionStatusSkipped
+					reportItem.StatusReason = "resource has dependencies which have not been deleted"
+					return nil
+				}
 
-			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "InvalidRouteTableID.NotFound" {
-				routeTableLogger.Debug("route does not exist, assuming deleted")
-				reportItem.ActionStatus = clusterservice.ActionStatusComplete
-				continue
+				if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "InvalidRouteTableID.NotFound" {
+					routeTableLogger.Debug("route does not exist, assuming deleted")
+					reportItem.ActionStatus = clusterservice.ActionStatusComplete
+					return nil
+				}
+				return failReportItem(reportItem, errors.WrapLog(err, "failed to delete route table", r.logger))
 			}
-			return nil, errors.WrapLog(err, "failed to delete route table", r.logger)
+			reportItem.ActionStatus = clusterservice.ActionStatusComplete
+			return nil
+		})
+	}
+	return reportItems, errors.Aggregate(deletePool.Wait())
+}
+
+//ListResourcesForCluster List route table resources for cluster without modifying them
+func (r *RouteTableManager) ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+	r.logger.Debug("list route table resources for cluster")
+	routeTables, err := r.getRouteTablesForCluster(ctx, clusterId, tags)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*clusterservice.Resource, 0, len(routeTables))
+	for _, routeTable := range routeTables {
+		resources = append(resources, newInventoryResource(routeTable, resourceTypeRouteTable, r.region, managerRouteTable))
+	}
+	return resources, nil
+}
+
+//getRouteTablesForCluster Get the route tables tagged for a cluster using the resource tagging api
+func (r *RouteTableManager) getRouteTablesForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*basicResource, error) {
+	resourceInput := &resourcegroupstaggingapi.GetResourcesInput{
+		ResourceTypeFilters: aws.StringSlice([]string{resourceTypeRouteTable}),
+		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
+	}
+	resourceTagMappings, err := getTaggedResources(ctx, r.taggingClient, resourceInput)
+	if err != nil {
+		return nil, errors.WrapLog(err, "failed to filter route tables", r.logger)
+	}
+	var routeTables []*basicResource
+	for _, resourceTagMapping := range resourceTagMappings {
+		arn := aws.StringValue(resourceTagMapping.ResourceARN)
+		arnElements := strings.Split(arn, "/")
+		routeTableId := arnElements[len(arnElements)-1]
+		if routeTableId == "" {
+			return nil, errors.WrapLog(err, fmt.Sprintf("invalid route table name from arn, %s", routeTableId), r.logger)
 		}
-		reportItem.ActionStatus = clusterservice.ActionStatusComplete
+		routeTables = append(routeTables, &basicResource{
+			Name: routeTableId,
+			ARN:  arn,
+			Tags: convertAWSTagsToMap(resourceTagMapping.Tags),
+		})
 	}
-	return reportItems, nil
+	r.logger.Debugf("found list of %d route tables for cluster", len(routeTables))
+	return routeTables, nil
 }
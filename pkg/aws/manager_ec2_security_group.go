@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -18,16 +19,19 @@ import (
 const (
 	loggingKeySecurityGroup = "security-group-id"
 
-	resourceTypeSecurtyGroup = "ec2:security-group"
+	resourceTypeSecurityGroup = "ec2:security-group"
 )
 
-var _ ClusterResourceManager = &SecurityGroupManager{}
+var _ PhasedClusterResourceManager = &SecurityGroupManager{}
+var _ ConfigurableClusterResourceManager = &SecurityGroupManager{}
 
 // SecurityGroupManager type
 type SecurityGroupManager struct {
+	configurable
 	ec2Client     ec2Client
 	taggingClient taggingClient
 	logger        *logrus.Entry
+	region        string
 }
 
 // NewDefaultSecurityGroupManager create session for manager
@@ -36,6 +40,7 @@ func NewDefaultSecurityGroupManager(session *session.Session, logger *logrus.Ent
 		ec2Client:     ec2.New(session),
 		taggingClient: resourcegroupstaggingapi.New(session),
 		logger:        logger.WithField(loggingKeyManager, managerSecurityGroup),
+		region:        regionFromSession(session),
 	}
 }
 
@@ -44,63 +49,114 @@ func (r *SecurityGroupManager) GetName() string {
 	return "AWS EC2 SecurityGroup Manager"
 }
 
+func (r *SecurityGroupManager) GetType() ResourceManagerType {
+	return managerSecurityGroup
+}
+
+func (r *SecurityGroupManager) GetDependencies() []ResourceManagerType {
+	return []ResourceManagerType{managerRDS, managerRDSCluster, managerElasticache}
+}
+
 // DeleteResourcesForCluster deletes resource for cluster
-func (r *SecurityGroupManager) DeleteResourcesForCluster(clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
-	var securityGroupsToDelete []*basicResource
+func (r *SecurityGroupManager) DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
 	r.logger.Debug("delete security groups resources for cluster")
-	//  integreatly.org/clusterID tags
-	resourceInput := &resourcegroupstaggingapi.GetResourcesInput{
-		ResourceTypeFilters: aws.StringSlice([]string{resourceTypeSecurtyGroup}),
-		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
-	}
-	// add to the security group delete array
-	resourceOutput, err := r.taggingClient.GetResources(resourceInput)
+	securityGroupsToDelete, err := r.getSecurityGroupsForCluster(ctx, clusterId, tags)
 	if err != nil {
-		return nil, errors.WrapLog(err, "failed to filter security groups", r.logger)
-	}
-
-	for _, resourceTagMapping := range resourceOutput.ResourceTagMappingList {
-		arn := aws.StringValue(resourceTagMapping.ResourceARN)
-		arnElements := strings.Split(arn, "/")
-		securityGroupID := arnElements[len(arnElements)-1]
-		if securityGroupID == "" {
-			return nil, errors.WrapLog(err, fmt.Sprintf("invalid security groups name from arn, %s", securityGroupID), r.logger)
-		}
-		securityGroupsToDelete = append(securityGroupsToDelete, &basicResource{
-			Name: securityGroupID,
-			ARN:  arn,
-		})
-		r.logger.Debugf("found list of %d security groups to delete", len(securityGroupsToDelete))
+		return nil, err
 	}
 	//delete resources
 	var reportItems []*clusterservice.ReportItem
+	deletePool := newWorkerPool(r.options.Concurrency)
 	for _, securityGroup := range securityGroupsToDelete {
+		securityGroup := securityGroup
 		securityGroupLogger := r.logger.WithField(loggingKeySecurityGroup, securityGroup.Name)
+		if !r.isTarget(securityGroup.ARN) {
+			securityGroupLogger.Debug("resource is not targeted, skipping")
+			continue
+		}
 		reportItem := &clusterservice.ReportItem{
 			ID:           securityGroup.ARN,
 			Name:         securityGroup.Name,
+			ResourceType: resourceTypeSecurityGroup,
+			Region:       r.region,
+			Account:      accountFromARN(securityGroup.ARN),
+			Manager:      string(managerSecurityGroup),
+			Tags:         securityGroup.Tags,
 			Action:       clusterservice.ActionDelete,
 			ActionStatus: clusterservice.ActionStatusInProgress,
 		}
 		reportItems = append(reportItems, reportItem)
+		if r.isProtectedWithoutCreationTime(reportItem) {
+			securityGroupLogger.Debug("resource is protected by the safety policy, skipping")
+			continue
+		}
 		if dryRun {
 			securityGroupLogger.Debugf("dry run is enabled, skipping deletion")
 			reportItem.ActionStatus = clusterservice.ActionStatusDryRun
 			continue
 		}
-		securityGroupLogger.Debugf("performing security group deletion")
-		deleteSecurityGroupInput := &ec2.DeleteSecurityGroupInput{
-			GroupId: aws.String(securityGroup.Name),
-		}
-		if _, err := r.ec2Client.DeleteSecurityGroup(deleteSecurityGroupInput); err != nil {
-			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "DependencyViolation" {
-				securityGroupLogger.Debug("security group has existing dependencies which have not been deleted, skipping")
-				reportItem.ActionStatus = clusterservice.ActionStatusSkipped
-				continue
+		deletePool.Go(func() error {
+			securityGroupLogger.Debugf("performing security group deletion")
+			deleteSecurityGroupInput := &ec2.DeleteSecurityGroupInput{
+				GroupId: aws.String(securityGroup.Name),
+			}
+			if _, err := r.ec2Client.DeleteSecurityGroupWithContext(ctx, deleteSecurityGroupInput); err != nil {
+				if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "DependencyViolation" {
+					securityGroupLogger.Debug("security group has existing dependencies which have not been deleted, skipping")
+					reportItem.ActionStatus = clusterservice.ActionStatusSkipped
+					reportItem.StatusReason = "resource has dependencies which have not been deleted"
+					return nil
+				}
+				return failReportItem(reportItem, errors.WrapLog(err, "failed to delete security group", r.logger))
 			}
-			return nil, errors.WrapLog(err, "failed to delete security group", r.logger)
+			reportItem.ActionStatus = clusterservice.ActionStatusComplete
+			return nil
+		})
+	}
+	return reportItems, errors.Aggregate(deletePool.Wait())
+}
+
+//ListResourcesForCluster List security group resources for cluster without modifying them
+func (r *SecurityGroupManager) ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+	r.logger.Debug("list security group resources for cluster")
+	securityGroups, err := r.getSecurityGroupsForCluster(ctx, clusterId, tags)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*clusterservice.Resource, 0, len(securityGroups))
+	for _, securityGroup := range securityGroups {
+		resources = append(resources, newInventoryResource(securityGroup, resourceTypeSecurityGroup, r.region, managerSecurityGroup))
+	}
+	return resources, nil
+}
+
+//getSecurityGroupsForCluster Get the security groups tagged for a cluster using the resource tagging api
+func (r *SecurityGroupManager) getSecurityGroupsForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*basicResource, error) {
+	var securityGroups []*basicResource
+	//  integreatly.org/clusterID tags
+	resourceInput := &resourcegroupstaggingapi.GetResourcesInput{
+		ResourceTypeFilters: aws.StringSlice([]string{resourceTypeSecurityGroup}),
+		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
+	}
+	// add to the security group delete array
+	resourceTagMappings, err := getTaggedResources(ctx, r.taggingClient, resourceInput)
+	if err != nil {
+		return nil, errors.WrapLog(err, "failed to filter security groups", r.logger)
+	}
+
+	for _, resourceTagMapping := range resourceTagMappings {
+		arn := aws.StringValue(resourceTagMapping.ResourceARN)
+		arnElements := strings.Split(arn, "/")
+		securityGroupID := arnElements[len(arnElements)-1]
+		if securityGroupID == "" {
+			return nil, errors.WrapLog(err, fmt.Sprintf("invalid security groups name from arn, %s", securityGroupID), r.logger)
 		}
-		reportItem.ActionStatus = clusterservice.ActionStatusComplete
+		securityGroups = append(securityGroups, &basicResource{
+			Name: securityGroupID,
+			ARN:  arn,
+			Tags: convertAWSTagsToMap(resourceTagMapping.Tags),
+		})
+		r.logger.Debugf("found list of %d security groups for cluster", len(securityGroups))
 	}
-	return reportItems, nil
+	return securityGroups, nil
 }
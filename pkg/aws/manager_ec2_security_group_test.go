@@ -1,8 +1,10 @@
 package aws
 
 import (
+	"context"
 	"errors"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"reflect"
 	"testing"
 
@@ -49,7 +51,7 @@ func TestSecurityGroupManager_DeleteResourcesForCluster(t *testing.T) {
 				}),
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
 							return nil, errors.New("some tagging error")
 						}
 						return nil
@@ -78,7 +80,7 @@ func TestSecurityGroupManager_DeleteResourcesForCluster(t *testing.T) {
 				}),
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
 							return &resourcegroupstaggingapi.GetResourcesOutput{
 								ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
 									fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
@@ -101,6 +103,18 @@ func TestSecurityGroupManager_DeleteResourcesForCluster(t *testing.T) {
 				tags:      map[string]string{},
 				dryRun:    false,
 			},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeEc2ClientInstanceArn
+					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeSecurityGroup
+					item.Manager = string(managerSecurityGroup)
+					item.Tags = fakeResourceTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusFailed
+					item.StatusReason = "failed to delete security group: some error deleting security group"
+				}),
+			},
 			wantErr: "failed to delete security group: some error deleting security group",
 		},
 		{
@@ -113,7 +127,7 @@ func TestSecurityGroupManager_DeleteResourcesForCluster(t *testing.T) {
 				}),
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
 							return &resourcegroupstaggingapi.GetResourcesOutput{
 								ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
 									fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
@@ -140,6 +154,9 @@ func TestSecurityGroupManager_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeEc2ClientInstanceArn
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeSecurityGroup
+					item.Manager = string(managerSecurityGroup)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusComplete
 				}),
@@ -155,7 +172,7 @@ func TestSecurityGroupManager_DeleteResourcesForCluster(t *testing.T) {
 				}),
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
 							return &resourcegroupstaggingapi.GetResourcesOutput{
 								ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
 									fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
@@ -182,6 +199,9 @@ func TestSecurityGroupManager_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeEc2ClientInstanceArn
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeSecurityGroup
+					item.Manager = string(managerSecurityGroup)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusDryRun
 				}),
@@ -195,7 +215,7 @@ func TestSecurityGroupManager_DeleteResourcesForCluster(t *testing.T) {
 				taggingClient: tt.fields.taggingClient(),
 				logger:        tt.fields.logger,
 			}
-			got, err := r.DeleteResourcesForCluster(tt.args.clusterId, tt.args.tags, tt.args.dryRun)
+			got, err := r.DeleteResourcesForCluster(context.TODO(), tt.args.clusterId, tt.args.tags, tt.args.dryRun)
 			if tt.wantErr != "" && err.Error() != tt.wantErr {
 				t.Errorf("DeleteResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
 				return
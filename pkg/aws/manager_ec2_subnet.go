@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -21,12 +22,15 @@ const (
 	resourceTypeSubnet = "ec2:subnet"
 )
 
-var _ ClusterResourceManager = &SubnetManager{}
+var _ PhasedClusterResourceManager = &SubnetManager{}
+var _ ConfigurableClusterResourceManager = &SubnetManager{}
 
 type SubnetManager struct {
+	configurable
 	ec2Client     ec2Client
 	taggingClient taggingClient
 	logger        *logrus.Entry
+	region        string
 }
 
 func NewDefaultSubnetManager(session *session.Session, logger *logrus.Entry) *SubnetManager {
@@ -34,6 +38,7 @@ func NewDefaultSubnetManager(session *session.Session, logger *logrus.Entry) *Su
 		ec2Client:     ec2.New(session),
 		taggingClient: resourcegroupstaggingapi.New(session),
 		logger:        logger.WithField(loggingKeyManager, managerSubnet),
+		region:        regionFromSession(session),
 	}
 }
 
@@ -41,60 +46,111 @@ func (r *SubnetManager) GetName() string {
 	return "AWS EC2 Subnet Manager"
 }
 
-func (s *SubnetManager) DeleteResourcesForCluster(clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
+func (r *SubnetManager) GetType() ResourceManagerType {
+	return managerSubnet
+}
+
+func (r *SubnetManager) GetDependencies() []ResourceManagerType {
+	return []ResourceManagerType{managerRDS, managerRDSCluster, managerRDSSubnetGroup, managerElasticache}
+}
+
+func (s *SubnetManager) DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
 	s.logger.Debug("delete subnet resources for cluster")
-	resourceInput := &resourcegroupstaggingapi.GetResourcesInput{
-		ResourceTypeFilters: aws.StringSlice([]string{resourceTypeSubnet}),
-		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
-	}
-	resourceOutput, err := s.taggingClient.GetResources(resourceInput)
+	subnetsToDelete, err := s.getSubnetsForCluster(ctx, clusterId, tags)
 	if err != nil {
-		return nil, errors.WrapLog(err, "failed to filter subnets", s.logger)
-	}
-	var subnetsToDelete []*basicResource
-	for _, resourceTagMapping := range resourceOutput.ResourceTagMappingList {
-		arn := aws.StringValue(resourceTagMapping.ResourceARN)
-		arnElements := strings.Split(arn, "/")
-		subnetId := arnElements[len(arnElements)-1]
-		if subnetId == "" {
-			return nil, errors.WrapLog(err, fmt.Sprintf("invalid subnet name from arn, %s", subnetId), s.logger)
-		}
-		subnetsToDelete = append(subnetsToDelete, &basicResource{
-			Name: subnetId,
-			ARN:  arn,
-		})
+		return nil, err
 	}
-	s.logger.Debugf("found list of %d subnets to delete", len(subnetsToDelete))
 	//delete resources
 	var reportItems []*clusterservice.ReportItem
+	deletePool := newWorkerPool(s.options.Concurrency)
 	for _, subnet := range subnetsToDelete {
+		subnet := subnet
 		subnetLogger := s.logger.WithField(loggingKeySubnet, subnet.Name)
+		if !s.isTarget(subnet.ARN) {
+			subnetLogger.Debug("resource is not targeted, skipping")
+			continue
+		}
 		reportItem := &clusterservice.ReportItem{
 			ID:           subnet.ARN,
 			Name:         subnet.Name,
+			ResourceType: resourceTypeSubnet,
+			Region:       s.region,
+			Account:      accountFromARN(subnet.ARN),
+			Manager:      string(managerSubnet),
+			Tags:         subnet.Tags,
 			Action:       clusterservice.ActionDelete,
 			ActionStatus: clusterservice.ActionStatusInProgress,
 		}
 		reportItems = append(reportItems, reportItem)
+		if s.isProtectedWithoutCreationTime(reportItem) {
+			subnetLogger.Debug("resource is protected by the safety policy, skipping")
+			continue
+		}
 		if dryRun {
 			subnetLogger.Debugf("dry run is enabled, skipping deletion")
 			reportItem.ActionStatus = clusterservice.ActionStatusDryRun
 			continue
 		}
-		subnetLogger.Debugf("performing subnet deletion")
-		deleteSubnetInput := &ec2.DeleteSubnetInput{
-			SubnetId: aws.String(subnet.Name),
-		}
-		if _, err := s.ec2Client.DeleteSubnet(deleteSubnetInput); err != nil {
-			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "DependencyViolation" {
-				subnetLogger.Debug("subnet has existing dependencies which have not been deleted, skipping")
-				reportItem.ActionStatus = clusterservice.ActionStatusSkipped
-				continue
+		deletePool.Go(func() error {
+			subnetLogger.Debugf("performing subnet deletion")
+			deleteSubnetInput := &ec2.DeleteSubnetInput{
+				SubnetId: aws.String(subnet.Name),
 			}
-			return nil, errors.WrapLog(err, "failed to delete subnet", s.logger)
-		}
-		reportItem.ActionStatus = clusterservice.ActionStatusComplete
+			if _, err := s.ec2Client.DeleteSubnetWithContext(ctx, deleteSubnetInput); err != nil {
+				if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "DependencyViolation" {
+					subnetLogger.Debug("subnet has existing dependencies which have not been deleted, skipping")
+					reportItem.ActionStatus = clusterservice.ActionStatusSkipped
+					reportItem.StatusReason = "resource has dependencies which have not been deleted"
+					return nil
+				}
+				return failReportItem(reportItem, errors.WrapLog(err, "failed to delete subnet", s.logger))
+			}
+			reportItem.ActionStatus = clusterservice.ActionStatusComplete
+			return nil
+		})
 	}
 
-	return reportItems, nil
+	return reportItems, errors.Aggregate(deletePool.Wait())
+}
+
+//ListResourcesForCluster List subnet resources for cluster without modifying them
+func (s *SubnetManager) ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+	s.logger.Debug("list subnet resources for cluster")
+	subnets, err := s.getSubnetsForCluster(ctx, clusterId, tags)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*clusterservice.Resource, 0, len(subnets))
+	for _, subnet := range subnets {
+		resources = append(resources, newInventoryResource(subnet, resourceTypeSubnet, s.region, managerSubnet))
+	}
+	return resources, nil
+}
+
+//getSubnetsForCluster Get the subnets tagged for a cluster using the resource tagging api
+func (s *SubnetManager) getSubnetsForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*basicResource, error) {
+	resourceInput := &resourcegroupstaggingapi.GetResourcesInput{
+		ResourceTypeFilters: aws.StringSlice([]string{resourceTypeSubnet}),
+		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
+	}
+	resourceTagMappings, err := getTaggedResources(ctx, s.taggingClient, resourceInput)
+	if err != nil {
+		return nil, errors.WrapLog(err, "failed to filter subnets", s.logger)
+	}
+	var subnets []*basicResource
+	for _, resourceTagMapping := range resourceTagMappings {
+		arn := aws.StringValue(resourceTagMapping.ResourceARN)
+		arnElements := strings.Split(arn, "/")
+		subnetId := arnElements[len(arnElements)-1]
+		if subnetId == "" {
+			return nil, errors.WrapLog(err, fmt.Sprintf("invalid subnet name from arn, %s", subnetId), s.logger)
+		}
+		subnets = append(subnets, &basicResource{
+			Name: subnetId,
+			ARN:  arn,
+			Tags: convertAWSTagsToMap(resourceTagMapping.Tags),
+		})
+	}
+	s.logger.Debugf("found list of %d subnets for cluster", len(subnets))
+	return subnets, nil
 }
@@ -1,9 +1,12 @@
 package aws
 
 import (
+	"context"
 	"errors"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -26,6 +29,7 @@ func TestSubnetManager_DeleteResourcesForCluster(t *testing.T) {
 		taggingClient func() *taggingClientMock
 		logger        *logrus.Entry
 		Ec2Api        ec2iface.EC2API
+		concurrency   int
 	}
 	type args struct {
 		clusterId string
@@ -49,7 +53,7 @@ func TestSubnetManager_DeleteResourcesForCluster(t *testing.T) {
 				}),
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
 							return nil, errors.New("")
 						}
 						return nil
@@ -78,7 +82,7 @@ func TestSubnetManager_DeleteResourcesForCluster(t *testing.T) {
 				}),
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
 							return &resourcegroupstaggingapi.GetResourcesOutput{
 								ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
 									fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
@@ -101,8 +105,143 @@ func TestSubnetManager_DeleteResourcesForCluster(t *testing.T) {
 				tags:      map[string]string{},
 				dryRun:    false,
 			},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeEc2ClientInstanceArn
+					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeSubnet
+					item.Manager = string(managerSubnet)
+					item.Tags = fakeResourceTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusFailed
+					item.StatusReason = "failed to delete subnet: some error deleting subnet"
+				}),
+			},
+			wantErr: "failed to delete subnet: some error deleting subnet",
+		},
+		{
+			name: "remaining subnets are deleted when a subnet deletion returns an error",
+			fields: fields{
+				Ec2Api: buildMockEc2Client(func(ec2Client *mockEc2Client) {
+					ec2Client.deleteSubnetFn = func(input *ec2.DeleteSubnetInput) (*ec2.DeleteSubnetOutput, error) {
+						if aws.StringValue(input.SubnetId) == fakeResourceIdentifier {
+							return nil, errors.New("some error deleting subnet")
+						}
+						return &ec2.DeleteSubnetOutput{}, nil
+					}
+				}),
+				taggingClient: func() *taggingClientMock {
+					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+							return &resourcegroupstaggingapi.GetResourcesOutput{
+								ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
+									fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
+										mapping.ResourceARN = aws.String(fakeEc2ClientInstanceArn)
+									}),
+									fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
+										mapping.ResourceARN = aws.String("arn:fake:resourceType/otherIdentifier")
+									}),
+								},
+							}, nil
+						}
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return client
+				},
+				logger: fakeLogger,
+			},
+			args: args{
+				clusterId: fakeClusterId,
+				tags:      map[string]string{},
+				dryRun:    false,
+			},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeEc2ClientInstanceArn
+					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeSubnet
+					item.Manager = string(managerSubnet)
+					item.Tags = fakeResourceTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusFailed
+					item.StatusReason = "failed to delete subnet: some error deleting subnet"
+				}),
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = "arn:fake:resourceType/otherIdentifier"
+					item.Name = "otherIdentifier"
+					item.ResourceType = resourceTypeSubnet
+					item.Manager = string(managerSubnet)
+					item.Tags = fakeResourceTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusComplete
+				}),
+			},
 			wantErr: "failed to delete subnet: some error deleting subnet",
 		},
+		{
+			name: "subnets are deleted concurrently and keep their order in the report",
+			fields: fields{
+				Ec2Api: func() ec2iface.EC2API {
+					//every deletion waits for the others to start, which only happens when they run at the same time
+					started := &sync.WaitGroup{}
+					started.Add(3)
+					return buildMockEc2Client(func(ec2Client *mockEc2Client) {
+						ec2Client.deleteSubnetFn = func(input *ec2.DeleteSubnetInput) (*ec2.DeleteSubnetOutput, error) {
+							started.Done()
+							started.Wait()
+							return &ec2.DeleteSubnetOutput{}, nil
+						}
+					})
+				}(),
+				taggingClient: func() *taggingClientMock {
+					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+							var mappings []*resourcegroupstaggingapi.ResourceTagMapping
+							for _, id := range []string{"first", "second", "third"} {
+								id := id
+								mappings = append(mappings, fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
+									mapping.ResourceARN = aws.String("arn:fake:resourceType/" + id)
+								}))
+							}
+							return &resourcegroupstaggingapi.GetResourcesOutput{
+								ResourceTagMappingList: mappings,
+							}, nil
+						}
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return client
+				},
+				logger:      fakeLogger,
+				concurrency: 3,
+			},
+			args: args{
+				clusterId: fakeClusterId,
+				tags:      map[string]string{},
+				dryRun:    false,
+			},
+			want: func() []*clusterservice.ReportItem {
+				var items []*clusterservice.ReportItem
+				for _, id := range []string{"first", "second", "third"} {
+					id := id
+					items = append(items, mockReportItem(func(item *clusterservice.ReportItem) {
+						item.ID = "arn:fake:resourceType/" + id
+						item.Name = id
+						item.ResourceType = resourceTypeSubnet
+						item.Manager = string(managerSubnet)
+						item.Tags = fakeResourceTags()
+						item.Action = clusterservice.ActionDelete
+						item.ActionStatus = clusterservice.ActionStatusComplete
+					}))
+				}
+				return items
+			}(),
+		},
 		{
 			name: "succeeds with status completed if dry run is false and no errors on delete aka successful deletion",
 			fields: fields{
@@ -113,7 +252,7 @@ func TestSubnetManager_DeleteResourcesForCluster(t *testing.T) {
 				}),
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
 							return &resourcegroupstaggingapi.GetResourcesOutput{
 								ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
 									fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
@@ -140,6 +279,9 @@ func TestSubnetManager_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeEc2ClientInstanceArn
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeSubnet
+					item.Manager = string(managerSubnet)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusComplete
 				}),
@@ -155,7 +297,7 @@ func TestSubnetManager_DeleteResourcesForCluster(t *testing.T) {
 				}),
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
 							return &resourcegroupstaggingapi.GetResourcesOutput{
 								ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
 									fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
@@ -182,6 +324,9 @@ func TestSubnetManager_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeEc2ClientInstanceArn
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeSubnet
+					item.Manager = string(managerSubnet)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusDryRun
 				}),
@@ -195,7 +340,8 @@ func TestSubnetManager_DeleteResourcesForCluster(t *testing.T) {
 				taggingClient: tt.fields.taggingClient(),
 				logger:        tt.fields.logger,
 			}
-			got, err := r.DeleteResourcesForCluster(tt.args.clusterId, tt.args.tags, tt.args.dryRun)
+			r.Configure(ManagerOptions{Concurrency: tt.fields.concurrency})
+			got, err := r.DeleteResourcesForCluster(context.TODO(), tt.args.clusterId, tt.args.tags, tt.args.dryRun)
 			if tt.wantErr != "" && err.Error() != tt.wantErr {
 				t.Errorf("DeleteResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -206,3 +352,49 @@ func TestSubnetManager_DeleteResourcesForCluster(t *testing.T) {
 		})
 	}
 }
+
+func TestSubnetManager_ListResourcesForCluster(t *testing.T) {
+	fakeLogger, err := fakeLogger(func(l *logrus.Entry) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeTaggingClient, err := fakeTaggingClient(func(c *taggingClientMock) error {
+		c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+			return &resourcegroupstaggingapi.GetResourcesOutput{
+				ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
+					fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
+						mapping.ResourceARN = aws.String(fakeEc2ClientInstanceArn)
+					}),
+				},
+			}, nil
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &SubnetManager{
+		//no delete functions are set, so any deletion attempt fails the test
+		ec2Client:     buildMockEc2Client(nil),
+		taggingClient: fakeTaggingClient,
+		logger:        fakeLogger,
+	}
+	got, err := r.ListResourcesForCluster(context.TODO(), fakeClusterId, map[string]string{})
+	if err != nil {
+		t.Fatalf("ListResourcesForCluster() unexpected error = %v", err)
+	}
+	want := []*clusterservice.Resource{
+		{
+			ID:           fakeEc2ClientInstanceArn,
+			Name:         fakeResourceIdentifier,
+			ResourceType: resourceTypeSubnet,
+			Manager:      string(managerSubnet),
+			Tags:         fakeResourceTags(),
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListResourcesForCluster() got = %v, want %v", got, want)
+	}
+}
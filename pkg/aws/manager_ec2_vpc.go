@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -21,13 +22,16 @@ const (
 	resourceTypeVpc = "ec2:vpc"
 )
 
-var _ ClusterResourceManager = &VpcManager{}
+var _ PhasedClusterResourceManager = &VpcManager{}
+var _ ConfigurableClusterResourceManager = &VpcManager{}
 
 // VpcManager type
 type VpcManager struct {
+	configurable
 	ec2Client     ec2Client
 	taggingClient taggingClient
 	logger        *logrus.Entry
+	region        string
 }
 
 // NewDefaultVpcManager create session for manager
@@ -36,6 +40,7 @@ func NewDefaultVpcManager(session *session.Session, logger *logrus.Entry) *VpcMa
 		ec2Client:     ec2.New(session),
 		taggingClient: resourcegroupstaggingapi.New(session),
 		logger:        logger.WithField(loggingKeyManager, managerVpc),
+		region:        regionFromSession(session),
 	}
 }
 
@@ -44,62 +49,113 @@ func (r *VpcManager) GetName() string {
 	return "AWS EC2 Vpc Manager"
 }
 
+func (r *VpcManager) GetType() ResourceManagerType {
+	return managerVpc
+}
+
+func (r *VpcManager) GetDependencies() []ResourceManagerType {
+	return []ResourceManagerType{managerSubnet, managerRouteTable, managerSecurityGroup, managerVpcPeering}
+}
+
 // DeleteResourcesForCluster deletes resource for cluster
-func (r *VpcManager) DeleteResourcesForCluster(clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
-	var vpcsToDelete []*basicResource
+func (r *VpcManager) DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
 	r.logger.Debug("delete vpc resources for cluster")
-	//  integreatly.org/clusterID tags
-	resourceInput := &resourcegroupstaggingapi.GetResourcesInput{
-		ResourceTypeFilters: aws.StringSlice([]string{resourceTypeVpc}),
-		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
-	}
-	// add to the vpc delete array
-	resourceOutput, err := r.taggingClient.GetResources(resourceInput)
+	vpcsToDelete, err := r.getVpcsForCluster(ctx, clusterId, tags)
 	if err != nil {
-		return nil, errors.WrapLog(err, "failed to filter vpc", r.logger)
-	}
-	for _, resourceTagMapping := range resourceOutput.ResourceTagMappingList {
-		arn := aws.StringValue(resourceTagMapping.ResourceARN)
-		arnElements := strings.Split(arn, "/")
-		vpcID := arnElements[len(arnElements)-1]
-		if vpcID == "" {
-			return nil, errors.WrapLog(err, fmt.Sprintf("invalid vpc name from arn, %s", vpcID), r.logger)
-		}
-		vpcsToDelete = append(vpcsToDelete, &basicResource{
-			Name: vpcID,
-			ARN:  arn,
-		})
+		return nil, err
 	}
-
-	r.logger.Debugf("found list of %d vpc to delete", len(vpcsToDelete))
 	//delete resources
 	var reportItems []*clusterservice.ReportItem
+	deletePool := newWorkerPool(r.options.Concurrency)
 	for _, vpc := range vpcsToDelete {
+		vpc := vpc
 		vpcLogger := r.logger.WithField(loggingKeyVpc, vpc.Name)
+		if !r.isTarget(vpc.ARN) {
+			vpcLogger.Debug("resource is not targeted, skipping")
+			continue
+		}
 		reportItem := &clusterservice.ReportItem{
 			ID:           vpc.ARN,
 			Name:         vpc.Name,
+			ResourceType: resourceTypeVpc,
+			Region:       r.region,
+			Account:      accountFromARN(vpc.ARN),
+			Manager:      string(managerVpc),
+			Tags:         vpc.Tags,
 			Action:       clusterservice.ActionDelete,
 			ActionStatus: clusterservice.ActionStatusInProgress,
 		}
 		reportItems = append(reportItems, reportItem)
+		if r.isProtectedWithoutCreationTime(reportItem) {
+			vpcLogger.Debug("resource is protected by the safety policy, skipping")
+			continue
+		}
 		if dryRun {
 			vpcLogger.Debugf("dry run is enabled, skipping deletion")
 			reportItem.ActionStatus = clusterservice.ActionStatusDryRun
 			continue
 		}
-		vpcLogger.Debugf("performing vpc deletion")
-		deleteVpcInput := &ec2.DeleteVpcInput{
-			VpcId: aws.String(vpc.Name),
-		}
-		if _, err := r.ec2Client.DeleteVpc(deleteVpcInput); err != nil {
-			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "DependencyViolation" {
-				vpcLogger.Debug("vpc has existing dependencies which have not been deleted, skipping")
-				reportItem.ActionStatus = clusterservice.ActionStatusSkipped
-				continue
+		deletePool.Go(func() error {
+			vpcLogger.Debugf("performing vpc deletion")
+			deleteVpcInput := &ec2.DeleteVpcInput{
+				VpcId: aws.String(vpc.Name),
+			}
+			if _, err := r.ec2Client.DeleteVpcWithContext(ctx, deleteVpcInput); err != nil {
+				if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "DependencyViolation" {
+					vpcLogger.Debug("vpc has existing dependencies which have not been deleted, skipping")
+					reportItem.ActionStatus = clusterservice.ActionStatusSkipped
+					reportItem.StatusReason = "resource has dependencies which have not been deleted"
+					return nil
+				}
+				return failReportItem(reportItem, errors.WrapLog(err, "failed to delete vpc", r.logger))
 			}
-			return nil, errors.WrapLog(err, "failed to delete vpc", r.logger)
+			return nil
+		})
+	}
+	return reportItems, errors.Aggregate(deletePool.Wait())
+}
+
+//ListResourcesForCluster List vpc resources for cluster without modifying them
+func (r *VpcManager) ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+	r.logger.Debug("list vpc resources for cluster")
+	vpcs, err := r.getVpcsForCluster(ctx, clusterId, tags)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*clusterservice.Resource, 0, len(vpcs))
+	for _, vpc := range vpcs {
+		resources = append(resources, newInventoryResource(vpc, resourceTypeVpc, r.region, managerVpc))
+	}
+	return resources, nil
+}
+
+//getVpcsForCluster Get the vpcs tagged for a cluster using the resource tagging api
+func (r *VpcManager) getVpcsForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*basicResource, error) {
+	var vpcs []*basicResource
+	//  integreatly.org/clusterID tags
+	resourceInput := &resourcegroupstaggingapi.GetResourcesInput{
+		ResourceTypeFilters: aws.StringSlice([]string{resourceTypeVpc}),
+		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
+	}
+	// add to the vpc delete array
+	resourceTagMappings, err := getTaggedResources(ctx, r.taggingClient, resourceInput)
+	if err != nil {
+		return nil, errors.WrapLog(err, "failed to filter vpc", r.logger)
+	}
+	for _, resourceTagMapping := range resourceTagMappings {
+		arn := aws.StringValue(resourceTagMapping.ResourceARN)
+		arnElements := strings.Split(arn, "/")
+		vpcID := arnElements[len(arnElements)-1]
+		if vpcID == "" {
+			return nil, errors.WrapLog(err, fmt.Sprintf("invalid vpc name from arn, %s", vpcID), r.logger)
 		}
+		vpcs = append(vpcs, &basicResource{
+			Name: vpcID,
+			ARN:  arn,
+			Tags: convertAWSTagsToMap(resourceTagMapping.Tags),
+		})
 	}
-	return reportItems, nil
+
+	r.logger.Debugf("found list of %d vpc for cluster", len(vpcs))
+	return vpcs, nil
 }
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -21,13 +22,16 @@ const (
 	resourceTypeVpcPeeringConnection = "ec2:vpc-peering-connection"
 )
 
-var _ ClusterResourceManager = &VpcPeeringManager{}
+var _ PhasedClusterResourceManager = &VpcPeeringManager{}
+var _ ConfigurableClusterResourceManager = &VpcPeeringManager{}
 
 // VpcPeeringManager type
 type VpcPeeringManager struct {
+	configurable
 	ec2Client     ec2Client
 	taggingClient taggingClient
 	logger        *logrus.Entry
+	region        string
 }
 
 // NewDefaultVpcPeeringManager create session for manager
@@ -36,6 +40,7 @@ func NewDefaultVpcPeeringManager(session *session.Session, logger *logrus.Entry)
 		ec2Client:     ec2.New(session),
 		taggingClient: resourcegroupstaggingapi.New(session),
 		logger:        logger.WithField(loggingKeyManager, managerVpcPeering),
+		region:        regionFromSession(session),
 	}
 }
 
@@ -44,73 +49,124 @@ func (r *VpcPeeringManager) GetName() string {
 	return "AWS EC2 Vpc Peering Connection Manager"
 }
 
+func (r *VpcPeeringManager) GetType() ResourceManagerType {
+	return managerVpcPeering
+}
+
+func (r *VpcPeeringManager) GetDependencies() []ResourceManagerType {
+	return nil
+}
+
 // DeleteResourcesForCluster deletes resource for cluster
-func (r *VpcPeeringManager) DeleteResourcesForCluster(clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
-	var vpcPeeringConnectionsToDelete []*basicResource
+func (r *VpcPeeringManager) DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
 	r.logger.Debug("delete vpc peering connections resources for cluster")
-	resourceInput := &resourcegroupstaggingapi.GetResourcesInput{
-		ResourceTypeFilters: aws.StringSlice([]string{resourceTypeVpcPeeringConnection}),
-		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
-	}
-	// add to the vpc peering delete array
-	resourceOutput, err := r.taggingClient.GetResources(resourceInput)
+	vpcPeeringConnectionsToDelete, err := r.getVpcPeeringConnectionsForCluster(ctx, clusterId, tags)
 	if err != nil {
-		return nil, errors.WrapLog(err, "failed to filter vpc peering connections", r.logger)
-	}
-
-	for _, resourceTagMapping := range resourceOutput.ResourceTagMappingList {
-		arn := aws.StringValue(resourceTagMapping.ResourceARN)
-		arnElements := strings.Split(arn, "/")
-		vpcPeeringConnectionID := arnElements[len(arnElements)-1]
-		if vpcPeeringConnectionID == "" {
-			return nil, errors.WrapLog(err, fmt.Sprintf("invalid vpc peering connection name from arn, %s", vpcPeeringConnectionID), r.logger)
-		}
-		vpcPeeringConnectionsToDelete = append(vpcPeeringConnectionsToDelete, &basicResource{
-			Name: vpcPeeringConnectionID,
-			ARN:  arn,
-		})
-		r.logger.Debugf("found list of %d vpc peering connection to delete", len(vpcPeeringConnectionsToDelete))
+		return nil, err
 	}
 	//delete resources
 	var reportItems []*clusterservice.ReportItem
+	deletePool := newWorkerPool(r.options.Concurrency)
 	for _, vpcPeeringConnection := range vpcPeeringConnectionsToDelete {
+		vpcPeeringConnection := vpcPeeringConnection
 		vpcPeeringConnectionLogger := r.logger.WithField(loggingKeyVpcPeeringConnection, vpcPeeringConnection.Name)
+		if !r.isTarget(vpcPeeringConnection.ARN) {
+			vpcPeeringConnectionLogger.Debug("resource is not targeted, skipping")
+			continue
+		}
 		reportItem := &clusterservice.ReportItem{
 			ID:           vpcPeeringConnection.ARN,
 			Name:         vpcPeeringConnection.Name,
+			ResourceType: resourceTypeVpcPeeringConnection,
+			Region:       r.region,
+			Account:      accountFromARN(vpcPeeringConnection.ARN),
+			Manager:      string(managerVpcPeering),
+			Tags:         vpcPeeringConnection.Tags,
 			Action:       clusterservice.ActionDelete,
 			ActionStatus: clusterservice.ActionStatusInProgress,
 		}
 		reportItems = append(reportItems, reportItem)
+		if r.isProtectedWithoutCreationTime(reportItem) {
+			vpcPeeringConnectionLogger.Debug("resource is protected by the safety policy, skipping")
+			continue
+		}
 		if dryRun {
 			vpcPeeringConnectionLogger.Debugf("dry run is enabled, skipping deletion")
 			reportItem.ActionStatus = clusterservice.ActionStatusDryRun
 			continue
 		}
-		vpcPeeringConnectionLogger.Debugf("performing vpc peering connection deletion")
-		deleteVpcPeeringConnectionInput := &ec2.DeleteVpcPeeringConnectionInput{
-			VpcPeeringConnectionId: aws.String(vpcPeeringConnection.Name),
-		}
-
-		if _, err := r.ec2Client.DeleteVpcPeeringConnection(deleteVpcPeeringConnectionInput); err != nil {
-			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "DependencyViolation" {
-				vpcPeeringConnectionLogger.Debug("vpc peering connection has existing dependencies which have not been deleted, skipping")
-				reportItem.ActionStatus = clusterservice.ActionStatusSkipped
-				r.logger.Infof("Error: %s, %s", awsErr.Code(), awsErr.Message())
-				continue
+		deletePool.Go(func() error {
+			vpcPeeringConnectionLogger.Debugf("performing vpc peering connection deletion")
+			deleteVpcPeeringConnectionInput := &ec2.DeleteVpcPeeringConnectionInput{
+				VpcPeeringConnectionId: aws.String(vpcPeeringConnection.Name),
 			}
 
-			// in the case of vpc peerings they are picked up on describe but then when attempting to delete they are not found
-			// any that are not found can be skipped.
-			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "InvalidVpcPeeringConnectionID.NotFound" {
-				vpcPeeringConnectionLogger.Debug("vpc peering connection does not exist, assume deleted")
-				reportItem.ActionStatus = clusterservice.ActionStatusComplete
-				continue
+			if _, err := r.ec2Client.DeleteVpcPeeringConnectionWithContext(ctx, deleteVpcPeeringConnectionInput); err != nil {
+				if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "DependencyViolation" {
+					vpcPeeringConnectionLogger.Debug("vpc peering connection has existing dependencies which have not been deleted, skipping")
+					reportItem.ActionStatus = clusterservice.ActionStatusSkipped
+					reportItem.StatusReason = "resource has dependencies which have not been deleted"
+					r.logger.Infof("Error: %s, %s", awsErr.Code(), awsErr.Message())
+					return nil
+				}
+
+				// in the case of vpc peerings they are picked up on describe but then when attempting to delete they are not found
+				// any that are not found can be skipped.
+				if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "InvalidVpcPeeringConnectionID.NotFound" {
+					vpcPeeringConnectionLogger.Debug("vpc peering connection does not exist, assume deleted")
+					reportItem.ActionStatus = clusterservice.ActionStatusComplete
+					return nil
+				}
+
+				return failReportItem(reportItem, errors.WrapLog(err, "failed to delete vpc peering connection", r.logger))
 			}
+			reportItem.ActionStatus = clusterservice.ActionStatusComplete
+			return nil
+		})
+	}
+	return reportItems, errors.Aggregate(deletePool.Wait())
+}
+
+//ListResourcesForCluster List vpc peering connection resources for cluster without modifying them
+func (r *VpcPeeringManager) ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+	r.logger.Debug("list vpc peering connection resources for cluster")
+	vpcPeeringConnections, err := r.getVpcPeeringConnectionsForCluster(ctx, clusterId, tags)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*clusterservice.Resource, 0, len(vpcPeeringConnections))
+	for _, vpcPeeringConnection := range vpcPeeringConnections {
+		resources = append(resources, newInventoryResource(vpcPeeringConnection, resourceTypeVpcPeeringConnection, r.region, managerVpcPeering))
+	}
+	return resources, nil
+}
 
-			return nil, errors.WrapLog(err, "failed to delete vpc peering connection", r.logger)
+//getVpcPeeringConnectionsForCluster Get the vpc peering connections tagged for a cluster using the resource tagging api
+func (r *VpcPeeringManager) getVpcPeeringConnectionsForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*basicResource, error) {
+	var vpcPeeringConnections []*basicResource
+	resourceInput := &resourcegroupstaggingapi.GetResourcesInput{
+		ResourceTypeFilters: aws.StringSlice([]string{resourceTypeVpcPeeringConnection}),
+		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
+	}
+	// add to the vpc peering delete array
+	resourceTagMappings, err := getTaggedResources(ctx, r.taggingClient, resourceInput)
+	if err != nil {
+		return nil, errors.WrapLog(err, "failed to filter vpc peering connections", r.logger)
+	}
+
+	for _, resourceTagMapping := range resourceTagMappings {
+		arn := aws.StringValue(resourceTagMapping.ResourceARN)
+		arnElements := strings.Split(arn, "/")
+		vpcPeeringConnectionID := arnElements[len(arnElements)-1]
+		if vpcPeeringConnectionID == "" {
+			return nil, errors.WrapLog(err, fmt.Sprintf("invalid vpc peering connection name from arn, %s", vpcPeeringConnectionID), r.logger)
 		}
-		reportItem.ActionStatus = clusterservice.ActionStatusComplete
+		vpcPeeringConnections = append(vpcPeeringConnections, &basicResource{
+			Name: vpcPeeringConnectionID,
+			ARN:  arn,
+			Tags: convertAWSTagsToMap(resourceTagMapping.Tags),
+		})
+		r.logger.Debugf("found list of %d vpc peering connection for cluster", len(vpcPeeringConnections))
 	}
-	return reportItems, nil
+	return vpcPeeringConnections, nil
 }
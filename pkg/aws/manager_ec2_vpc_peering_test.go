@@ -1,8 +1,10 @@
 package aws
 
 import (
+	"context"
 	"errors"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"reflect"
 	"testing"
 
@@ -50,7 +52,7 @@ func TestVpcPeeringManager_DeleteResourcesForCluster(t *testing.T) {
 				}),
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
 							return &resourcegroupstaggingapi.GetResourcesOutput{
 								ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
 									fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
@@ -77,6 +79,9 @@ func TestVpcPeeringManager_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeEc2ClientInstanceArn
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeVpcPeeringConnection
+					item.Manager = string(managerVpcPeering)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusComplete
 				}),
@@ -92,7 +97,7 @@ func TestVpcPeeringManager_DeleteResourcesForCluster(t *testing.T) {
 				}),
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
 							return nil, errors.New("")
 						}
 						return nil
@@ -121,7 +126,7 @@ func TestVpcPeeringManager_DeleteResourcesForCluster(t *testing.T) {
 				}),
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
 							return &resourcegroupstaggingapi.GetResourcesOutput{
 								ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
 									fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
@@ -144,6 +149,18 @@ func TestVpcPeeringManager_DeleteResourcesForCluster(t *testing.T) {
 				tags:      map[string]string{},
 				dryRun:    false,
 			},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeEc2ClientInstanceArn
+					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeVpcPeeringConnection
+					item.Manager = string(managerVpcPeering)
+					item.Tags = fakeResourceTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusFailed
+					item.StatusReason = "failed to delete vpc peering connection: some error deleting vpc peering connection"
+				}),
+			},
 			wantErr: "failed to delete vpc peering connection: some error deleting vpc peering connection",
 		},
 		{
@@ -156,7 +173,7 @@ func TestVpcPeeringManager_DeleteResourcesForCluster(t *testing.T) {
 				}),
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
 							return &resourcegroupstaggingapi.GetResourcesOutput{
 								ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
 									fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
@@ -183,6 +200,9 @@ func TestVpcPeeringManager_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeEc2ClientInstanceArn
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeVpcPeeringConnection
+					item.Manager = string(managerVpcPeering)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusComplete
 				}),
@@ -198,7 +218,7 @@ func TestVpcPeeringManager_DeleteResourcesForCluster(t *testing.T) {
 				}),
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
 							return &resourcegroupstaggingapi.GetResourcesOutput{
 								ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
 									fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
@@ -225,6 +245,9 @@ func TestVpcPeeringManager_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeEc2ClientInstanceArn
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeVpcPeeringConnection
+					item.Manager = string(managerVpcPeering)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusDryRun
 				}),
@@ -238,7 +261,7 @@ func TestVpcPeeringManager_DeleteResourcesForCluster(t *testing.T) {
 				taggingClient: tt.fields.taggingClient(),
 				logger:        tt.fields.logger,
 			}
-			got, err := r.DeleteResourcesForCluster(tt.args.clusterId, tt.args.tags, tt.args.dryRun)
+			got, err := r.DeleteResourcesForCluster(context.TODO(), tt.args.clusterId, tt.args.tags, tt.args.dryRun)
 			if tt.wantErr != "" && err.Error() != tt.wantErr {
 				t.Errorf("DeleteResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -1,10 +1,13 @@
 package aws
 
 import (
+	"context"
 	"errors"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
@@ -36,6 +39,7 @@ func TestVpcManager_DeleteResourcesForCluster(t *testing.T) {
 		name    string
 		fields  fields
 		args    args
+		options ManagerOptions
 		want    []*clusterservice.ReportItem
 		wantErr string
 	}{
@@ -49,7 +53,7 @@ func TestVpcManager_DeleteResourcesForCluster(t *testing.T) {
 				}),
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
 							return nil, errors.New("")
 						}
 						return nil
@@ -78,7 +82,7 @@ func TestVpcManager_DeleteResourcesForCluster(t *testing.T) {
 				}),
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
 							return &resourcegroupstaggingapi.GetResourcesOutput{
 								ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
 									fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
@@ -101,6 +105,18 @@ func TestVpcManager_DeleteResourcesForCluster(t *testing.T) {
 				tags:      map[string]string{},
 				dryRun:    false,
 			},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeEc2ClientInstanceArn
+					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeVpc
+					item.Manager = string(managerVpc)
+					item.Tags = fakeResourceTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusFailed
+					item.StatusReason = "failed to delete vpc: some error deleting vpc"
+				}),
+			},
 			wantErr: "failed to delete vpc: some error deleting vpc",
 		},
 		{
@@ -113,7 +129,7 @@ func TestVpcManager_DeleteResourcesForCluster(t *testing.T) {
 				}),
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
 							return &resourcegroupstaggingapi.GetResourcesOutput{
 								ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
 									fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
@@ -140,6 +156,9 @@ func TestVpcManager_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeEc2ClientInstanceArn
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeVpc
+					item.Manager = string(managerVpc)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusInProgress
 				}),
@@ -155,7 +174,7 @@ func TestVpcManager_DeleteResourcesForCluster(t *testing.T) {
 				}),
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
 							return &resourcegroupstaggingapi.GetResourcesOutput{
 								ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
 									fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
@@ -182,20 +201,71 @@ func TestVpcManager_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeEc2ClientInstanceArn
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeVpc
+					item.Manager = string(managerVpc)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusDryRun
 				}),
 			},
 		},
+		{
+			name: "vpcs are deleted under a minimum age, they have no creation time",
+			fields: fields{
+				Ec2Api: buildMockEc2Client(func(ec2Client *mockEc2Client) {
+					ec2Client.deleteVpcFn = func(input *ec2.DeleteVpcInput) (*ec2.DeleteVpcOutput, error) {
+						return &ec2.DeleteVpcOutput{}, nil
+					}
+				}),
+				taggingClient: func() *taggingClientMock {
+					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+							return &resourcegroupstaggingapi.GetResourcesOutput{
+								ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
+									fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
+										mapping.ResourceARN = aws.String(fakeEc2ClientInstanceArn)
+									}),
+								},
+							}, nil
+						}
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return client
+				},
+				logger: fakeLogger,
+			},
+			args: args{
+				clusterId: fakeClusterId,
+				tags:      map[string]string{},
+				dryRun:    false,
+			},
+			options: ManagerOptions{Policy: &SafetyPolicy{MinimumAge: 24 * time.Hour}},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeEc2ClientInstanceArn
+					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeVpc
+					item.Manager = string(managerVpc)
+					item.Tags = fakeResourceTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.StatusReason = "age not applicable, the resource type has no creation time"
+				}),
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &VpcManager{
+				configurable:  configurable{options: tt.options},
 				ec2Client:     tt.fields.Ec2Api,
 				taggingClient: tt.fields.taggingClient(),
 				logger:        tt.fields.logger,
 			}
-			got, err := r.DeleteResourcesForCluster(tt.args.clusterId, tt.args.tags, tt.args.dryRun)
+			got, err := r.DeleteResourcesForCluster(context.TODO(), tt.args.clusterId, tt.args.tags, tt.args.dryRun)
 			if tt.wantErr != "" && err.Error() != tt.wantErr {
 				t.Errorf("DeleteResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
 				return
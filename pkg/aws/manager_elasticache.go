@@ -1,8 +1,10 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -16,13 +18,53 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-var _ ClusterResourceManager = &ElasticacheManager{}
+const (
+	resourceTypeElasticacheCluster          = "elasticache:cluster"
+	resourceTypeElasticacheReplicationGroup = "elasticache:replicationgroup"
+	resourceTypeElasticacheSubnetGroup      = "elasticache:subnetgroup"
+
+	//elasticacheKindReplicationGroup and elasticacheKindSubnetGroup Kinds of the resources without an arn in the ids of their report items
+	elasticacheKindReplicationGroup = "replicationgroup"
+	elasticacheKindSubnetGroup      = "subnetgroup"
+
+	//pendingFinalSnapshotPrefix Prefix of the pending resources which are final snapshots waiting to be tagged, the others are cache subnet groups
+	//The name of the snapshot is followed by its retain until time, e.g. snapshot:my-group-final@2021-04-04T10:30:00Z
+	pendingFinalSnapshotPrefix = "snapshot:"
+	//pendingFinalSnapshotSeparator Separates the name of a pending final snapshot from its retain until time, snapshot names only allow letters, digits and hyphens
+	pendingFinalSnapshotSeparator = "@"
+
+	//elasticacheReplicationGroupDeletionCheckInterval Deleting a replication group usually takes several minutes, there is no point checking on it much sooner
+	elasticacheReplicationGroupDeletionCheckInterval = 3 * time.Minute
+)
+
+var _ PhasedClusterResourceManager = &ElasticacheManager{}
+var _ ConfigurableClusterResourceManager = &ElasticacheManager{}
+var _ StatefulClusterResourceManager = &ElasticacheManager{}
+
+//elasticacheReplicationGroup replication group discovered through one of its tagged cache clusters
+type elasticacheReplicationGroup struct {
+	ID           string
+	Account      string
+	Tags         map[string]string
+	CreationTime *time.Time
+}
+
+//pendingFinalSnapshot final snapshot of a deleted replication group waiting to be tagged
+type pendingFinalSnapshot struct {
+	Name string
+	//RetainUntil Value of the retain until tag, set when the deletion started so later runs keep the retention of the run which took the snapshot
+	RetainUntil string
+}
 
 type ElasticacheManager struct {
+	configurable
 	elasticacheClient    elasticacheiface.ElastiCacheAPI
 	taggingClient        resourcegroupstaggingapiiface.ResourceGroupsTaggingAPIAPI
 	logger               *logrus.Entry
+	region               string
 	subnetGroupsToDelete []string
+	//finalSnapshotsToTag Final snapshots of deleted replication groups, which only exist some time after the deletion started
+	finalSnapshotsToTag []*pendingFinalSnapshot
 }
 
 func NewDefaultElasticacheManager(session *session.Session, logger *logrus.Entry) *ElasticacheManager {
@@ -30,6 +72,7 @@ func NewDefaultElasticacheManager(session *session.Session, logger *logrus.Entry
 		elasticacheClient:    elasticache.New(session),
 		taggingClient:        resourcegroupstaggingapi.New(session),
 		logger:               logger.WithField(loggingKeyManager, managerElasticache),
+		region:               regionFromSession(session),
 		subnetGroupsToDelete: make([]string, 0),
 	}
 }
@@ -38,85 +81,137 @@ func (r *ElasticacheManager) GetName() string {
 	return "AWS ElastiCache Manager"
 }
 
+func (r *ElasticacheManager) GetType() ResourceManagerType {
+	return managerElasticache
+}
+
+func (r *ElasticacheManager) GetDependencies() []ResourceManagerType {
+	return nil
+}
+
+//GetPendingResources Names of the cache subnet groups which still need to be deleted and of the final snapshots which still need to be tagged
+func (r *ElasticacheManager) GetPendingResources() []string {
+	pending := make([]string, len(r.subnetGroupsToDelete), len(r.subnetGroupsToDelete)+len(r.finalSnapshotsToTag))
+	copy(pending, r.subnetGroupsToDelete)
+	for _, snapshot := range r.finalSnapshotsToTag {
+		pending = append(pending, pendingFinalSnapshotPrefix+snapshot.Name+pendingFinalSnapshotSeparator+snapshot.RetainUntil)
+	}
+	return pending
+}
+
+//RestorePendingResources Add the cache subnet groups a previous process didn't get to delete and the final snapshots it didn't get to tag
+func (r *ElasticacheManager) RestorePendingResources(pending []string) {
+	for _, name := range pending {
+		if strings.HasPrefix(name, pendingFinalSnapshotPrefix) {
+			snapshotName, retainUntil, _ := strings.Cut(strings.TrimPrefix(name, pendingFinalSnapshotPrefix), pendingFinalSnapshotSeparator)
+			r.addFinalSnapshotToTag(snapshotName, retainUntil)
+			continue
+		}
+		r.subnetGroupsToDelete = appendIfUnique(r.subnetGroupsToDelete, name)
+	}
+}
+
 //Delete all elasticache resources for a specified cluster
-func (r *ElasticacheManager) DeleteResourcesForCluster(clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
+func (r *ElasticacheManager) DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
 	logger := r.logger.WithFields(logrus.Fields{"clusterId": clusterId, "dryRun": dryRun})
 	logger.Debug("deleting resources for cluster")
 
 	var reportItems []*clusterservice.ReportItem
-	var replicationGroupsToDelete []string
-	resourceInput := &resourcegroupstaggingapi.GetResourcesInput{
-		ResourceTypeFilters: aws.StringSlice([]string{"elasticache:cluster"}),
-		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
-	}
-	resourceOutput, err := r.taggingClient.GetResources(resourceInput)
+	replicationGroupsToDelete, subnetGroupNames, err := r.getReplicationGroupsForCluster(ctx, clusterId, tags, logger)
 	if err != nil {
-		return nil, errors.WrapLog(err, "failed to describe cache clusters", logger)
+		return nil, err
 	}
-
-	for _, resourceTagMapping := range resourceOutput.ResourceTagMappingList {
-		arn := aws.StringValue(resourceTagMapping.ResourceARN)
-		arnSplit := strings.Split(arn, ":")
-		cacheClusterId := arnSplit[len(arnSplit)-1]
-		cacheClusterInput := &elasticache.DescribeCacheClustersInput{
-			CacheClusterId: aws.String(cacheClusterId),
-		}
-		cacheClusterOutput, err := r.elasticacheClient.DescribeCacheClusters(cacheClusterInput)
-		if err != nil {
-			return nil, errors.WrapLog(err, "cannot get cacheCluster output", logger)
-		}
-		for _, cacheCluster := range cacheClusterOutput.CacheClusters {
-			rgLogger := logger.WithField("replicationGroup", cacheCluster.ReplicationGroupId)
-			if contains(replicationGroupsToDelete, *cacheCluster.ReplicationGroupId) {
-				rgLogger.Debugf("replication Group already exists in deletion list (%s=%s)", *cacheCluster.ReplicationGroupId, clusterId)
-				break
-			}
-			replicationGroupsToDelete = append(replicationGroupsToDelete, *cacheCluster.ReplicationGroupId)
-			// elasticache subnet groups don't support tags
-			// add the cache subnet group to the subnetGroupsToDelete list
-			// This way we can actually delete the subnet groups later on
-			// when the caches are torn down
-			r.subnetGroupsToDelete = appendIfUnique(r.subnetGroupsToDelete, *cacheCluster.CacheSubnetGroupName)
-		}
+	// elasticache subnet groups don't support tags
+	// add the cache subnet groups to the subnetGroupsToDelete list
+	// This way we can actually delete the subnet groups later on
+	// when the caches are torn down
+	for _, subnetGroupName := range subnetGroupNames {
+		r.subnetGroupsToDelete = appendIfUnique(r.subnetGroupsToDelete, subnetGroupName)
+	}
+	//final snapshots started by earlier runs are tagged whether or not this run takes final snapshots
+	if !dryRun {
+		r.tagFinalSnapshots(ctx, clusterId, tags, logger)
 	}
-	logger.Debugf("filtering complete, %d replicationGroups matched", len(replicationGroupsToDelete))
-	for _, replicationGroupId := range replicationGroupsToDelete {
+	deletePool := newWorkerPool(r.options.Concurrency)
+	//each deletion only writes its own element, so the snapshots can be recorded without locking
+	startedFinalSnapshots := make([]string, len(replicationGroupsToDelete))
+	retainUntil := ""
+	if r.options.FinalSnapshot != nil {
+		retainUntil = r.options.FinalSnapshot.RetainUntil()
+	}
+	for i, replicationGroup := range replicationGroupsToDelete {
+		i := i
+		replicationGroup := replicationGroup
 		//delete each replication group in the list
+		replicationGroupId := replicationGroup.ID
 		rgLogger := logger.WithField("replicationGroupId", aws.String(replicationGroupId))
+		if !r.isTarget(elasticacheReportID(r.region, elasticacheKindReplicationGroup, replicationGroupId)) {
+			rgLogger.Debug("resource is not targeted, skipping")
+			continue
+		}
 		rgLogger.Debugf("building report for database")
 		reportItem := &clusterservice.ReportItem{
-			ID:           replicationGroupId,
-			Name:         "elasticache Replication group",
+			ID:           elasticacheReportID(r.region, elasticacheKindReplicationGroup, replicationGroupId),
+			Name:         replicationGroupId,
+			ResourceType: resourceTypeElasticacheReplicationGroup,
+			Region:       r.region,
+			Account:      replicationGroup.Account,
+			Manager:      string(managerElasticache),
+			Tags:         replicationGroup.Tags,
 			Action:       clusterservice.ActionDelete,
 			ActionStatus: clusterservice.ActionStatusInProgress,
 		}
 		reportItems = append(reportItems, reportItem)
-		if dryRun {
-			rgLogger.Debug("dry run enabled, skipping deletion step")
-			reportItem.ActionStatus = clusterservice.ActionStatusDryRun
+		if r.isProtected(reportItem, replicationGroup.CreationTime) {
+			rgLogger.Debug("resource is protected by the safety policy, skipping")
 			continue
 		}
-		rgLogger.Debug("performing deletion of replication group")
-		replicationGroupDescribeInput := &elasticache.DescribeReplicationGroupsInput{
-			ReplicationGroupId: &replicationGroupId,
+		if r.options.FinalSnapshot != nil {
+			reportItem.FinalSnapshot = finalSnapshotIdentifier(replicationGroupId, replicationGroup.CreationTime)
 		}
-		replicationGroup, err := r.elasticacheClient.DescribeReplicationGroups(replicationGroupDescribeInput)
-		if err != nil {
-			return nil, errors.WrapLog(err, "cannot describe replicationGroups", logger)
-		}
-		//deleting will return an error if the replication group is already in a deleting state
-		if len(replicationGroup.ReplicationGroups) > 0 &&
-			aws.StringValue(replicationGroup.ReplicationGroups[0].Status) == statusDeleting {
-			rgLogger.Debugf("deletion of replication Groups already in progress")
-			reportItem.ActionStatus = clusterservice.ActionStatusInProgress
+		if dryRun {
+			rgLogger.Debug("dry run enabled, skipping deletion step")
+			reportItem.ActionStatus = clusterservice.ActionStatusDryRun
 			continue
 		}
-		deleteReplicationGroupInput := &elasticache.DeleteReplicationGroupInput{
-			ReplicationGroupId:   aws.String(replicationGroupId),
-			RetainPrimaryCluster: aws.Bool(false),
-		}
-		if _, err := r.elasticacheClient.DeleteReplicationGroup(deleteReplicationGroupInput); err != nil {
-			return nil, errors.WrapLog(err, "failed to delete elasticache replication group", logger)
+		deletePool.Go(func() error {
+			rgLogger.Debug("performing deletion of replication group")
+			reportItem.NextCheckAfter = elasticacheReplicationGroupDeletionCheckInterval
+			replicationGroupDescribeInput := &elasticache.DescribeReplicationGroupsInput{
+				ReplicationGroupId: &replicationGroupId,
+			}
+			replicationGroups, err := describeReplicationGroups(ctx, r.elasticacheClient, replicationGroupDescribeInput)
+			if err != nil {
+				return failReportItem(reportItem, errors.WrapLog(err, "cannot describe replicationGroups", logger))
+			}
+			//deleting will return an error if the replication group is already in a deleting state
+			if len(replicationGroups) > 0 &&
+				aws.StringValue(replicationGroups[0].Status) == statusDeleting {
+				rgLogger.Debugf("deletion of replication Groups already in progress")
+				reportItem.ActionStatus = clusterservice.ActionStatusInProgress
+				reportItem.StatusReason = "deletion already in progress"
+				return nil
+			}
+			deleteReplicationGroupInput := &elasticache.DeleteReplicationGroupInput{
+				ReplicationGroupId:   aws.String(replicationGroupId),
+				RetainPrimaryCluster: aws.Bool(false),
+			}
+			//the final snapshot is taken by the deletion itself
+			if reportItem.FinalSnapshot != "" {
+				deleteReplicationGroupInput.FinalSnapshotIdentifier = aws.String(reportItem.FinalSnapshot)
+			}
+			if _, err := r.elasticacheClient.DeleteReplicationGroupWithContext(ctx, deleteReplicationGroupInput); err != nil {
+				return failReportItem(reportItem, errors.WrapLog(err, "failed to delete elasticache replication group", logger))
+			}
+			startedFinalSnapshots[i] = reportItem.FinalSnapshot
+			return nil
+		})
+	}
+	deleteErrors := deletePool.Wait()
+	//the final snapshot only exists some time after the deletion started, so it's tagged by later runs
+	for _, snapshotName := range startedFinalSnapshots {
+		if snapshotName != "" {
+			r.addFinalSnapshotToTag(snapshotName, retainUntil)
 		}
 	}
 	// handle deletion of orphaned cache subnet groups
@@ -126,14 +221,28 @@ func (r *ElasticacheManager) DeleteResourcesForCluster(clusterId string, tags ma
 
 	for _, subnetGroupName := range r.subnetGroupsToDelete {
 		sgLogger := logger.WithField("subnetGroup", aws.String(subnetGroupName))
+		//untargeted subnet groups are left pending, they may be targeted by a later run
+		if !r.isTarget(elasticacheReportID(r.region, elasticacheKindSubnetGroup, subnetGroupName)) {
+			sgLogger.Debug("resource is not targeted, skipping")
+			nextSubnetGroupsToDelete = append(nextSubnetGroupsToDelete, subnetGroupName)
+			continue
+		}
 		sgLogger.Debugf("building report for cache subnet groups")
 		reportItem := &clusterservice.ReportItem{
-			ID:           fmt.Sprintf("subnetgroup:%s", subnetGroupName),
-			Name:         "elasticache subnet group",
+			ID:           elasticacheReportID(r.region, elasticacheKindSubnetGroup, subnetGroupName),
+			Name:         subnetGroupName,
+			ResourceType: resourceTypeElasticacheSubnetGroup,
+			Region:       r.region,
+			Manager:      string(managerElasticache),
 			Action:       clusterservice.ActionDelete,
 			ActionStatus: clusterservice.ActionStatusInProgress,
 		}
 		reportItems = append(reportItems, reportItem)
+		if r.isProtectedWithoutCreationTime(reportItem) {
+			sgLogger.Debug("resource is protected by the safety policy, skipping")
+			nextSubnetGroupsToDelete = append(nextSubnetGroupsToDelete, subnetGroupName)
+			continue
+		}
 		if dryRun {
 			sgLogger.Debug("dry run enabled, skipping deletion step")
 			reportItem.ActionStatus = clusterservice.ActionStatusDryRun
@@ -142,25 +251,167 @@ func (r *ElasticacheManager) DeleteResourcesForCluster(clusterId string, tags ma
 		deleteSubnetGroupInput := &elasticache.DeleteCacheSubnetGroupInput{
 			CacheSubnetGroupName: &subnetGroupName,
 		}
-		if _, err := r.elasticacheClient.DeleteCacheSubnetGroup(deleteSubnetGroupInput); err != nil {
+		if _, err := r.elasticacheClient.DeleteCacheSubnetGroupWithContext(ctx, deleteSubnetGroupInput); err != nil {
 			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "CacheSubnetGroupInUse" {
 				sgLogger.Debug("cache subnet group is still in use, skipping")
 				reportItem.ActionStatus = clusterservice.ActionStatusSkipped
+				reportItem.StatusReason = "cache subnet group is in use"
 				// push the subnetGroup into the list of groups to be deleted next time
 				nextSubnetGroupsToDelete = append(nextSubnetGroupsToDelete, subnetGroupName)
 				continue
 			}
-			return nil, errors.WrapLog(err, "failed to delete cache subnet group", sgLogger)
+			deleteErrors = append(deleteErrors, failReportItem(reportItem, errors.WrapLog(err, "failed to delete cache subnet group", sgLogger)))
+			// retry failed deletions the next time
+			nextSubnetGroupsToDelete = append(nextSubnetGroupsToDelete, subnetGroupName)
+			continue
 		}
 		reportItem.ActionStatus = clusterservice.ActionStatusComplete
 	}
 	r.subnetGroupsToDelete = nextSubnetGroupsToDelete
 	if reportItems != nil {
-		return reportItems, nil
+		return reportItems, errors.Aggregate(deleteErrors)
 	}
 	return nil, nil
 }
 
+//tagFinalSnapshots Tag the final snapshots of deleted replication groups so the snapshot manager finds them and keeps them until the retention elapses
+//A final snapshot only exists some time after the deletion of its replication group started, snapshots which can't be tagged yet are tried again by the next run
+func (r *ElasticacheManager) tagFinalSnapshots(ctx context.Context, clusterId string, tags map[string]string, logger *logrus.Entry) {
+	snapshotTags := map[string]string{tagKeyClusterId: clusterId}
+	for key, value := range tags {
+		snapshotTags[key] = value
+	}
+	nextFinalSnapshotsToTag := make([]*pendingFinalSnapshot, 0)
+	for _, snapshot := range r.finalSnapshotsToTag {
+		snapshotLogger := logger.WithField(loggingKeySnapshot, snapshot.Name)
+		snapshots, err := describeCacheSnapshots(ctx, r.elasticacheClient, &elasticache.DescribeSnapshotsInput{
+			SnapshotName: aws.String(snapshot.Name),
+		})
+		if err != nil {
+			if awsErr, ok := err.(awserr.Error); !ok || awsErr.Code() != elasticache.ErrCodeSnapshotNotFoundFault {
+				snapshotLogger.Warnf("failed to describe final snapshot: %v", err)
+			}
+			nextFinalSnapshotsToTag = append(nextFinalSnapshotsToTag, snapshot)
+			continue
+		}
+		if len(snapshots) == 0 || aws.StringValue(snapshots[0].ARN) == "" {
+			snapshotLogger.Debug("final snapshot not created yet, tagging it on the next run")
+			nextFinalSnapshotsToTag = append(nextFinalSnapshotsToTag, snapshot)
+			continue
+		}
+		snapshotLogger.Debug("tagging final snapshot")
+		addTagsInput := &elasticache.AddTagsToResourceInput{
+			ResourceName: snapshots[0].ARN,
+			Tags:         finalSnapshotElasticacheTags(snapshot.RetainUntil, snapshotTags),
+		}
+		if _, err := r.elasticacheClient.AddTagsToResourceWithContext(ctx, addTagsInput); err != nil {
+			snapshotLogger.Warnf("failed to tag final snapshot: %v", err)
+			nextFinalSnapshotsToTag = append(nextFinalSnapshotsToTag, snapshot)
+		}
+	}
+	r.finalSnapshotsToTag = nextFinalSnapshotsToTag
+}
+
+//addFinalSnapshotToTag Add a final snapshot waiting to be tagged, unless it's already waiting
+//A snapshot without a retain until time, e.g. restored from the state of an older version, is kept for the default retention
+func (r *ElasticacheManager) addFinalSnapshotToTag(snapshotName string, retainUntil string) {
+	for _, snapshot := range r.finalSnapshotsToTag {
+		if snapshot.Name == snapshotName {
+			return
+		}
+	}
+	if retainUntil == "" {
+		retainUntil = NewDefaultFinalSnapshotPolicy().RetainUntil()
+	}
+	r.finalSnapshotsToTag = append(r.finalSnapshotsToTag, &pendingFinalSnapshot{Name: snapshotName, RetainUntil: retainUntil})
+}
+
+//ListResourcesForCluster List elasticache resources for a specified cluster without modifying them
+func (r *ElasticacheManager) ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+	logger := r.logger.WithField("clusterId", clusterId)
+	logger.Debug("listing resources for cluster")
+	replicationGroups, subnetGroupNames, err := r.getReplicationGroupsForCluster(ctx, clusterId, tags, logger)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*clusterservice.Resource, 0, len(replicationGroups)+len(subnetGroupNames))
+	for _, replicationGroup := range replicationGroups {
+		resource := &clusterservice.Resource{
+			ID:           elasticacheReportID(r.region, elasticacheKindReplicationGroup, replicationGroup.ID),
+			Name:         replicationGroup.ID,
+			ResourceType: resourceTypeElasticacheReplicationGroup,
+			Region:       r.region,
+			Account:      replicationGroup.Account,
+			Manager:      string(managerElasticache),
+			Tags:         replicationGroup.Tags,
+		}
+		describedReplicationGroups, err := describeReplicationGroups(ctx, r.elasticacheClient, &elasticache.DescribeReplicationGroupsInput{
+			ReplicationGroupId: aws.String(replicationGroup.ID),
+		})
+		if err != nil {
+			return nil, errors.WrapLog(err, "cannot describe replicationGroups", logger)
+		}
+		if len(describedReplicationGroups) > 0 {
+			resource.State = aws.StringValue(describedReplicationGroups[0].Status)
+			resource.CreationTime = describedReplicationGroups[0].ReplicationGroupCreateTime
+		}
+		resources = append(resources, resource)
+	}
+	for _, subnetGroupName := range subnetGroupNames {
+		resources = append(resources, &clusterservice.Resource{
+			ID:           elasticacheReportID(r.region, elasticacheKindSubnetGroup, subnetGroupName),
+			Name:         subnetGroupName,
+			ResourceType: resourceTypeElasticacheSubnetGroup,
+			Region:       r.region,
+			Manager:      string(managerElasticache),
+		})
+	}
+	return resources, nil
+}
+
+//getReplicationGroupsForCluster Get the replication groups of the cache clusters tagged for a cluster, along with the cache subnet groups they use
+func (r *ElasticacheManager) getReplicationGroupsForCluster(ctx context.Context, clusterId string, tags map[string]string, logger *logrus.Entry) ([]*elasticacheReplicationGroup, []string, error) {
+	var replicationGroups []*elasticacheReplicationGroup
+	var subnetGroupNames []string
+	resourceInput := &resourcegroupstaggingapi.GetResourcesInput{
+		ResourceTypeFilters: aws.StringSlice([]string{resourceTypeElasticacheCluster}),
+		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
+	}
+	resourceTagMappings, err := getTaggedResources(ctx, r.taggingClient, resourceInput)
+	if err != nil {
+		return nil, nil, errors.WrapLog(err, "failed to describe cache clusters", logger)
+	}
+
+	for _, resourceTagMapping := range resourceTagMappings {
+		arn := aws.StringValue(resourceTagMapping.ResourceARN)
+		arnSplit := strings.Split(arn, ":")
+		cacheClusterId := arnSplit[len(arnSplit)-1]
+		cacheClusterInput := &elasticache.DescribeCacheClustersInput{
+			CacheClusterId: aws.String(cacheClusterId),
+		}
+		cacheClusters, err := describeCacheClusters(ctx, r.elasticacheClient, cacheClusterInput)
+		if err != nil {
+			return nil, nil, errors.WrapLog(err, "cannot get cacheCluster output", logger)
+		}
+		for _, cacheCluster := range cacheClusters {
+			rgLogger := logger.WithField("replicationGroup", cacheCluster.ReplicationGroupId)
+			if findReplicationGroup(replicationGroups, *cacheCluster.ReplicationGroupId) != nil {
+				rgLogger.Debugf("replication Group already exists in list (%s=%s)", *cacheCluster.ReplicationGroupId, clusterId)
+				break
+			}
+			replicationGroups = append(replicationGroups, &elasticacheReplicationGroup{
+				ID:           *cacheCluster.ReplicationGroupId,
+				Account:      accountFromARN(arn),
+				Tags:         convertAWSTagsToMap(resourceTagMapping.Tags),
+				CreationTime: cacheCluster.CacheClusterCreateTime,
+			})
+			subnetGroupNames = appendIfUnique(subnetGroupNames, *cacheCluster.CacheSubnetGroupName)
+		}
+	}
+	logger.Debugf("filtering complete, %d replicationGroups matched", len(replicationGroups))
+	return replicationGroups, subnetGroupNames, nil
+}
+
 func contains(arr []string, targetValue string) bool {
 	for _, element := range arr {
 		if element != "" && element == targetValue {
@@ -170,6 +421,20 @@ func contains(arr []string, targetValue string) bool {
 	return false
 }
 
+func findReplicationGroup(replicationGroups []*elasticacheReplicationGroup, id string) *elasticacheReplicationGroup {
+	for _, replicationGroup := range replicationGroups {
+		if replicationGroup.ID == id {
+			return replicationGroup
+		}
+	}
+	return nil
+}
+
+//elasticacheReportID Id of an elasticache resource without an arn in reports, prefixed with the region as the names are only unique within a region, e.g. eu-west-1:subnetgroup:my-group
+func elasticacheReportID(region string, kind string, name string) string {
+	return fmt.Sprintf("%s:%s:%s", region, kind, name)
+}
+
 func appendIfUnique(arr []string, targetValue string) []string {
 	if !contains(arr, targetValue) {
 		return append(arr, targetValue)
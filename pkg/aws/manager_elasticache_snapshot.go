@@ -1,7 +1,9 @@
 package aws
 
 import (
+	"context"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 
@@ -20,12 +22,21 @@ const (
 	resourceTypeElasticacheSnapshot = "elasticache:snapshot"
 )
 
-var _ ClusterResourceManager = &ElasticacheSnapshotManager{}
+//elasticacheSnapshot snapshot tagged for a cluster along with its description from the elasticache api
+type elasticacheSnapshot struct {
+	basicResource
+	Snapshot *elasticache.Snapshot
+}
+
+var _ PhasedClusterResourceManager = &ElasticacheSnapshotManager{}
+var _ ConfigurableClusterResourceManager = &ElasticacheSnapshotManager{}
 
 type ElasticacheSnapshotManager struct {
+	configurable
 	elasticacheClient elasticacheiface.ElastiCacheAPI
 	taggingClient     resourcegroupstaggingapiiface.ResourceGroupsTaggingAPIAPI
 	logger            *logrus.Entry
+	region            string
 }
 
 func NewDefaultElasticacheSnapshotManager(session *session.Session, logger *logrus.Entry) *ElasticacheSnapshotManager {
@@ -33,6 +44,7 @@ func NewDefaultElasticacheSnapshotManager(session *session.Session, logger *logr
 		elasticacheClient: elasticache.New(session),
 		taggingClient:     resourcegroupstaggingapi.New(session),
 		logger:            logger.WithField(loggingKeyManager, managerElasticacheSnapshot),
+		region:            regionFromSession(session),
 	}
 }
 
@@ -40,75 +52,158 @@ func (r *ElasticacheSnapshotManager) GetName() string {
 	return "AWS ElastiCache Snapshot Manager"
 }
 
-func (r *ElasticacheSnapshotManager) DeleteResourcesForCluster(clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
+func (r *ElasticacheSnapshotManager) GetType() ResourceManagerType {
+	return managerElasticacheSnapshot
+}
+
+func (r *ElasticacheSnapshotManager) GetDependencies() []ResourceManagerType {
+	return []ResourceManagerType{managerElasticache}
+}
+
+func (r *ElasticacheSnapshotManager) DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
 	logger := r.logger.WithFields(logrus.Fields{"clusterId": clusterId, "dryRun": dryRun})
 	logger.Debug("deleting resources for cluster")
 
+	snapshotsToDelete, err := r.getSnapshotsForCluster(ctx, clusterId, tags, logger)
+	if err != nil {
+		return nil, err
+	}
+	var reportItems []*clusterservice.ReportItem
+	deletePool := newWorkerPool(r.options.Concurrency)
+	for _, snapshot := range snapshotsToDelete {
+		snapshot := snapshot
+		snapshotLogger := r.logger.WithField(loggingKeySnapshot, snapshot.Name)
+		if !r.isTarget(snapshot.ARN) {
+			snapshotLogger.Debug("resource is not targeted, skipping")
+			continue
+		}
+		snapshotLogger.Debug("handling deletion for snapshot")
+
+		reportItem := &clusterservice.ReportItem{
+			ID:           snapshot.ARN,
+			Name:         snapshot.Name,
+			ResourceType: resourceTypeElasticacheSnapshot,
+			Region:       r.region,
+			Account:      accountFromARN(snapshot.ARN),
+			Manager:      string(managerElasticacheSnapshot),
+			Tags:         snapshot.Tags,
+			Action:       clusterservice.ActionDelete,
+			ActionStatus: clusterservice.ActionStatusInProgress,
+		}
+		reportItems = append(reportItems, reportItem)
+		if r.isProtected(reportItem, snapshot.creationTime()) {
+			snapshotLogger.Debug("resource is protected by the safety policy, skipping")
+			continue
+		}
+		if r.isRetained(reportItem) {
+			snapshotLogger.Debug("final snapshot is still retained, skipping")
+			continue
+		}
+		if dryRun {
+			snapshotLogger.Debug("dry run is enabled, skipping")
+			reportItem.ActionStatus = clusterservice.ActionStatusDryRun
+			continue
+		}
+		deletePool.Go(func() error {
+			deleteSnapshotInput := &elasticache.DeleteSnapshotInput{
+				SnapshotName: aws.String(snapshot.Name),
+			}
+			if _, err := r.elasticacheClient.DeleteSnapshotWithContext(ctx, deleteSnapshotInput); err != nil {
+				if awsErr, ok := err.(awserr.Error); ok {
+					if awsErr.Code() == elasticache.ErrCodeInvalidSnapshotStateFault {
+						snapshotLogger.Debug("snapshot is in a deleting state, ignoring error")
+						return nil
+					}
+					if awsErr.Code() == elasticache.ErrCodeSnapshotNotFoundFault {
+						snapshotLogger.Debug("snapshot is not found, assuming already removed or aws caching, ignoring error")
+						return nil
+					}
+				}
+				return failReportItem(reportItem, errors.WrapLog(err, "failed to delete snapshot", r.logger))
+			}
+			return nil
+		})
+	}
+	return reportItems, errors.Aggregate(deletePool.Wait())
+}
+
+//ListResourcesForCluster List elasticache snapshots for a specified cluster without modifying them
+func (r *ElasticacheSnapshotManager) ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+	logger := r.logger.WithField("clusterId", clusterId)
+	logger.Debug("listing resources for cluster")
+	snapshots, err := r.getSnapshotsForCluster(ctx, clusterId, tags, logger)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*clusterservice.Resource, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		resource := &clusterservice.Resource{
+			ID:           snapshot.ARN,
+			Name:         snapshot.Name,
+			ResourceType: resourceTypeElasticacheSnapshot,
+			Region:       r.region,
+			Account:      accountFromARN(snapshot.ARN),
+			Manager:      string(managerElasticacheSnapshot),
+			Tags:         snapshot.Tags,
+			State:        aws.StringValue(snapshot.Snapshot.SnapshotStatus),
+		}
+		resource.CreationTime = snapshot.creationTime()
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+//creationTime A snapshot is made up of a snapshot per cache node, the earliest is used as the creation time
+func (s *elasticacheSnapshot) creationTime() *time.Time {
+	var creationTime *time.Time
+	for _, nodeSnapshot := range s.Snapshot.NodeSnapshots {
+		if nodeSnapshot.SnapshotCreateTime == nil {
+			continue
+		}
+		if creationTime == nil || nodeSnapshot.SnapshotCreateTime.Before(*creationTime) {
+			creationTime = nodeSnapshot.SnapshotCreateTime
+		}
+	}
+	return creationTime
+}
+
+//getSnapshotsForCluster Get the elasticache snapshots tagged for a cluster, skipping snapshots the elasticache api can't find
+func (r *ElasticacheSnapshotManager) getSnapshotsForCluster(ctx context.Context, clusterId string, tags map[string]string, logger *logrus.Entry) ([]*elasticacheSnapshot, error) {
 	//collection of clusterID's for respective snapshots
-	var snapshotsToDelete []*basicResource
+	var snapshots []*elasticacheSnapshot
 
 	resourceInput := &resourcegroupstaggingapi.GetResourcesInput{
 		ResourceTypeFilters: aws.StringSlice([]string{resourceTypeElasticacheSnapshot}),
 		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
 	}
-	resourceOutput, err := r.taggingClient.GetResources(resourceInput)
+	resourceTagMappings, err := getTaggedResources(ctx, r.taggingClient, resourceInput)
 	if err != nil {
 		return nil, errors.WrapLog(err, "failed to get tagged snapshots", logger)
 	}
 	//convert response to standardised resource
-	for _, resourceTagMapping := range resourceOutput.ResourceTagMappingList {
+	for _, resourceTagMapping := range resourceTagMappings {
 		snapshotARN := aws.StringValue(resourceTagMapping.ResourceARN)
 		snapshotARNElements := strings.Split(snapshotARN, ":")
 		snapshotName := snapshotARNElements[len(snapshotARNElements)-1]
 		snapshotLogger := r.logger.WithField(loggingKeySnapshot, snapshotName)
-		describeSnapshotsOutput, err := r.elasticacheClient.DescribeSnapshots(&elasticache.DescribeSnapshotsInput{
+		describedSnapshots, err := describeCacheSnapshots(ctx, r.elasticacheClient, &elasticache.DescribeSnapshotsInput{
 			SnapshotName: aws.String(snapshotName),
 		})
 		if err != nil {
 			return nil, errors.WrapLog(err, "failed to get elasticache snapshot", r.logger)
 		}
-		if len(describeSnapshotsOutput.Snapshots) == 0 {
+		if len(describedSnapshots) == 0 {
 			snapshotLogger.Debug("no snapshot found, assuming caching issue in aws, skipping")
 			continue
 		}
-		snapshotsToDelete = append(snapshotsToDelete, &basicResource{
-			Name: snapshotName,
-			ARN:  snapshotARN,
+		snapshots = append(snapshots, &elasticacheSnapshot{
+			basicResource: basicResource{
+				Name: snapshotName,
+				ARN:  snapshotARN,
+				Tags: convertAWSTagsToMap(resourceTagMapping.Tags),
+			},
+			Snapshot: describedSnapshots[0],
 		})
 	}
-	var reportItems []*clusterservice.ReportItem
-	for _, snapshot := range snapshotsToDelete {
-		snapshotLogger := r.logger.WithField(loggingKeySnapshot, snapshot.Name)
-		snapshotLogger.Debug("handling deletion for snapshot")
-
-		reportItem := &clusterservice.ReportItem{
-			ID:           snapshot.ARN,
-			Name:         snapshot.Name,
-			Action:       clusterservice.ActionDelete,
-			ActionStatus: clusterservice.ActionStatusInProgress,
-		}
-		reportItems = append(reportItems, reportItem)
-		if dryRun {
-			snapshotLogger.Debug("dry run is enabled, skipping")
-			reportItem.ActionStatus = clusterservice.ActionStatusDryRun
-			continue
-		}
-		deleteSnapshotInput := &elasticache.DeleteSnapshotInput{
-			SnapshotName: aws.String(snapshot.Name),
-		}
-		if _, err := r.elasticacheClient.DeleteSnapshot(deleteSnapshotInput); err != nil {
-			if awsErr, ok := err.(awserr.Error); ok {
-				if awsErr.Code() == elasticache.ErrCodeInvalidSnapshotStateFault {
-					snapshotLogger.Debug("snapshot is in a deleting state, ignoring error")
-					continue
-				}
-				if awsErr.Code() == elasticache.ErrCodeSnapshotNotFoundFault {
-					snapshotLogger.Debug("snapshot is not found, assuming already removed or aws caching, ignoring error")
-					continue
-				}
-			}
-			return nil, errors.WrapLog(err, "failed to delete snapshot", r.logger)
-		}
-	}
-	return reportItems, nil
+	return snapshots, nil
 }
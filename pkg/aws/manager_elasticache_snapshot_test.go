@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/elasticache"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
 	"github.com/integr8ly/cluster-service/pkg/clusterservice"
@@ -43,7 +45,7 @@ func TestElasticacheSnapshotManager_DeleteResourcesForCluster(t *testing.T) {
 			fields: fields{
 				elasticacheClient: func() *elasticacheClientMock {
 					fakeClient, err := fakeElasticacheClient(func(c *elasticacheClientMock) error {
-						c.DeleteSnapshotFunc = func(in1 *elasticache.DeleteSnapshotInput) (output *elasticache.DeleteSnapshotOutput, e error) {
+						c.DeleteSnapshotWithContextFunc = func(ctx context.Context, in1 *elasticache.DeleteSnapshotInput, opts ...request.Option) (output *elasticache.DeleteSnapshotOutput, e error) {
 							return nil, errors.New("")
 						}
 						return nil
@@ -69,6 +71,18 @@ func TestElasticacheSnapshotManager_DeleteResourcesForCluster(t *testing.T) {
 				clusterId: fakeClusterId,
 				dryRun:    false,
 			},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeARN
+					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeElasticacheSnapshot
+					item.Manager = string(managerElasticacheSnapshot)
+					item.Tags = fakeResourceTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusFailed
+					item.StatusReason = "failed to delete snapshot: "
+				}),
+			},
 			wantErr: "failed to delete snapshot: ",
 		}, {
 			name: "pass when no report is returned if no snapshots deleted ",
@@ -84,7 +98,7 @@ func TestElasticacheSnapshotManager_DeleteResourcesForCluster(t *testing.T) {
 				},
 				taggingClient: func() *taggingClientMock {
 					fakeTaggingClient, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (output *resourcegroupstaggingapi.GetResourcesOutput, err error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (output *resourcegroupstaggingapi.GetResourcesOutput, err error) {
 							return nil, errors.New("")
 						}
 						return nil
@@ -133,6 +147,9 @@ func TestElasticacheSnapshotManager_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeARN
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeElasticacheSnapshot
+					item.Manager = string(managerElasticacheSnapshot)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusInProgress
 				}),
@@ -144,7 +161,7 @@ func TestElasticacheSnapshotManager_DeleteResourcesForCluster(t *testing.T) {
 			fields: fields{
 				elasticacheClient: func() *elasticacheClientMock {
 					fakeClient, err := fakeElasticacheClient(func(c *elasticacheClientMock) error {
-						c.DescribeSnapshotsFunc = func(in1 *elasticache.DescribeSnapshotsInput) (output *elasticache.DescribeSnapshotsOutput, err error) {
+						c.DescribeSnapshotsWithContextFunc = func(ctx context.Context, in1 *elasticache.DescribeSnapshotsInput, opts ...request.Option) (output *elasticache.DescribeSnapshotsOutput, err error) {
 							return &elasticache.DescribeSnapshotsOutput{
 								Snapshots: []*elasticache.Snapshot{
 									fakeElasticacheSnapshot(),
@@ -177,12 +194,15 @@ func TestElasticacheSnapshotManager_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeARN
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeElasticacheSnapshot
+					item.Manager = string(managerElasticacheSnapshot)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusDryRun
 				}),
 			},
 			wantFn: func(mock *elasticacheClientMock) error {
-				if len(mock.DeleteSnapshotCalls()) != 0 {
+				if len(mock.DeleteSnapshotWithContextCalls()) != 0 {
 					return errors.New("delete snapshot call count should be 0 as dry run is true")
 				}
 				return nil
@@ -198,7 +218,7 @@ func TestElasticacheSnapshotManager_DeleteResourcesForCluster(t *testing.T) {
 				taggingClient:     tt.fields.taggingClient(),
 				logger:            tt.fields.logger,
 			}
-			got, err := r.DeleteResourcesForCluster(tt.args.clusterId, nil, tt.args.dryRun)
+			got, err := r.DeleteResourcesForCluster(context.TODO(), tt.args.clusterId, nil, tt.args.dryRun)
 			if tt.wantErr != "" && err.Error() != tt.wantErr {
 				t.Errorf("DeleteResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -1,18 +1,21 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/elasticache"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
 	"github.com/integr8ly/cluster-service/pkg/clusterservice"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
-	//"reflect"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestElasticacheEngine_DeleteResourcesForCluster(t *testing.T) {
@@ -56,7 +59,7 @@ func TestElasticacheEngine_DeleteResourcesForCluster(t *testing.T) {
 				},
 				taggingClient: func() *taggingClientMock {
 					fakeTaggingClient, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
 							return nil, errors.New("")
 						}
 						return nil
@@ -79,7 +82,7 @@ func TestElasticacheEngine_DeleteResourcesForCluster(t *testing.T) {
 			fields: fields{
 				elasticacheClient: func() *elasticacheClientMock {
 					fakeClient, err := fakeElasticacheClient(func(c *elasticacheClientMock) error {
-						c.DescribeCacheClustersFunc = func(in1 *elasticache.DescribeCacheClustersInput) (*elasticache.DescribeCacheClustersOutput, error) {
+						c.DescribeCacheClustersWithContextFunc = func(ctx context.Context, in1 *elasticache.DescribeCacheClustersInput, opts ...request.Option) (*elasticache.DescribeCacheClustersOutput, error) {
 							return nil, errors.New("")
 						}
 						return nil
@@ -110,7 +113,7 @@ func TestElasticacheEngine_DeleteResourcesForCluster(t *testing.T) {
 			fields: fields{
 				elasticacheClient: func() *elasticacheClientMock {
 					fakeClient, err := fakeElasticacheClient(func(c *elasticacheClientMock) error {
-						c.DescribeReplicationGroupsFunc = func(in1 *elasticache.DescribeReplicationGroupsInput) (output *elasticache.DescribeReplicationGroupsOutput, e error) {
+						c.DescribeReplicationGroupsWithContextFunc = func(ctx context.Context, in1 *elasticache.DescribeReplicationGroupsInput, opts ...request.Option) (output *elasticache.DescribeReplicationGroupsOutput, e error) {
 							return nil, errors.New("")
 						}
 						return nil
@@ -135,13 +138,35 @@ func TestElasticacheEngine_DeleteResourcesForCluster(t *testing.T) {
 				clusterId: fakeClusterId,
 				dryRun:    false,
 			},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeElasticacheReplicationGroupID
+					item.Region = fakeRegion
+					item.Name = fakeElasticacheClientReplicationGroupId
+					item.ResourceType = resourceTypeElasticacheReplicationGroup
+					item.Manager = string(managerElasticache)
+					item.Tags = fakeResourceTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusFailed
+					item.StatusReason = "cannot describe replicationGroups: "
+				}),
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeElasticacheSubnetGroupID
+					item.Region = fakeRegion
+					item.Name = fakeElasticacheSubnetGroupNameValue
+					item.ResourceType = resourceTypeElasticacheSubnetGroup
+					item.Manager = string(managerElasticache)
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusComplete
+				}),
+			},
 			wantErr: "cannot describe replicationGroups: ",
 		}, {
 			name: "error when delete replicationGroups fail",
 			fields: fields{
 				elasticacheClient: func() *elasticacheClientMock {
 					fakeClient, err := fakeElasticacheClient(func(c *elasticacheClientMock) error {
-						c.DeleteReplicationGroupFunc = func(in1 *elasticache.DeleteReplicationGroupInput) (output *elasticache.DeleteReplicationGroupOutput, err error) {
+						c.DeleteReplicationGroupWithContextFunc = func(ctx context.Context, in1 *elasticache.DeleteReplicationGroupInput, opts ...request.Option) (output *elasticache.DeleteReplicationGroupOutput, err error) {
 							return nil, errors.New("")
 						}
 						return nil
@@ -166,13 +191,35 @@ func TestElasticacheEngine_DeleteResourcesForCluster(t *testing.T) {
 				clusterId: fakeClusterId,
 				dryRun:    false,
 			},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeElasticacheReplicationGroupID
+					item.Region = fakeRegion
+					item.Name = fakeElasticacheClientReplicationGroupId
+					item.ResourceType = resourceTypeElasticacheReplicationGroup
+					item.Manager = string(managerElasticache)
+					item.Tags = fakeResourceTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusFailed
+					item.StatusReason = "failed to delete elasticache replication group: "
+				}),
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeElasticacheSubnetGroupID
+					item.Region = fakeRegion
+					item.Name = fakeElasticacheSubnetGroupNameValue
+					item.ResourceType = resourceTypeElasticacheSubnetGroup
+					item.Manager = string(managerElasticache)
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusComplete
+				}),
+			},
 			wantErr: "failed to delete elasticache replication group: ",
 		}, {
 			name: "error when delete subnet groups fail",
 			fields: fields{
 				elasticacheClient: func() *elasticacheClientMock {
 					fakeClient, err := fakeElasticacheClient(func(c *elasticacheClientMock) error {
-						c.DeleteCacheSubnetGroupFunc = func(in1 *elasticache.DeleteCacheSubnetGroupInput) (output *elasticache.DeleteCacheSubnetGroupOutput, err error) {
+						c.DeleteCacheSubnetGroupWithContextFunc = func(ctx context.Context, in1 *elasticache.DeleteCacheSubnetGroupInput, opts ...request.Option) (output *elasticache.DeleteCacheSubnetGroupOutput, err error) {
 							return nil, errors.New("")
 						}
 						return nil
@@ -197,6 +244,29 @@ func TestElasticacheEngine_DeleteResourcesForCluster(t *testing.T) {
 				clusterId: fakeClusterId,
 				dryRun:    false,
 			},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeElasticacheReplicationGroupID
+					item.Region = fakeRegion
+					item.Name = fakeElasticacheClientReplicationGroupId
+					item.ResourceType = resourceTypeElasticacheReplicationGroup
+					item.Manager = string(managerElasticache)
+					item.Tags = fakeResourceTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.NextCheckAfter = elasticacheReplicationGroupDeletionCheckInterval
+				}),
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeElasticacheSubnetGroupID
+					item.Region = fakeRegion
+					item.Name = fakeElasticacheSubnetGroupNameValue
+					item.ResourceType = resourceTypeElasticacheSubnetGroup
+					item.Manager = string(managerElasticache)
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusFailed
+					item.StatusReason = "failed to delete cache subnet group: "
+				}),
+			},
 			wantErr: "failed to delete cache subnet group: ",
 		}, {
 			name: "pass when no report is returned  if no replicationgroups deleted ",
@@ -212,7 +282,7 @@ func TestElasticacheEngine_DeleteResourcesForCluster(t *testing.T) {
 				},
 				taggingClient: func() *taggingClientMock {
 					fakeTaggingClient, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (output *resourcegroupstaggingapi.GetResourcesOutput, err error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (output *resourcegroupstaggingapi.GetResourcesOutput, err error) {
 							return nil, errors.New("")
 						}
 						return nil
@@ -259,14 +329,22 @@ func TestElasticacheEngine_DeleteResourcesForCluster(t *testing.T) {
 			},
 			want: []*clusterservice.ReportItem{
 				mockReportItem(func(item *clusterservice.ReportItem) {
-					item.ID = fakeElasticacheClientReplicationGroupId
-					item.Name = fakeElasticacheClientName
+					item.ID = fakeElasticacheReplicationGroupID
+					item.Region = fakeRegion
+					item.Name = fakeElasticacheClientReplicationGroupId
+					item.ResourceType = resourceTypeElasticacheReplicationGroup
+					item.Manager = string(managerElasticache)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.NextCheckAfter = elasticacheReplicationGroupDeletionCheckInterval
 				}),
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeElasticacheSubnetGroupID
-					item.Name = fakeElasticacheSubnetGroupName
+					item.Region = fakeRegion
+					item.Name = fakeElasticacheSubnetGroupNameValue
+					item.ResourceType = resourceTypeElasticacheSubnetGroup
+					item.Manager = string(managerElasticache)
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusComplete
 				}),
@@ -279,7 +357,7 @@ func TestElasticacheEngine_DeleteResourcesForCluster(t *testing.T) {
 					fakeClient, err := fakeElasticacheClient(func(c *elasticacheClientMock) error {
 						fakeReplicationGroup := fakeElasticacheReplicationGroup()
 						fakeReplicationGroup.Status = aws.String(statusDeleting)
-						c.DescribeReplicationGroupsFunc = func(in1 *elasticache.DescribeReplicationGroupsInput) (output *elasticache.DescribeReplicationGroupsOutput, err error) {
+						c.DescribeReplicationGroupsWithContextFunc = func(ctx context.Context, in1 *elasticache.DescribeReplicationGroupsInput, opts ...request.Option) (output *elasticache.DescribeReplicationGroupsOutput, err error) {
 							return &elasticache.DescribeReplicationGroupsOutput{
 								ReplicationGroups: []*elasticache.ReplicationGroup{
 									fakeReplicationGroup,
@@ -310,20 +388,29 @@ func TestElasticacheEngine_DeleteResourcesForCluster(t *testing.T) {
 			},
 			want: []*clusterservice.ReportItem{
 				mockReportItem(func(item *clusterservice.ReportItem) {
-					item.ID = fakeElasticacheClientReplicationGroupId
-					item.Name = fakeElasticacheClientName
+					item.ID = fakeElasticacheReplicationGroupID
+					item.Region = fakeRegion
+					item.Name = fakeElasticacheClientReplicationGroupId
+					item.ResourceType = resourceTypeElasticacheReplicationGroup
+					item.Manager = string(managerElasticache)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.NextCheckAfter = elasticacheReplicationGroupDeletionCheckInterval
+					item.StatusReason = "deletion already in progress"
 				}),
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeElasticacheSubnetGroupID
-					item.Name = fakeElasticacheSubnetGroupName
+					item.Region = fakeRegion
+					item.Name = fakeElasticacheSubnetGroupNameValue
+					item.ResourceType = resourceTypeElasticacheSubnetGroup
+					item.Manager = string(managerElasticache)
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusComplete
 				}),
 			},
 			wantFn: func(mock *elasticacheClientMock) error {
-				if len(mock.DeleteReplicationGroupCalls()) != 0 {
+				if len(mock.DeleteReplicationGroupWithContextCalls()) != 0 {
 					return errors.New("delete replication group call count should be 0")
 				}
 				return nil
@@ -333,7 +420,7 @@ func TestElasticacheEngine_DeleteResourcesForCluster(t *testing.T) {
 			fields: fields{
 				elasticacheClient: func() *elasticacheClientMock {
 					fakeClient, err := fakeElasticacheClient(func(c *elasticacheClientMock) error {
-						c.DescribeReplicationGroupsFunc = func(in1 *elasticache.DescribeReplicationGroupsInput) (output *elasticache.DescribeReplicationGroupsOutput, err error) {
+						c.DescribeReplicationGroupsWithContextFunc = func(ctx context.Context, in1 *elasticache.DescribeReplicationGroupsInput, opts ...request.Option) (output *elasticache.DescribeReplicationGroupsOutput, err error) {
 							return &elasticache.DescribeReplicationGroupsOutput{
 								ReplicationGroups: []*elasticache.ReplicationGroup{
 									fakeElasticacheReplicationGroup(),
@@ -364,20 +451,27 @@ func TestElasticacheEngine_DeleteResourcesForCluster(t *testing.T) {
 			},
 			want: []*clusterservice.ReportItem{
 				mockReportItem(func(item *clusterservice.ReportItem) {
-					item.ID = fakeElasticacheClientReplicationGroupId
-					item.Name = fakeElasticacheClientName
+					item.ID = fakeElasticacheReplicationGroupID
+					item.Region = fakeRegion
+					item.Name = fakeElasticacheClientReplicationGroupId
+					item.ResourceType = resourceTypeElasticacheReplicationGroup
+					item.Manager = string(managerElasticache)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusDryRun
 				}),
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeElasticacheSubnetGroupID
-					item.Name = fakeElasticacheSubnetGroupName
+					item.Region = fakeRegion
+					item.Name = fakeElasticacheSubnetGroupNameValue
+					item.ResourceType = resourceTypeElasticacheSubnetGroup
+					item.Manager = string(managerElasticache)
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusDryRun
 				}),
 			},
 			wantFn: func(mock *elasticacheClientMock) error {
-				if len(mock.DeleteReplicationGroupCalls()) != 0 {
+				if len(mock.DeleteReplicationGroupWithContextCalls()) != 0 {
 					return errors.New("delete replication group call count should be 0 as dry run is true")
 				}
 				return nil
@@ -387,7 +481,7 @@ func TestElasticacheEngine_DeleteResourcesForCluster(t *testing.T) {
 			fields: fields{
 				elasticacheClient: func() *elasticacheClientMock {
 					fakeClient, err := fakeElasticacheClient(func(c *elasticacheClientMock) error {
-						c.DeleteCacheSubnetGroupFunc = func(in1 *elasticache.DeleteCacheSubnetGroupInput) (out *elasticache.DeleteCacheSubnetGroupOutput, err error) {
+						c.DeleteCacheSubnetGroupWithContextFunc = func(ctx context.Context, in1 *elasticache.DeleteCacheSubnetGroupInput, opts ...request.Option) (out *elasticache.DeleteCacheSubnetGroupOutput, err error) {
 							errorMsg := "cache subnet group is still in use"
 							return nil, awserr.New("CacheSubnetGroupInUse", errorMsg, errors.New(errorMsg))
 						}
@@ -415,16 +509,25 @@ func TestElasticacheEngine_DeleteResourcesForCluster(t *testing.T) {
 			},
 			want: []*clusterservice.ReportItem{
 				mockReportItem(func(item *clusterservice.ReportItem) {
-					item.ID = fakeElasticacheClientReplicationGroupId
-					item.Name = fakeElasticacheClientName
+					item.ID = fakeElasticacheReplicationGroupID
+					item.Region = fakeRegion
+					item.Name = fakeElasticacheClientReplicationGroupId
+					item.ResourceType = resourceTypeElasticacheReplicationGroup
+					item.Manager = string(managerElasticache)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.NextCheckAfter = elasticacheReplicationGroupDeletionCheckInterval
 				}),
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeElasticacheSubnetGroupID
-					item.Name = fakeElasticacheSubnetGroupName
+					item.Region = fakeRegion
+					item.Name = fakeElasticacheSubnetGroupNameValue
+					item.ResourceType = resourceTypeElasticacheSubnetGroup
+					item.Manager = string(managerElasticache)
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusSkipped
+					item.StatusReason = "cache subnet group is in use"
 				}),
 			},
 		},
@@ -434,11 +537,12 @@ func TestElasticacheEngine_DeleteResourcesForCluster(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			fakeClient := tt.fields.elasticacheClient()
 			r := &ElasticacheManager{
+				region:            fakeRegion,
 				elasticacheClient: fakeClient,
 				taggingClient:     tt.fields.taggingClient(),
 				logger:            tt.fields.logger,
 			}
-			got, err := r.DeleteResourcesForCluster(tt.args.clusterId, nil, tt.args.dryRun)
+			got, err := r.DeleteResourcesForCluster(context.TODO(), tt.args.clusterId, nil, tt.args.dryRun)
 			if tt.wantErr != "" && err.Error() != tt.wantErr {
 				t.Errorf("DeleteResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -467,6 +571,141 @@ func buildReportItemsString(reportItems []*clusterservice.ReportItem) string {
 	return result
 }
 
+func TestElasticacheEngine_DeleteResourcesForClusterWithFinalSnapshot(t *testing.T) {
+	fakeLogger, err := fakeLogger(func(l *logrus.Entry) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeCacheClusterARN := "arn:aws:elasticache:eu-west-1:123456789012:cluster:testCacheCluster"
+	fakeCreationTime := time.Date(2021, 3, 4, 10, 30, 0, 0, time.UTC)
+	fakeFinalSnapshot := fmt.Sprintf("%s-final-20210304103000", fakeElasticacheClientReplicationGroupId)
+	fakeTaggingClient, err := fakeTaggingClient(func(c *taggingClientMock) error {
+		c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+			return &resourcegroupstaggingapi.GetResourcesOutput{
+				ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
+					fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
+						mapping.ResourceARN = aws.String(fakeCacheClusterARN)
+					}),
+				},
+			}, nil
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fakeSnapshotARN := fmt.Sprintf("arn:aws:elasticache:eu-west-1:123456789012:snapshot:%s", fakeFinalSnapshot)
+	fakeNow := time.Date(2021, 3, 5, 12, 0, 0, 0, time.UTC)
+	fakeRetainUntil := "2021-03-06T12:00:00Z"
+
+	tests := []struct {
+		name        string
+		addTagsErr  error
+		wantPending []string
+	}{
+		{
+			name:        "final snapshot is taken by the deletion and tagged for retention once it exists",
+			wantPending: []string{},
+		},
+		{
+			name:        "final snapshot which can't be tagged is tried again by the next run",
+			addTagsErr:  errors.New("denied"),
+			wantPending: []string{pendingFinalSnapshotPrefix + fakeFinalSnapshot + pendingFinalSnapshotSeparator + fakeRetainUntil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshotCreated := false
+			fakeClient, err := fakeElasticacheClient(func(c *elasticacheClientMock) error {
+				c.DescribeCacheClustersWithContextFunc = func(ctx context.Context, in1 *elasticache.DescribeCacheClustersInput, opts ...request.Option) (*elasticache.DescribeCacheClustersOutput, error) {
+					cacheCluster := fakeElasticacheCacheCluster()
+					cacheCluster.CacheClusterCreateTime = aws.Time(fakeCreationTime)
+					return &elasticache.DescribeCacheClustersOutput{CacheClusters: []*elasticache.CacheCluster{cacheCluster}}, nil
+				}
+				c.DescribeReplicationGroupsWithContextFunc = func(ctx context.Context, in1 *elasticache.DescribeReplicationGroupsInput, opts ...request.Option) (*elasticache.DescribeReplicationGroupsOutput, error) {
+					replicationGroup := fakeElasticacheReplicationGroup()
+					if snapshotCreated {
+						replicationGroup.Status = aws.String(statusDeleting)
+					}
+					return &elasticache.DescribeReplicationGroupsOutput{ReplicationGroups: []*elasticache.ReplicationGroup{replicationGroup}}, nil
+				}
+				c.DescribeSnapshotsWithContextFunc = func(ctx context.Context, in1 *elasticache.DescribeSnapshotsInput, opts ...request.Option) (*elasticache.DescribeSnapshotsOutput, error) {
+					if !snapshotCreated {
+						return nil, awserr.New(elasticache.ErrCodeSnapshotNotFoundFault, "not found", nil)
+					}
+					snapshot := fakeElasticacheSnapshot()
+					snapshot.SnapshotName = aws.String(fakeFinalSnapshot)
+					snapshot.ARN = aws.String(fakeSnapshotARN)
+					return &elasticache.DescribeSnapshotsOutput{Snapshots: []*elasticache.Snapshot{snapshot}}, nil
+				}
+				c.AddTagsToResourceWithContextFunc = func(ctx context.Context, in1 *elasticache.AddTagsToResourceInput, opts ...request.Option) (*elasticache.TagListMessage, error) {
+					return &elasticache.TagListMessage{}, tt.addTagsErr
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := &ElasticacheManager{
+				region:            fakeRegion,
+				configurable:      configurable{options: ManagerOptions{FinalSnapshot: &FinalSnapshotPolicy{Retention: 24 * time.Hour, now: func() time.Time { return fakeNow }}}},
+				elasticacheClient: fakeClient,
+				taggingClient:     fakeTaggingClient,
+				logger:            fakeLogger,
+			}
+			got, err := r.DeleteResourcesForCluster(context.TODO(), fakeClusterID, nil, false)
+			if err != nil {
+				t.Fatalf("DeleteResourcesForCluster() unexpected error = %v", err)
+			}
+			if len(got) == 0 || got[0].FinalSnapshot != fakeFinalSnapshot {
+				t.Fatalf("DeleteResourcesForCluster() got = %s, want final snapshot %s", buildReportItemsString(got), fakeFinalSnapshot)
+			}
+			deleteCalls := fakeClient.DeleteReplicationGroupWithContextCalls()
+			if len(deleteCalls) != 1 || aws.StringValue(deleteCalls[0].DeleteReplicationGroupInput.FinalSnapshotIdentifier) != fakeFinalSnapshot {
+				t.Fatalf("delete replication group should be called once with final snapshot identifier %s", fakeFinalSnapshot)
+			}
+			if len(fakeClient.AddTagsToResourceWithContextCalls()) != 0 {
+				t.Fatal("final snapshot should not be tagged before it exists")
+			}
+			//the snapshot exists once the deletion is underway, the restored state stands in for a new process
+			//which tags it with the retention of the first run, even though it doesn't take final snapshots itself
+			snapshotCreated = true
+			next := &ElasticacheManager{
+				region:            fakeRegion,
+				elasticacheClient: fakeClient,
+				taggingClient:     fakeTaggingClient,
+				logger:            fakeLogger,
+			}
+			next.RestorePendingResources(r.GetPendingResources())
+			if _, err := next.DeleteResourcesForCluster(context.TODO(), fakeClusterID, nil, false); err != nil {
+				t.Fatalf("DeleteResourcesForCluster() unexpected error = %v", err)
+			}
+			addTagsCalls := fakeClient.AddTagsToResourceWithContextCalls()
+			if len(addTagsCalls) != 1 {
+				t.Fatalf("add tags to resource call count should be 1, got %d", len(addTagsCalls))
+			}
+			if aws.StringValue(addTagsCalls[0].AddTagsToResourceInput.ResourceName) != fakeSnapshotARN {
+				t.Errorf("final snapshot tagged as %s, want %s", aws.StringValue(addTagsCalls[0].AddTagsToResourceInput.ResourceName), fakeSnapshotARN)
+			}
+			wantTags := map[string]string{TagKeyRetainUntil: fakeRetainUntil, tagKeyClusterId: fakeClusterID}
+			for _, tag := range addTagsCalls[0].AddTagsToResourceInput.Tags {
+				if want, ok := wantTags[aws.StringValue(tag.Key)]; !ok || want != aws.StringValue(tag.Value) {
+					t.Errorf("final snapshot tagged with unexpected tag %s=%s", aws.StringValue(tag.Key), aws.StringValue(tag.Value))
+				}
+			}
+			if len(addTagsCalls[0].AddTagsToResourceInput.Tags) != len(wantTags) {
+				t.Errorf("final snapshot should be tagged with %s and the cluster id", TagKeyRetainUntil)
+			}
+			if pending := next.GetPendingResources(); !reflect.DeepEqual(pending, tt.wantPending) {
+				t.Errorf("GetPendingResources() got = %v, want %v", pending, tt.wantPending)
+			}
+		})
+	}
+}
+
 // test for the deletion of cache subnet groups which realistically only happens
 // when DeleteResourcesForCluster() is called several times (watch mode)
 // On the first attempt, a CacheSubnetGroupInUser error will be thrown so the report item status will be ActionStatusSkipped
@@ -486,7 +725,7 @@ func TestElasticacheEngine_DeleteSubnetGroupsAcrossMultipleAttempts(t *testing.T
 	deleteResourcesForClusterCallCount := 0
 
 	fakeClient, err := fakeElasticacheClient(func(c *elasticacheClientMock) error {
-		c.DescribeReplicationGroupsFunc = func(in1 *elasticache.DescribeReplicationGroupsInput) (output *elasticache.DescribeReplicationGroupsOutput, err error) {
+		c.DescribeReplicationGroupsWithContextFunc = func(ctx context.Context, in1 *elasticache.DescribeReplicationGroupsInput, opts ...request.Option) (output *elasticache.DescribeReplicationGroupsOutput, err error) {
 			if deleteResourcesForClusterCallCount > 0 {
 				// it's been called before this time return an empty result
 				return &elasticache.DescribeReplicationGroupsOutput{
@@ -499,7 +738,7 @@ func TestElasticacheEngine_DeleteSubnetGroupsAcrossMultipleAttempts(t *testing.T
 				},
 			}, nil
 		}
-		c.DeleteCacheSubnetGroupFunc = func(in1 *elasticache.DeleteCacheSubnetGroupInput) (out *elasticache.DeleteCacheSubnetGroupOutput, err error) {
+		c.DeleteCacheSubnetGroupWithContextFunc = func(ctx context.Context, in1 *elasticache.DeleteCacheSubnetGroupInput, opts ...request.Option) (out *elasticache.DeleteCacheSubnetGroupOutput, err error) {
 			if deleteResourcesForClusterCallCount > 0 {
 				// DeleteResourcesForCluster has been called more than once,
 				// this time set the replication group status as deleting
@@ -517,6 +756,7 @@ func TestElasticacheEngine_DeleteSubnetGroupsAcrossMultipleAttempts(t *testing.T
 	}
 
 	manager := &ElasticacheManager{
+		region:            fakeRegion,
 		elasticacheClient: fakeClient,
 		taggingClient:     fakeTaggingClient,
 		logger:            fakeLogger,
@@ -529,34 +769,51 @@ func TestElasticacheEngine_DeleteSubnetGroupsAcrossMultipleAttempts(t *testing.T
 		{
 			wantReport: []*clusterservice.ReportItem{
 				mockReportItem(func(item *clusterservice.ReportItem) {
-					item.ID = fakeElasticacheClientReplicationGroupId
-					item.Name = fakeElasticacheClientName
+					item.ID = fakeElasticacheReplicationGroupID
+					item.Region = fakeRegion
+					item.Name = fakeElasticacheClientReplicationGroupId
+					item.ResourceType = resourceTypeElasticacheReplicationGroup
+					item.Manager = string(managerElasticache)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.NextCheckAfter = elasticacheReplicationGroupDeletionCheckInterval
 
 				}),
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeElasticacheSubnetGroupID
-					item.Name = fakeElasticacheSubnetGroupName
+					item.Region = fakeRegion
+					item.Name = fakeElasticacheSubnetGroupNameValue
+					item.ResourceType = resourceTypeElasticacheSubnetGroup
+					item.Manager = string(managerElasticache)
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusSkipped
+					item.StatusReason = "cache subnet group is in use"
 				}),
 			},
 			wantSubnetGroupsToDeleteLength: 1,
 		}, {
 			wantReport: []*clusterservice.ReportItem{
 				mockReportItem(func(item *clusterservice.ReportItem) {
-					item.ID = fakeElasticacheClientReplicationGroupId
-					item.Name = fakeElasticacheClientName
+					item.ID = fakeElasticacheReplicationGroupID
+					item.Region = fakeRegion
+					item.Name = fakeElasticacheClientReplicationGroupId
+					item.ResourceType = resourceTypeElasticacheReplicationGroup
+					item.Manager = string(managerElasticache)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.NextCheckAfter = elasticacheReplicationGroupDeletionCheckInterval
 
 				}),
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusComplete
 					item.ID = fakeElasticacheSubnetGroupID
-					item.Name = fakeElasticacheSubnetGroupName
+					item.Region = fakeRegion
+					item.Name = fakeElasticacheSubnetGroupNameValue
+					item.ResourceType = resourceTypeElasticacheSubnetGroup
+					item.Manager = string(managerElasticache)
 				}),
 			},
 			wantSubnetGroupsToDeleteLength: 0,
@@ -564,7 +821,7 @@ func TestElasticacheEngine_DeleteSubnetGroupsAcrossMultipleAttempts(t *testing.T
 	}
 
 	for i, attempt := range attempts {
-		gotReport, err := manager.DeleteResourcesForCluster(fakeClusterID, nil, false)
+		gotReport, err := manager.DeleteResourcesForCluster(context.TODO(), fakeClusterID, nil, false)
 
 		if err != nil {
 			t.Error(err)
@@ -581,3 +838,126 @@ func TestElasticacheEngine_DeleteSubnetGroupsAcrossMultipleAttempts(t *testing.T
 		}
 	}
 }
+
+func TestElasticacheEngine_ListResourcesForCluster(t *testing.T) {
+	fakeLogger, err := fakeLogger(func(l *logrus.Entry) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeTaggingClient, err := fakeTaggingClient(func(c *taggingClientMock) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeCreationTime := time.Date(2021, 3, 4, 10, 30, 0, 0, time.UTC)
+	fakeClient, err := fakeElasticacheClient(func(c *elasticacheClientMock) error {
+		c.DescribeReplicationGroupsWithContextFunc = func(ctx context.Context, in1 *elasticache.DescribeReplicationGroupsInput, opts ...request.Option) (*elasticache.DescribeReplicationGroupsOutput, error) {
+			replicationGroup := fakeElasticacheReplicationGroup()
+			replicationGroup.ReplicationGroupCreateTime = aws.Time(fakeCreationTime)
+			return &elasticache.DescribeReplicationGroupsOutput{
+				ReplicationGroups: []*elasticache.ReplicationGroup{replicationGroup},
+			}, nil
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := &ElasticacheManager{
+		region:            fakeRegion,
+		elasticacheClient: fakeClient,
+		taggingClient:     fakeTaggingClient,
+		logger:            fakeLogger,
+	}
+
+	got, err := manager.ListResourcesForCluster(context.TODO(), fakeClusterID, nil)
+	if err != nil {
+		t.Fatalf("ListResourcesForCluster() unexpected error = %v", err)
+	}
+	want := []*clusterservice.Resource{
+		{
+			ID:           fakeElasticacheReplicationGroupID,
+			Name:         fakeElasticacheClientReplicationGroupId,
+			ResourceType: resourceTypeElasticacheReplicationGroup,
+			Region:       fakeRegion,
+			Manager:      string(managerElasticache),
+			Tags:         fakeResourceTags(),
+			State:        fakeElasticacheClientStatusAvailable,
+			CreationTime: aws.Time(fakeCreationTime),
+		},
+		{
+			ID:           fakeElasticacheSubnetGroupID,
+			Name:         fakeElasticacheSubnetGroupNameValue,
+			ResourceType: resourceTypeElasticacheSubnetGroup,
+			Region:       fakeRegion,
+			Manager:      string(managerElasticache),
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListResourcesForCluster() got = %v, want %v", got, want)
+	}
+	if len(fakeClient.DeleteReplicationGroupWithContextCalls()) != 0 || len(fakeClient.DeleteCacheSubnetGroupWithContextCalls()) != 0 {
+		t.Error("ListResourcesForCluster() should not delete any resources")
+	}
+	//listing must not queue subnet groups for deletion on later delete runs
+	if len(manager.subnetGroupsToDelete) != 0 {
+		t.Errorf("ListResourcesForCluster() subnetGroupsToDelete got = %v, want empty", manager.subnetGroupsToDelete)
+	}
+}
+
+func TestElasticacheEngine_MergeReportsOfRegions(t *testing.T) {
+	fakeLogger, err := fakeLogger(func(l *logrus.Entry) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	//the same names are used in both regions, as clusters of the same name in different regions do
+	deleteInRegion := func(region string) []*clusterservice.ReportItem {
+		fakeClient, err := fakeElasticacheClient(func(c *elasticacheClientMock) error {
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		fakeTaggingClient, err := fakeTaggingClient(func(c *taggingClientMock) error {
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		manager := &ElasticacheManager{
+			region:            region,
+			elasticacheClient: fakeClient,
+			taggingClient:     fakeTaggingClient,
+			logger:            fakeLogger,
+		}
+		reportItems, err := manager.DeleteResourcesForCluster(context.TODO(), fakeClusterID, nil, true)
+		if err != nil {
+			t.Fatalf("DeleteResourcesForCluster() unexpected error = %v", err)
+		}
+		return reportItems
+	}
+	report := &clusterservice.Report{Items: append(deleteInRegion(fakeRegion), deleteInRegion("us-east-1")...)}
+	ids := map[string]bool{}
+	for _, item := range report.Items {
+		ids[item.ID] = true
+	}
+	if len(ids) != 4 {
+		t.Fatalf("DeleteResourcesForCluster() ids of both regions collide, got = %v", buildReportItemsString(report.Items))
+	}
+	//the resources are gone from the first region, so only the second region reports them
+	report.MergeForward(&clusterservice.Report{Items: deleteInRegion("us-east-1")})
+	for _, item := range report.Items {
+		wantStatus := clusterservice.ActionStatusDryRun
+		if item.Region == fakeRegion {
+			wantStatus = clusterservice.ActionStatusComplete
+		}
+		if item.ActionStatus != wantStatus {
+			t.Errorf("MergeForward() %s status = %s, want %s", item.ID, item.ActionStatus, wantStatus)
+		}
+	}
+}
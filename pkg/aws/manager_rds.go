@@ -1,7 +1,12 @@
 package aws
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/rds"
 	"github.com/integr8ly/cluster-service/pkg/clusterservice"
@@ -11,19 +16,28 @@ import (
 
 const (
 	loggingKeyDatabase = "database-id"
+
+	resourceTypeRDSInstance = "rds:db"
+
+	//rdsInstanceDeletionCheckInterval Deleting an rds instance usually takes around 10 minutes, there is no point checking on it much sooner
+	rdsInstanceDeletionCheckInterval = 5 * time.Minute
 )
 
-var _ ClusterResourceManager = &RDSInstanceManager{}
+var _ PhasedClusterResourceManager = &RDSInstanceManager{}
+var _ ConfigurableClusterResourceManager = &RDSInstanceManager{}
 
 type RDSInstanceManager struct {
+	configurable
 	rdsClient rdsClient
 	logger    *logrus.Entry
+	region    string
 }
 
 func NewDefaultRDSInstanceManager(session *session.Session, logger *logrus.Entry) *RDSInstanceManager {
 	return &RDSInstanceManager{
 		rdsClient: rds.New(session),
 		logger:    logger.WithField("engine", managerRDS),
+		region:    regionFromSession(session),
 	}
 }
 
@@ -31,24 +45,182 @@ func (r *RDSInstanceManager) GetName() string {
 	return "AWS RDS Manager"
 }
 
+func (r *RDSInstanceManager) GetType() ResourceManagerType {
+	return managerRDS
+}
+
+func (r *RDSInstanceManager) GetDependencies() []ResourceManagerType {
+	return nil
+}
+
 //Delete all RDS resources for a specified cluster
-func (r *RDSInstanceManager) DeleteResourcesForCluster(clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
+func (r *RDSInstanceManager) DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
 	r.logger.Debug("deleting resources for cluster")
+	databasesToDelete, databaseTags, err := r.getDatabasesForCluster(ctx, clusterId, tags)
+	if err != nil {
+		return nil, err
+	}
+	reportItems := make([]*clusterservice.ReportItem, 0)
+	deletePool := newWorkerPool(r.options.Concurrency)
+	for _, dbInstance := range databasesToDelete {
+		dbInstance := dbInstance
+		dbLogger := r.logger.WithField(loggingKeyDatabase, aws.StringValue(dbInstance.DBInstanceIdentifier))
+		dbInstanceARN := aws.StringValue(dbInstance.DBInstanceArn)
+		if !r.isTarget(dbInstanceARN) {
+			dbLogger.Debug("resource is not targeted, skipping")
+			continue
+		}
+		dbLogger.Debugf("building report for database")
+		reportItem := &clusterservice.ReportItem{
+			ID:           dbInstanceARN,
+			Name:         aws.StringValue(dbInstance.DBInstanceIdentifier),
+			ResourceType: resourceTypeRDSInstance,
+			Region:       r.region,
+			Account:      accountFromARN(dbInstanceARN),
+			Manager:      string(managerRDS),
+			Tags:         databaseTags[dbInstanceARN],
+			Action:       clusterservice.ActionDelete,
+			ActionStatus: clusterservice.ActionStatusEmpty,
+		}
+		reportItems = append(reportItems, reportItem)
+		if r.isProtected(reportItem, dbInstance.InstanceCreateTime) {
+			dbLogger.Debug("resource is protected by the safety policy, skipping")
+			continue
+		}
+		if r.options.FinalSnapshot != nil {
+			reportItem.FinalSnapshot = finalSnapshotIdentifier(aws.StringValue(dbInstance.DBInstanceIdentifier), dbInstance.InstanceCreateTime)
+		}
+		if dryRun {
+			dbLogger.Debug("dry run enabled, skipping deletion step")
+			reportItem.ActionStatus = clusterservice.ActionStatusDryRun
+			continue
+		}
+		deletePool.Go(func() error {
+			dbLogger.Debug("performing deletion of database")
+			reportItem.ActionStatus = clusterservice.ActionStatusInProgress
+			reportItem.NextCheckAfter = rdsInstanceDeletionCheckInterval
+			//deleting will return an error if the database is already in a deleting state
+			if aws.StringValue(dbInstance.DBInstanceStatus) == statusDeleting {
+				dbLogger.Debugf("deletion of database already in progress")
+				reportItem.StatusReason = "deletion already in progress"
+				return nil
+			}
+			if reportItem.FinalSnapshot != "" {
+				snapshotReady, err := r.ensureFinalSnapshot(ctx, dbInstance, reportItem, dbLogger)
+				if err != nil || !snapshotReady {
+					return err
+				}
+			}
+			if aws.BoolValue(dbInstance.DeletionProtection) {
+				dbLogger.Debug("removing deletion protection on database")
+				modifyInput := &rds.ModifyDBInstanceInput{
+					DBInstanceIdentifier: dbInstance.DBInstanceIdentifier,
+					DeletionProtection:   aws.Bool(false),
+				}
+				modifyOutput, err := r.rdsClient.ModifyDBInstanceWithContext(ctx, modifyInput)
+				if err != nil {
+					return failReportItem(reportItem, errors.WrapLog(err, "failed to remove instance protection on database", dbLogger))
+				}
+				dbInstance = modifyOutput.DBInstance
+			}
+
+			deleteInput := &rds.DeleteDBInstanceInput{
+				DBInstanceIdentifier:   dbInstance.DBInstanceIdentifier,
+				DeleteAutomatedBackups: aws.Bool(true),
+				SkipFinalSnapshot:      aws.Bool(true),
+			}
+			_, err := r.rdsClient.DeleteDBInstanceWithContext(ctx, deleteInput)
+			if err != nil {
+				return failReportItem(reportItem, errors.WrapLog(err, "failed to delete rds instance", dbLogger))
+			}
+			return nil
+		})
+	}
+	return reportItems, errors.Aggregate(deletePool.Wait())
+}
+
+//ensureFinalSnapshot Take the final snapshot of a database unless an earlier run already started it, returning whether the snapshot is available
+func (r *RDSInstanceManager) ensureFinalSnapshot(ctx context.Context, dbInstance *rds.DBInstance, reportItem *clusterservice.ReportItem, dbLogger *logrus.Entry) (bool, error) {
+	snapshotLogger := dbLogger.WithField(loggingKeySnapshot, reportItem.FinalSnapshot)
+	dbSnapshots, err := describeDBSnapshots(ctx, r.rdsClient, &rds.DescribeDBSnapshotsInput{
+		DBSnapshotIdentifier: aws.String(reportItem.FinalSnapshot),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); !ok || awsErr.Code() != rds.ErrCodeDBSnapshotNotFoundFault {
+			return false, failReportItem(reportItem, errors.WrapLog(err, "failed to describe final snapshot of database", snapshotLogger))
+		}
+	}
+	if len(dbSnapshots) == 0 {
+		snapshotLogger.Debug("creating final snapshot of database")
+		createInput := &rds.CreateDBSnapshotInput{
+			DBInstanceIdentifier: dbInstance.DBInstanceIdentifier,
+			DBSnapshotIdentifier: aws.String(reportItem.FinalSnapshot),
+			Tags:                 r.options.FinalSnapshot.finalSnapshotRDSTags(reportItem.Tags),
+		}
+		if _, err := r.rdsClient.CreateDBSnapshotWithContext(ctx, createInput); err != nil {
+			return false, failReportItem(reportItem, errors.WrapLog(err, "failed to create final snapshot of database", snapshotLogger))
+		}
+		reportItem.StatusReason = fmt.Sprintf("creating final snapshot %s", reportItem.FinalSnapshot)
+		return false, nil
+	}
+	if status := aws.StringValue(dbSnapshots[0].Status); status != "available" {
+		snapshotLogger.Debugf("waiting for final snapshot of database in state %s", status)
+		reportItem.StatusReason = fmt.Sprintf("waiting for final snapshot %s in state %s", reportItem.FinalSnapshot, status)
+		return false, nil
+	}
+	return true, nil
+}
+
+//ListResourcesForCluster List RDS instances for a specified cluster without modifying them
+func (r *RDSInstanceManager) ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+	r.logger.Debug("listing resources for cluster")
+	databases, databaseTags, err := r.getDatabasesForCluster(ctx, clusterId, tags)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*clusterservice.Resource, 0, len(databases))
+	for _, dbInstance := range databases {
+		dbInstanceARN := aws.StringValue(dbInstance.DBInstanceArn)
+		resources = append(resources, &clusterservice.Resource{
+			ID:           dbInstanceARN,
+			Name:         aws.StringValue(dbInstance.DBInstanceIdentifier),
+			ResourceType: resourceTypeRDSInstance,
+			Region:       r.region,
+			Account:      accountFromARN(dbInstanceARN),
+			Manager:      string(managerRDS),
+			Tags:         databaseTags[dbInstanceARN],
+			State:        aws.StringValue(dbInstance.DBInstanceStatus),
+			CreationTime: dbInstance.InstanceCreateTime,
+			SizeBytes:    gibToBytes(dbInstance.AllocatedStorage),
+		})
+	}
+	return resources, nil
+}
+
+//getDatabasesForCluster Get the RDS instances tagged for a cluster, along with their tags keyed by instance arn
+//RDS instances aren't filtered using the resource tagging api, the tags of every instance are checked instead
+func (r *RDSInstanceManager) getDatabasesForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*rds.DBInstance, map[string]map[string]string, error) {
 	clusterDescribeInput := &rds.DescribeDBInstancesInput{}
-	clusterDescribeOutput, err := r.rdsClient.DescribeDBInstances(clusterDescribeInput)
+	dbInstances, err := describeDBInstances(ctx, r.rdsClient, clusterDescribeInput)
 	if err != nil {
-		return nil, errors.WrapLog(err, "failed to describe database clusters", r.logger)
+		return nil, nil, errors.WrapLog(err, "failed to describe database clusters", r.logger)
 	}
-	var databasesToDelete []*rds.DBInstance
-	for _, dbInstance := range clusterDescribeOutput.DBInstances {
+	var databases []*rds.DBInstance
+	databaseTags := map[string]map[string]string{}
+	for _, dbInstance := range dbInstances {
 		dbLogger := r.logger.WithField(loggingKeyDatabase, aws.StringValue(dbInstance.DBInstanceIdentifier))
+		//members of database clusters, e.g. aurora, are deleted along with their cluster by the cluster manager
+		if aws.StringValue(dbInstance.DBClusterIdentifier) != "" {
+			dbLogger.Debug("database is a member of a database cluster, ignoring")
+			continue
+		}
 		dbLogger.Debug("checking tags database cluster")
 		tagListInput := &rds.ListTagsForResourceInput{
 			ResourceName: dbInstance.DBInstanceArn,
 		}
-		tagListOutput, err := r.rdsClient.ListTagsForResource(tagListInput)
+		tagListOutput, err := r.rdsClient.ListTagsForResourceWithContext(ctx, tagListInput)
 		if err != nil {
-			return nil, errors.WrapLog(err, "failed to list tags for database cluster", dbLogger)
+			return nil, nil, errors.WrapLog(err, "failed to list tags for database cluster", dbLogger)
 		}
 		dbLogger.Debugf("checking for cluster tag match (%s=%s) on database", tagKeyClusterId, clusterId)
 		if findTag(tagKeyClusterId, clusterId, tagListOutput.TagList) == nil {
@@ -67,56 +239,11 @@ func (r *RDSInstanceManager) DeleteResourcesForCluster(clusterId string, tags ma
 			dbLogger.Debug("additional tags did not match, ignoring database")
 			continue
 		}
-		databasesToDelete = append(databasesToDelete, dbInstance)
-	}
-	r.logger.Debugf("filtering complete, %d databases matched", len(databasesToDelete))
-	reportItems := make([]*clusterservice.ReportItem, 0)
-	for _, dbInstance := range databasesToDelete {
-		dbLogger := r.logger.WithField(loggingKeyDatabase, aws.StringValue(dbInstance.DBInstanceIdentifier))
-		dbLogger.Debugf("building report for database")
-		reportItem := &clusterservice.ReportItem{
-			ID:           aws.StringValue(dbInstance.DBInstanceArn),
-			Name:         aws.StringValue(dbInstance.DBInstanceIdentifier),
-			Action:       clusterservice.ActionDelete,
-			ActionStatus: clusterservice.ActionStatusEmpty,
-		}
-		reportItems = append(reportItems, reportItem)
-		if dryRun {
-			dbLogger.Debug("dry run enabled, skipping deletion step")
-			reportItem.ActionStatus = clusterservice.ActionStatusDryRun
-			continue
-		}
-		dbLogger.Debug("performing deletion of database")
-		reportItem.ActionStatus = clusterservice.ActionStatusInProgress
-		//deleting will return an error if the database is already in a deleting state
-		if aws.StringValue(dbInstance.DBInstanceStatus) == statusDeleting {
-			dbLogger.Debugf("deletion of database already in progress")
-			continue
-		}
-		if aws.BoolValue(dbInstance.DeletionProtection) {
-			dbLogger.Debug("removing deletion protection on database")
-			modifyInput := &rds.ModifyDBInstanceInput{
-				DBInstanceIdentifier: dbInstance.DBInstanceIdentifier,
-				DeletionProtection:   aws.Bool(false),
-			}
-			modifyOutput, err := r.rdsClient.ModifyDBInstance(modifyInput)
-			if err != nil {
-				return nil, errors.WrapLog(err, "failed to remove instance protection on database", dbLogger)
-			}
-			dbInstance = modifyOutput.DBInstance
-		}
-
-		deleteInput := &rds.DeleteDBInstanceInput{
-			DBInstanceIdentifier:   dbInstance.DBInstanceIdentifier,
-			DeleteAutomatedBackups: aws.Bool(true),
-			SkipFinalSnapshot:      aws.Bool(true),
-		}
-		_, err := r.rdsClient.DeleteDBInstance(deleteInput)
-		if err != nil {
-			return nil, errors.WrapLog(err, "failed to delete rds instance", dbLogger)
-		}
+		databases = append(databases, dbInstance)
+		databaseTags[aws.StringValue(dbInstance.DBInstanceArn)] = convertRDSTagsToMap(tagListOutput.TagList)
 	}
-	return reportItems, nil
+	r.logger.Debugf("filtering complete, %d databases matched", len(databases))
+	return databases, databaseTags, nil
 }
 
 func findTag(key, value string, tags []*rds.Tag) *rds.Tag {
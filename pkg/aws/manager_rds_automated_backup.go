@@ -0,0 +1,177 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+	"github.com/integr8ly/cluster-service/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	loggingKeyAutomatedBackup = "automated-backup-id"
+
+	resourceTypeRDSAutomatedBackup = "rds:auto-backup"
+
+	//automatedBackupStatusRetained Status of the automated backups kept after their db instance was deleted, only these can be deleted
+	automatedBackupStatusRetained = "retained"
+)
+
+var _ PhasedClusterResourceManager = &RDSAutomatedBackupManager{}
+var _ ConfigurableClusterResourceManager = &RDSAutomatedBackupManager{}
+
+//RDSAutomatedBackupManager Delete the automated backups retained after their rds db instance was deleted
+type RDSAutomatedBackupManager struct {
+	configurable
+	rdsClient rdsClient
+	logger    *logrus.Entry
+	region    string
+}
+
+func NewDefaultRDSAutomatedBackupManager(session *session.Session, logger *logrus.Entry) *RDSAutomatedBackupManager {
+	return &RDSAutomatedBackupManager{
+		rdsClient: rds.New(session),
+		logger:    logger.WithField(loggingKeyManager, managerRDSAutomatedBackup),
+		region:    regionFromSession(session),
+	}
+}
+
+func (r *RDSAutomatedBackupManager) GetName() string {
+	return "AWS RDS Automated Backup Manager"
+}
+
+func (r *RDSAutomatedBackupManager) GetType() ResourceManagerType {
+	return managerRDSAutomatedBackup
+}
+
+func (r *RDSAutomatedBackupManager) GetDependencies() []ResourceManagerType {
+	return []ResourceManagerType{managerRDS}
+}
+
+//DeleteResourcesForCluster Delete the retained automated backups for a specified cluster
+func (r *RDSAutomatedBackupManager) DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
+	r.logger.Debug("delete retained automated backups for cluster")
+	automatedBackups, automatedBackupTags, err := r.getAutomatedBackupsForCluster(ctx, clusterId, tags)
+	if err != nil {
+		return nil, err
+	}
+	reportItems := make([]*clusterservice.ReportItem, 0)
+	deletePool := newWorkerPool(r.options.Concurrency)
+	for _, automatedBackup := range automatedBackups {
+		automatedBackup := automatedBackup
+		automatedBackupARN := aws.StringValue(automatedBackup.DBInstanceAutomatedBackupsArn)
+		automatedBackupLogger := r.logger.WithField(loggingKeyAutomatedBackup, aws.StringValue(automatedBackup.DbiResourceId))
+		if !r.isTarget(automatedBackupARN) {
+			automatedBackupLogger.Debug("resource is not targeted, skipping")
+			continue
+		}
+		reportItem := &clusterservice.ReportItem{
+			ID:           automatedBackupARN,
+			Name:         aws.StringValue(automatedBackup.DBInstanceIdentifier),
+			ResourceType: resourceTypeRDSAutomatedBackup,
+			Region:       r.region,
+			Account:      accountFromARN(automatedBackupARN),
+			Manager:      string(managerRDSAutomatedBackup),
+			Tags:         automatedBackupTags[automatedBackupARN],
+			Action:       clusterservice.ActionDelete,
+			ActionStatus: clusterservice.ActionStatusInProgress,
+		}
+		reportItems = append(reportItems, reportItem)
+		if r.isProtected(reportItem, automatedBackup.InstanceCreateTime) {
+			automatedBackupLogger.Debug("resource is protected by the safety policy, skipping")
+			continue
+		}
+		if dryRun {
+			automatedBackupLogger.Debug("dry run is enabled, skipping deletion")
+			reportItem.ActionStatus = clusterservice.ActionStatusDryRun
+			continue
+		}
+		if aws.StringValue(automatedBackup.Status) == statusDeleting {
+			automatedBackupLogger.Debug("deletion of automated backup already in progress")
+			reportItem.StatusReason = "deletion already in progress"
+			continue
+		}
+		deletePool.Go(func() error {
+			automatedBackupLogger.Debug("performing deletion request")
+			deleteInput := &rds.DeleteDBInstanceAutomatedBackupInput{
+				DbiResourceId: automatedBackup.DbiResourceId,
+			}
+			if _, err := r.rdsClient.DeleteDBInstanceAutomatedBackupWithContext(ctx, deleteInput); err != nil {
+				if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == rds.ErrCodeDBInstanceAutomatedBackupNotFoundFault {
+					automatedBackupLogger.Debug("automated backup not found, assuming it's been deleted")
+					reportItem.ActionStatus = clusterservice.ActionStatusComplete
+					return nil
+				}
+				return failReportItem(reportItem, errors.WrapLog(err, "failed to delete rds automated backup", automatedBackupLogger))
+			}
+			return nil
+		})
+	}
+	return reportItems, errors.Aggregate(deletePool.Wait())
+}
+
+//ListResourcesForCluster List the retained automated backups for a specified cluster without modifying them
+func (r *RDSAutomatedBackupManager) ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+	r.logger.Debug("list retained automated backups for cluster")
+	automatedBackups, automatedBackupTags, err := r.getAutomatedBackupsForCluster(ctx, clusterId, tags)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*clusterservice.Resource, 0, len(automatedBackups))
+	for _, automatedBackup := range automatedBackups {
+		automatedBackupARN := aws.StringValue(automatedBackup.DBInstanceAutomatedBackupsArn)
+		resources = append(resources, &clusterservice.Resource{
+			ID:           automatedBackupARN,
+			Name:         aws.StringValue(automatedBackup.DBInstanceIdentifier),
+			ResourceType: resourceTypeRDSAutomatedBackup,
+			Region:       r.region,
+			Account:      accountFromARN(automatedBackupARN),
+			Manager:      string(managerRDSAutomatedBackup),
+			Tags:         automatedBackupTags[automatedBackupARN],
+			State:        aws.StringValue(automatedBackup.Status),
+			CreationTime: automatedBackup.InstanceCreateTime,
+			SizeBytes:    gibToBytes(automatedBackup.AllocatedStorage),
+		})
+	}
+	return resources, nil
+}
+
+//getAutomatedBackupsForCluster Get the retained automated backups tagged for a cluster, along with their tags keyed by automated backup arn
+//Automated backups aren't returned by the resource tagging api, the tags of every retained automated backup are checked instead
+func (r *RDSAutomatedBackupManager) getAutomatedBackupsForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*rds.DBInstanceAutomatedBackup, map[string]map[string]string, error) {
+	describeInput := &rds.DescribeDBInstanceAutomatedBackupsInput{
+		Filters: []*rds.Filter{
+			{
+				Name:   aws.String("status"),
+				Values: aws.StringSlice([]string{automatedBackupStatusRetained, statusDeleting}),
+			},
+		},
+	}
+	describedBackups, err := describeDBInstanceAutomatedBackups(ctx, r.rdsClient, describeInput)
+	if err != nil {
+		return nil, nil, errors.WrapLog(err, "failed to describe rds automated backups", r.logger)
+	}
+	var automatedBackups []*rds.DBInstanceAutomatedBackup
+	automatedBackupTags := map[string]map[string]string{}
+	for _, automatedBackup := range describedBackups {
+		automatedBackupLogger := r.logger.WithField(loggingKeyAutomatedBackup, aws.StringValue(automatedBackup.DbiResourceId))
+		tagListOutput, err := r.rdsClient.ListTagsForResourceWithContext(ctx, &rds.ListTagsForResourceInput{
+			ResourceName: automatedBackup.DBInstanceAutomatedBackupsArn,
+		})
+		if err != nil {
+			return nil, nil, errors.WrapLog(err, "failed to list tags for rds automated backup", automatedBackupLogger)
+		}
+		if !hasRDSClusterTags(clusterId, tags, tagListOutput.TagList) {
+			automatedBackupLogger.Debug("automated backup did not match cluster tags, ignoring")
+			continue
+		}
+		automatedBackups = append(automatedBackups, automatedBackup)
+		automatedBackupTags[aws.StringValue(automatedBackup.DBInstanceAutomatedBackupsArn)] = convertRDSTagsToMap(tagListOutput.TagList)
+	}
+	r.logger.Debugf("found list of %d retained rds automated backups for cluster", len(automatedBackups))
+	return automatedBackups, automatedBackupTags, nil
+}
@@ -0,0 +1,168 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRDSAutomatedBackupManager_DeleteResourcesForCluster(t *testing.T) {
+	fakeLogger, err := fakeLogger(func(l *logrus.Entry) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeAutomatedBackupReportItem := func(modifyFn func(item *clusterservice.ReportItem)) *clusterservice.ReportItem {
+		return mockReportItem(func(item *clusterservice.ReportItem) {
+			item.ID = fakeRDSClientAutomatedBackupARN
+			item.Name = fakeResourceIdentifier
+			item.ResourceType = resourceTypeRDSAutomatedBackup
+			item.Account = fakeAccountId
+			item.Manager = string(managerRDSAutomatedBackup)
+			item.Tags = fakeRDSClientTags()
+			item.Action = clusterservice.ActionDelete
+			item.ActionStatus = clusterservice.ActionStatusInProgress
+			modifyFn(item)
+		})
+	}
+
+	tests := []struct {
+		name      string
+		modifyFn  func(c *rdsClientMock)
+		clusterId string
+		dryRun    bool
+		want      []*clusterservice.ReportItem
+		wantFn    func(mock *rdsClientMock) error
+		wantErr   string
+	}{
+		{
+			name:      "retained automated backups are deleted",
+			clusterId: fakeRDSClientTagVal,
+			want: []*clusterservice.ReportItem{
+				fakeAutomatedBackupReportItem(func(item *clusterservice.ReportItem) {}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				describeCalls := mock.DescribeDBInstanceAutomatedBackupsWithContextCalls()
+				if len(describeCalls) != 1 || aws.StringValue(describeCalls[0].DescribeDBInstanceAutomatedBackupsInput.Filters[0].Name) != "status" {
+					return errors.New("automated backups should be filtered by status")
+				}
+				deleteCalls := mock.DeleteDBInstanceAutomatedBackupWithContextCalls()
+				if len(deleteCalls) != 1 || aws.StringValue(deleteCalls[0].DeleteDBInstanceAutomatedBackupInput.DbiResourceId) != fakeRDSClientDbiResourceId {
+					return errors.New("automated backup should be deleted by its resource id")
+				}
+				return nil
+			},
+		},
+		{
+			name:      "report empty when no automated backups match the cluster id tag",
+			clusterId: "other",
+			want:      []*clusterservice.ReportItem{},
+		},
+		{
+			name:      "no destructive methods are used when dry run is true",
+			clusterId: fakeRDSClientTagVal,
+			dryRun:    true,
+			want: []*clusterservice.ReportItem{
+				fakeAutomatedBackupReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusDryRun
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.DeleteDBInstanceAutomatedBackupWithContextCalls()) != 0 {
+					return errors.New("delete automated backup call count should be 0")
+				}
+				return nil
+			},
+		},
+		{
+			name: "delete is not performed if automated backup is in state deleting",
+			modifyFn: func(c *rdsClientMock) {
+				c.DescribeDBInstanceAutomatedBackupsWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBInstanceAutomatedBackupsInput, opts ...request.Option) (*rds.DescribeDBInstanceAutomatedBackupsOutput, error) {
+					automatedBackup := fakeRDSClientAutomatedBackup()
+					automatedBackup.Status = aws.String(statusDeleting)
+					return &rds.DescribeDBInstanceAutomatedBackupsOutput{DBInstanceAutomatedBackups: []*rds.DBInstanceAutomatedBackup{automatedBackup}}, nil
+				}
+			},
+			clusterId: fakeRDSClientTagVal,
+			want: []*clusterservice.ReportItem{
+				fakeAutomatedBackupReportItem(func(item *clusterservice.ReportItem) {
+					item.StatusReason = "deletion already in progress"
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.DeleteDBInstanceAutomatedBackupWithContextCalls()) != 0 {
+					return errors.New("delete automated backup call count should be 0")
+				}
+				return nil
+			},
+		},
+		{
+			name: "automated backups which are not found are complete",
+			modifyFn: func(c *rdsClientMock) {
+				c.DeleteDBInstanceAutomatedBackupWithContextFunc = func(ctx context.Context, in1 *rds.DeleteDBInstanceAutomatedBackupInput, opts ...request.Option) (*rds.DeleteDBInstanceAutomatedBackupOutput, error) {
+					return nil, awserr.New(rds.ErrCodeDBInstanceAutomatedBackupNotFoundFault, "not found", nil)
+				}
+			},
+			clusterId: fakeRDSClientTagVal,
+			want: []*clusterservice.ReportItem{
+				fakeAutomatedBackupReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusComplete
+				}),
+			},
+		},
+		{
+			name: "error when listing tags of automated backups fails",
+			modifyFn: func(c *rdsClientMock) {
+				c.ListTagsForResourceWithContextFunc = func(ctx context.Context, in1 *rds.ListTagsForResourceInput, opts ...request.Option) (*rds.ListTagsForResourceOutput, error) {
+					return nil, errors.New("denied")
+				}
+			},
+			clusterId: fakeRDSClientTagVal,
+			wantErr:   "failed to list tags for rds automated backup: denied",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
+				if tt.modifyFn != nil {
+					tt.modifyFn(c)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := &RDSAutomatedBackupManager{
+				rdsClient: fakeClient,
+				logger:    fakeLogger,
+			}
+			got, err := r.DeleteResourcesForCluster(context.TODO(), tt.clusterId, map[string]string{}, tt.dryRun)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("DeleteResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DeleteResourcesForCluster() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DeleteResourcesForCluster() got = %v, want %v", got, tt.want)
+			}
+			if tt.wantFn != nil {
+				if err := tt.wantFn(fakeClient); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,363 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+	"github.com/integr8ly/cluster-service/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	loggingKeyDBCluster = "db-cluster-id"
+
+	resourceTypeRDSCluster = "rds:cluster"
+
+	//rdsClusterDeletionCheckInterval Deleting the members of an aurora cluster and then the cluster takes a while, there is no point checking on it much sooner
+	rdsClusterDeletionCheckInterval = 5 * time.Minute
+)
+
+var _ PhasedClusterResourceManager = &RDSClusterManager{}
+var _ ConfigurableClusterResourceManager = &RDSClusterManager{}
+
+//rdsDBCluster An rds db cluster, e.g. aurora, along with its member instances
+type rdsDBCluster struct {
+	Cluster *rds.DBCluster
+	Members []*rds.DBInstance
+}
+
+//RDSClusterManager Delete rds db clusters, such as aurora clusters, along with their member instances
+//Member instances are deleted first, the cluster is deleted by a later run once it has no members left
+type RDSClusterManager struct {
+	configurable
+	rdsClient rdsClient
+	logger    *logrus.Entry
+	region    string
+}
+
+func NewDefaultRDSClusterManager(session *session.Session, logger *logrus.Entry) *RDSClusterManager {
+	return &RDSClusterManager{
+		rdsClient: rds.New(session),
+		logger:    logger.WithField(loggingKeyManager, managerRDSCluster),
+		region:    regionFromSession(session),
+	}
+}
+
+func (r *RDSClusterManager) GetName() string {
+	return "AWS RDS Cluster Manager"
+}
+
+func (r *RDSClusterManager) GetType() ResourceManagerType {
+	return managerRDSCluster
+}
+
+func (r *RDSClusterManager) GetDependencies() []ResourceManagerType {
+	return nil
+}
+
+//DeleteResourcesForCluster Delete the rds db clusters and their member instances for a specified cluster
+func (r *RDSClusterManager) DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
+	r.logger.Debug("deleting resources for cluster")
+	dbClusters, err := r.getDBClustersForCluster(ctx, clusterId, tags)
+	if err != nil {
+		return nil, err
+	}
+	reportItems := make([]*clusterservice.ReportItem, 0)
+	deletePool := newWorkerPool(r.options.Concurrency)
+	for _, dbCluster := range dbClusters {
+		dbCluster := dbCluster
+		dbClusterLogger := r.logger.WithField(loggingKeyDBCluster, aws.StringValue(dbCluster.Cluster.DBClusterIdentifier))
+		dbClusterARN := aws.StringValue(dbCluster.Cluster.DBClusterArn)
+		if !r.isTarget(dbClusterARN) {
+			dbClusterLogger.Debug("resource is not targeted, skipping")
+			continue
+		}
+		dbClusterLogger.Debug("building report for database cluster")
+		clusterReportItem := r.newClusterReportItem(dbCluster.Cluster)
+		reportItems = append(reportItems, clusterReportItem)
+		//a plan lists the members along with their cluster, a member missing from it was created after planning and mustn't be deleted unreviewed
+		if untargetedMember := r.findUntargetedMember(dbCluster); untargetedMember != nil {
+			dbClusterLogger.Debug("member instance is not targeted, skipping")
+			clusterReportItem.ActionStatus = clusterservice.ActionStatusSkipped
+			clusterReportItem.StatusReason = fmt.Sprintf("member instance %s not in plan", aws.StringValue(untargetedMember.DBInstanceIdentifier))
+			for _, member := range dbCluster.Members {
+				if !r.isTarget(aws.StringValue(member.DBInstanceArn)) {
+					continue
+				}
+				memberReportItem := r.newMemberReportItem(member)
+				memberReportItem.ActionStatus = clusterservice.ActionStatusSkipped
+				memberReportItem.StatusReason = fmt.Sprintf("member of skipped database cluster %s", clusterReportItem.Name)
+				reportItems = append(reportItems, memberReportItem)
+			}
+			continue
+		}
+		memberReportItems := make([]*clusterservice.ReportItem, 0, len(dbCluster.Members))
+		for _, member := range dbCluster.Members {
+			memberReportItems = append(memberReportItems, r.newMemberReportItem(member))
+		}
+		reportItems = append(reportItems, memberReportItems...)
+		if r.isProtected(clusterReportItem, dbCluster.Cluster.ClusterCreateTime) {
+			dbClusterLogger.Debug("resource is protected by the safety policy, skipping")
+			//the members can't outlive their cluster, so they're only deleted along with it
+			for _, memberReportItem := range memberReportItems {
+				memberReportItem.ActionStatus = clusterservice.ActionStatusProtected
+				memberReportItem.StatusReason = fmt.Sprintf("member of protected database cluster %s", clusterReportItem.Name)
+			}
+			continue
+		}
+		if protectedMember := r.findProtectedMember(dbCluster, memberReportItems); protectedMember != nil {
+			dbClusterLogger.Debug("member instance is protected by the safety policy, skipping")
+			clusterReportItem.ActionStatus = clusterservice.ActionStatusProtected
+			clusterReportItem.StatusReason = fmt.Sprintf("member instance %s is protected", protectedMember.Name)
+			continue
+		}
+		if r.options.FinalSnapshot != nil {
+			clusterReportItem.FinalSnapshot = finalSnapshotIdentifier(aws.StringValue(dbCluster.Cluster.DBClusterIdentifier), dbCluster.Cluster.ClusterCreateTime)
+		}
+		if dryRun {
+			dbClusterLogger.Debug("dry run enabled, skipping deletion step")
+			clusterReportItem.ActionStatus = clusterservice.ActionStatusDryRun
+			for _, memberReportItem := range memberReportItems {
+				memberReportItem.ActionStatus = clusterservice.ActionStatusDryRun
+			}
+			continue
+		}
+		deletePool.Go(func() error {
+			return r.deleteDBCluster(ctx, dbCluster, clusterReportItem, memberReportItems, dbClusterLogger)
+		})
+	}
+	return reportItems, errors.Aggregate(deletePool.Wait())
+}
+
+//deleteDBCluster Remove the deletion protection of a database cluster and delete its member instances, the cluster itself is only deleted once it has no members left
+func (r *RDSClusterManager) deleteDBCluster(ctx context.Context, dbCluster *rdsDBCluster, clusterReportItem *clusterservice.ReportItem, memberReportItems []*clusterservice.ReportItem, dbClusterLogger *logrus.Entry) error {
+	clusterReportItem.ActionStatus = clusterservice.ActionStatusInProgress
+	clusterReportItem.NextCheckAfter = rdsClusterDeletionCheckInterval
+	//deleting will return an error if the cluster is already in a deleting state
+	if aws.StringValue(dbCluster.Cluster.Status) == statusDeleting {
+		dbClusterLogger.Debug("deletion of database cluster already in progress")
+		clusterReportItem.StatusReason = "deletion already in progress"
+		return nil
+	}
+	//the snapshot is taken while the members still exist, a cluster can only be snapshotted while it's available
+	if clusterReportItem.FinalSnapshot != "" {
+		snapshotReady, err := r.ensureFinalSnapshot(ctx, dbCluster.Cluster, clusterReportItem, dbClusterLogger)
+		if err != nil {
+			return err
+		}
+		if !snapshotReady {
+			for _, memberReportItem := range memberReportItems {
+				memberReportItem.ActionStatus = clusterservice.ActionStatusInProgress
+				memberReportItem.StatusReason = fmt.Sprintf("waiting for final snapshot %s of database cluster", clusterReportItem.FinalSnapshot)
+			}
+			return nil
+		}
+	}
+	if aws.BoolValue(dbCluster.Cluster.DeletionProtection) {
+		dbClusterLogger.Debug("removing deletion protection on database cluster")
+		modifyInput := &rds.ModifyDBClusterInput{
+			DBClusterIdentifier: dbCluster.Cluster.DBClusterIdentifier,
+			DeletionProtection:  aws.Bool(false),
+			ApplyImmediately:    aws.Bool(true),
+		}
+		if _, err := r.rdsClient.ModifyDBClusterWithContext(ctx, modifyInput); err != nil {
+			return failReportItem(clusterReportItem, errors.WrapLog(err, "failed to remove deletion protection on database cluster", dbClusterLogger))
+		}
+	}
+	var memberErrors []error
+	for i, member := range dbCluster.Members {
+		memberReportItem := memberReportItems[i]
+		memberLogger := dbClusterLogger.WithField(loggingKeyDatabase, aws.StringValue(member.DBInstanceIdentifier))
+		memberReportItem.ActionStatus = clusterservice.ActionStatusInProgress
+		memberReportItem.NextCheckAfter = rdsInstanceDeletionCheckInterval
+		if aws.StringValue(member.DBInstanceStatus) == statusDeleting {
+			memberLogger.Debug("deletion of member instance already in progress")
+			memberReportItem.StatusReason = "deletion already in progress"
+			continue
+		}
+		memberLogger.Debug("performing deletion of member instance")
+		deleteInput := &rds.DeleteDBInstanceInput{
+			DBInstanceIdentifier: member.DBInstanceIdentifier,
+			SkipFinalSnapshot:    aws.Bool(true),
+		}
+		if _, err := r.rdsClient.DeleteDBInstanceWithContext(ctx, deleteInput); err != nil {
+			memberErrors = append(memberErrors, failReportItem(memberReportItem, errors.WrapLog(err, "failed to delete member instance of database cluster", memberLogger)))
+		}
+	}
+	if len(memberErrors) > 0 {
+		return failReportItem(clusterReportItem, errors.Aggregate(memberErrors))
+	}
+	if len(dbCluster.Members) > 0 {
+		dbClusterLogger.Debugf("waiting for %d member instances to be deleted before deleting database cluster", len(dbCluster.Members))
+		clusterReportItem.StatusReason = fmt.Sprintf("waiting for %d member instances to be deleted", len(dbCluster.Members))
+		return nil
+	}
+	dbClusterLogger.Debug("performing deletion of database cluster")
+	deleteInput := &rds.DeleteDBClusterInput{
+		DBClusterIdentifier: dbCluster.Cluster.DBClusterIdentifier,
+		SkipFinalSnapshot:   aws.Bool(true),
+	}
+	if _, err := r.rdsClient.DeleteDBClusterWithContext(ctx, deleteInput); err != nil {
+		return failReportItem(clusterReportItem, errors.WrapLog(err, "failed to delete database cluster", dbClusterLogger))
+	}
+	return nil
+}
+
+//ensureFinalSnapshot Take the final snapshot of a database cluster unless an earlier run already started it, returning whether the snapshot is available
+func (r *RDSClusterManager) ensureFinalSnapshot(ctx context.Context, dbCluster *rds.DBCluster, clusterReportItem *clusterservice.ReportItem, dbClusterLogger *logrus.Entry) (bool, error) {
+	snapshotLogger := dbClusterLogger.WithField(loggingKeySnapshot, clusterReportItem.FinalSnapshot)
+	dbClusterSnapshots, err := describeDBClusterSnapshots(ctx, r.rdsClient, &rds.DescribeDBClusterSnapshotsInput{
+		DBClusterSnapshotIdentifier: aws.String(clusterReportItem.FinalSnapshot),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); !ok || awsErr.Code() != rds.ErrCodeDBClusterSnapshotNotFoundFault {
+			return false, failReportItem(clusterReportItem, errors.WrapLog(err, "failed to describe final snapshot of database cluster", snapshotLogger))
+		}
+	}
+	if len(dbClusterSnapshots) == 0 {
+		snapshotLogger.Debug("creating final snapshot of database cluster")
+		createInput := &rds.CreateDBClusterSnapshotInput{
+			DBClusterIdentifier:         dbCluster.DBClusterIdentifier,
+			DBClusterSnapshotIdentifier: aws.String(clusterReportItem.FinalSnapshot),
+			Tags:                        r.options.FinalSnapshot.finalSnapshotRDSTags(clusterReportItem.Tags),
+		}
+		if _, err := r.rdsClient.CreateDBClusterSnapshotWithContext(ctx, createInput); err != nil {
+			return false, failReportItem(clusterReportItem, errors.WrapLog(err, "failed to create final snapshot of database cluster", snapshotLogger))
+		}
+		clusterReportItem.StatusReason = fmt.Sprintf("creating final snapshot %s", clusterReportItem.FinalSnapshot)
+		return false, nil
+	}
+	if status := aws.StringValue(dbClusterSnapshots[0].Status); status != "available" {
+		snapshotLogger.Debugf("waiting for final snapshot of database cluster in state %s", status)
+		clusterReportItem.StatusReason = fmt.Sprintf("waiting for final snapshot %s in state %s", clusterReportItem.FinalSnapshot, status)
+		return false, nil
+	}
+	return true, nil
+}
+
+//findProtectedMember Find the report item of the first member instance the safety policy protects, which keeps its cluster from being deleted
+func (r *RDSClusterManager) findProtectedMember(dbCluster *rdsDBCluster, memberReportItems []*clusterservice.ReportItem) *clusterservice.ReportItem {
+	var protectedMember *clusterservice.ReportItem
+	for i, member := range dbCluster.Members {
+		if r.isProtected(memberReportItems[i], member.InstanceCreateTime) && protectedMember == nil {
+			protectedMember = memberReportItems[i]
+		}
+	}
+	return protectedMember
+}
+
+//findUntargetedMember Find the first member instance of a database cluster which isn't targeted, nil if every member is
+func (r *RDSClusterManager) findUntargetedMember(dbCluster *rdsDBCluster) *rds.DBInstance {
+	for _, member := range dbCluster.Members {
+		if !r.isTarget(aws.StringValue(member.DBInstanceArn)) {
+			return member
+		}
+	}
+	return nil
+}
+
+//ListResourcesForCluster List the rds db clusters and their member instances for a specified cluster without modifying them
+func (r *RDSClusterManager) ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+	r.logger.Debug("listing resources for cluster")
+	dbClusters, err := r.getDBClustersForCluster(ctx, clusterId, tags)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*clusterservice.Resource, 0, len(dbClusters))
+	for _, dbCluster := range dbClusters {
+		resources = append(resources, &clusterservice.Resource{
+			ID:           aws.StringValue(dbCluster.Cluster.DBClusterArn),
+			Name:         aws.StringValue(dbCluster.Cluster.DBClusterIdentifier),
+			ResourceType: resourceTypeRDSCluster,
+			Region:       r.region,
+			Account:      accountFromARN(aws.StringValue(dbCluster.Cluster.DBClusterArn)),
+			Manager:      string(managerRDSCluster),
+			Tags:         convertRDSTagsToMap(dbCluster.Cluster.TagList),
+			State:        aws.StringValue(dbCluster.Cluster.Status),
+			CreationTime: dbCluster.Cluster.ClusterCreateTime,
+			SizeBytes:    gibToBytes(dbCluster.Cluster.AllocatedStorage),
+		})
+		for _, member := range dbCluster.Members {
+			resources = append(resources, &clusterservice.Resource{
+				ID:           aws.StringValue(member.DBInstanceArn),
+				Name:         aws.StringValue(member.DBInstanceIdentifier),
+				ResourceType: resourceTypeRDSInstance,
+				Region:       r.region,
+				Account:      accountFromARN(aws.StringValue(member.DBInstanceArn)),
+				Manager:      string(managerRDSCluster),
+				Tags:         convertRDSTagsToMap(member.TagList),
+				State:        aws.StringValue(member.DBInstanceStatus),
+				CreationTime: member.InstanceCreateTime,
+			})
+		}
+	}
+	return resources, nil
+}
+
+func (r *RDSClusterManager) newClusterReportItem(dbCluster *rds.DBCluster) *clusterservice.ReportItem {
+	dbClusterARN := aws.StringValue(dbCluster.DBClusterArn)
+	return &clusterservice.ReportItem{
+		ID:           dbClusterARN,
+		Name:         aws.StringValue(dbCluster.DBClusterIdentifier),
+		ResourceType: resourceTypeRDSCluster,
+		Region:       r.region,
+		Account:      accountFromARN(dbClusterARN),
+		Manager:      string(managerRDSCluster),
+		Tags:         convertRDSTagsToMap(dbCluster.TagList),
+		Action:       clusterservice.ActionDelete,
+		ActionStatus: clusterservice.ActionStatusEmpty,
+	}
+}
+
+func (r *RDSClusterManager) newMemberReportItem(member *rds.DBInstance) *clusterservice.ReportItem {
+	memberARN := aws.StringValue(member.DBInstanceArn)
+	return &clusterservice.ReportItem{
+		ID:           memberARN,
+		Name:         aws.StringValue(member.DBInstanceIdentifier),
+		ResourceType: resourceTypeRDSInstance,
+		Region:       r.region,
+		Account:      accountFromARN(memberARN),
+		Manager:      string(managerRDSCluster),
+		Tags:         convertRDSTagsToMap(member.TagList),
+		Action:       clusterservice.ActionDelete,
+		ActionStatus: clusterservice.ActionStatusEmpty,
+	}
+}
+
+//getDBClustersForCluster Get the rds db clusters tagged for a cluster along with their member instances
+//The member instances are found by their cluster identifier, they don't need to be tagged themselves
+func (r *RDSClusterManager) getDBClustersForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*rdsDBCluster, error) {
+	clusters, err := describeDBClusters(ctx, r.rdsClient, &rds.DescribeDBClustersInput{})
+	if err != nil {
+		return nil, errors.WrapLog(err, "failed to describe database clusters", r.logger)
+	}
+	var dbClusters []*rdsDBCluster
+	for _, cluster := range clusters {
+		dbClusterLogger := r.logger.WithField(loggingKeyDBCluster, aws.StringValue(cluster.DBClusterIdentifier))
+		if !hasRDSClusterTags(clusterId, tags, cluster.TagList) {
+			dbClusterLogger.Debug("database cluster did not match cluster tags, ignoring")
+			continue
+		}
+		describeInput := &rds.DescribeDBInstancesInput{
+			Filters: []*rds.Filter{
+				{
+					Name:   aws.String("db-cluster-id"),
+					Values: []*string{cluster.DBClusterIdentifier},
+				},
+			},
+		}
+		members, err := describeDBInstances(ctx, r.rdsClient, describeInput)
+		if err != nil {
+			return nil, errors.WrapLog(err, "failed to describe member instances of database cluster", dbClusterLogger)
+		}
+		dbClusters = append(dbClusters, &rdsDBCluster{Cluster: cluster, Members: members})
+	}
+	r.logger.Debugf("filtering complete, %d database clusters matched", len(dbClusters))
+	return dbClusters, nil
+}
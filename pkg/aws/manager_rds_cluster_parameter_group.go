@@ -0,0 +1,64 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	resourceTypeRDSClusterParameterGroup = "rds:cluster-pg"
+)
+
+var _ PhasedClusterResourceManager = &RDSClusterParameterGroupManager{}
+var _ ConfigurableClusterResourceManager = &RDSClusterParameterGroupManager{}
+
+//rdsClusterParameterGroupKind Custom db cluster parameter groups, used by db clusters such as aurora clusters
+var rdsClusterParameterGroupKind = &rdsGroupKind{
+	resourceType:      resourceTypeRDSClusterParameterGroup,
+	manager:           managerRDSClusterParameterGroup,
+	description:       "db cluster parameter group",
+	inUseErrorCode:    rds.ErrCodeInvalidDBParameterGroupStateFault,
+	notFoundErrorCode: rds.ErrCodeDBParameterGroupNotFoundFault,
+	findReferences: func(ctx context.Context, client rdsClient) (map[string][]string, error) {
+		dbClusters, err := describeDBClusters(ctx, client, &rds.DescribeDBClustersInput{})
+		if err != nil {
+			return nil, err
+		}
+		references := map[string][]string{}
+		for _, dbCluster := range dbClusters {
+			groupName := aws.StringValue(dbCluster.DBClusterParameterGroup)
+			references[groupName] = append(references[groupName], fmt.Sprintf("db cluster %s", aws.StringValue(dbCluster.DBClusterIdentifier)))
+		}
+		return references, nil
+	},
+	deleteGroup: func(ctx context.Context, client rdsClient, name string) error {
+		_, err := client.DeleteDBClusterParameterGroupWithContext(ctx, &rds.DeleteDBClusterParameterGroupInput{
+			DBClusterParameterGroupName: aws.String(name),
+		})
+		return err
+	},
+}
+
+//RDSClusterParameterGroupManager Delete custom db cluster parameter groups once no db cluster uses them
+type RDSClusterParameterGroupManager struct {
+	rdsGroupManager
+}
+
+func NewDefaultRDSClusterParameterGroupManager(session *session.Session, logger *logrus.Entry) *RDSClusterParameterGroupManager {
+	return &RDSClusterParameterGroupManager{
+		rdsGroupManager: newRDSGroupManager(session, logger, rdsClusterParameterGroupKind),
+	}
+}
+
+func (r *RDSClusterParameterGroupManager) GetName() string {
+	return "AWS RDS Cluster Parameter Group Manager"
+}
+
+func (r *RDSClusterParameterGroupManager) GetDependencies() []ResourceManagerType {
+	return []ResourceManagerType{managerRDSCluster}
+}
@@ -0,0 +1,219 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+	"github.com/integr8ly/cluster-service/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	resourceTypeRDSClusterSnapshot = "rds:cluster-snapshot"
+)
+
+var _ PhasedClusterResourceManager = &RDSClusterSnapshotManager{}
+var _ ConfigurableClusterResourceManager = &RDSClusterSnapshotManager{}
+
+//RDSClusterSnapshotManager Delete manual snapshots of rds db clusters, such as aurora clusters
+type RDSClusterSnapshotManager struct {
+	configurable
+	rdsClient     rdsClient
+	taggingClient taggingClient
+	logger        *logrus.Entry
+	region        string
+}
+
+func NewDefaultRDSClusterSnapshotManager(session *session.Session, logger *logrus.Entry) *RDSClusterSnapshotManager {
+	return &RDSClusterSnapshotManager{
+		rdsClient:     rds.New(session),
+		taggingClient: resourcegroupstaggingapi.New(session),
+		logger:        logger.WithField(loggingKeyManager, managerRDSClusterSnapshot),
+		region:        regionFromSession(session),
+	}
+}
+
+func (r *RDSClusterSnapshotManager) GetName() string {
+	return "AWS RDS Cluster Snapshot Manager"
+}
+
+func (r *RDSClusterSnapshotManager) GetType() ResourceManagerType {
+	return managerRDSClusterSnapshot
+}
+
+func (r *RDSClusterSnapshotManager) GetDependencies() []ResourceManagerType {
+	return []ResourceManagerType{managerRDSCluster}
+}
+
+//DeleteResourcesForCluster Delete the manual RDS cluster snapshots for a specified cluster, automated snapshots are removed by aws along with their db cluster
+func (r *RDSClusterSnapshotManager) DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
+	r.logger.Debug("delete cluster snapshots for cluster")
+	snapshotsToDelete, err := r.getClusterSnapshotsForCluster(ctx, clusterId, tags)
+	if err != nil {
+		return nil, err
+	}
+	var reportItems []*clusterservice.ReportItem
+	deletePool := newWorkerPool(r.options.Concurrency)
+	for _, snapshot := range snapshotsToDelete {
+		snapshot := snapshot
+		snapshotLogger := r.logger.WithField(loggingKeySnapshot, snapshot.ID)
+		if !r.isTarget(snapshot.ARN) {
+			snapshotLogger.Debug("resource is not targeted, skipping")
+			continue
+		}
+		snapshotLogger.Debug("handling deletion for cluster snapshot")
+		reportItem := &clusterservice.ReportItem{
+			ID:           snapshot.ARN,
+			Name:         snapshot.ID,
+			ResourceType: resourceTypeRDSClusterSnapshot,
+			Region:       r.region,
+			Account:      accountFromARN(snapshot.ARN),
+			Manager:      string(managerRDSClusterSnapshot),
+			Tags:         snapshot.Tags,
+			Action:       clusterservice.ActionDelete,
+			ActionStatus: clusterservice.ActionStatusInProgress,
+		}
+		reportItems = append(reportItems, reportItem)
+		if r.isProtected(reportItem, snapshot.CreationTime) {
+			snapshotLogger.Debug("resource is protected by the safety policy, skipping")
+			continue
+		}
+		if r.isRetained(reportItem) {
+			snapshotLogger.Debug("final snapshot is still retained, skipping")
+			continue
+		}
+		if dryRun {
+			snapshotLogger.Debug("dry run is enabled, skipping deletion")
+			reportItem.ActionStatus = clusterservice.ActionStatusDryRun
+			continue
+		}
+		deletePool.Go(func() error {
+			describeSnapshotInput := &rds.DescribeDBClusterSnapshotsInput{
+				DBClusterSnapshotIdentifier: aws.String(snapshot.ID),
+			}
+			dbClusterSnapshots, err := describeDBClusterSnapshots(ctx, r.rdsClient, describeSnapshotInput)
+			if err != nil {
+				if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == rds.ErrCodeDBClusterSnapshotNotFoundFault {
+					snapshotLogger.Debug("cluster snapshot not found, assuming it's been deleted")
+					reportItem.ActionStatus = clusterservice.ActionStatusComplete
+					return nil
+				}
+				return failReportItem(reportItem, errors.WrapLog(err, "failed to describe db cluster snapshots", snapshotLogger))
+			}
+			if len(dbClusterSnapshots) != 1 {
+				return failReportItem(reportItem, errors.WrapLog(fmt.Errorf("found %d cluster snapshots", len(dbClusterSnapshots)), "unexpected number of cluster snapshots found", snapshotLogger))
+			}
+			foundSnapshot := dbClusterSnapshots[0]
+			if aws.StringValue(foundSnapshot.SnapshotType) != "manual" {
+				snapshotLogger.Debugf("unsupported snapshot type %s cannot be deleted, skipping", aws.StringValue(foundSnapshot.SnapshotType))
+				reportItem.ActionStatus = clusterservice.ActionStatusSkipped
+				reportItem.StatusReason = fmt.Sprintf("unsupported snapshot type %s", aws.StringValue(foundSnapshot.SnapshotType))
+				return nil
+			}
+			if aws.StringValue(foundSnapshot.Status) != "available" {
+				snapshotLogger.Debugf("cluster snapshot is not in an available state, current state is %s", aws.StringValue(foundSnapshot.Status))
+				reportItem.ActionStatus = clusterservice.ActionStatusSkipped
+				reportItem.StatusReason = fmt.Sprintf("snapshot is in state %s", aws.StringValue(foundSnapshot.Status))
+				return nil
+			}
+			snapshotLogger.Debug("performing deletion request")
+			deleteSnapshotInput := &rds.DeleteDBClusterSnapshotInput{
+				DBClusterSnapshotIdentifier: aws.String(snapshot.ID),
+			}
+			if _, err := r.rdsClient.DeleteDBClusterSnapshotWithContext(ctx, deleteSnapshotInput); err != nil {
+				return failReportItem(reportItem, errors.WrapLog(err, "failed to delete rds cluster snapshot", snapshotLogger))
+			}
+			return nil
+		})
+	}
+	return reportItems, errors.Aggregate(deletePool.Wait())
+}
+
+//ListResourcesForCluster List RDS cluster snapshots for a specified cluster without modifying them
+func (r *RDSClusterSnapshotManager) ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+	r.logger.Debug("list cluster snapshots for cluster")
+	snapshots, err := r.getClusterSnapshotsForCluster(ctx, clusterId, tags)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*clusterservice.Resource, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		snapshotLogger := r.logger.WithField(loggingKeySnapshot, snapshot.ID)
+		resource := &clusterservice.Resource{
+			ID:           snapshot.ARN,
+			Name:         snapshot.ID,
+			ResourceType: resourceTypeRDSClusterSnapshot,
+			Region:       r.region,
+			Account:      accountFromARN(snapshot.ARN),
+			Manager:      string(managerRDSClusterSnapshot),
+			Tags:         snapshot.Tags,
+		}
+		dbClusterSnapshots, err := describeDBClusterSnapshots(ctx, r.rdsClient, &rds.DescribeDBClusterSnapshotsInput{
+			DBClusterSnapshotIdentifier: aws.String(snapshot.ID),
+		})
+		if err != nil {
+			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == rds.ErrCodeDBClusterSnapshotNotFoundFault {
+				snapshotLogger.Debug("cluster snapshot not found, assuming it's been deleted")
+				continue
+			}
+			return nil, errors.WrapLog(err, "failed to describe db cluster snapshots", snapshotLogger)
+		}
+		if len(dbClusterSnapshots) == 1 {
+			resource.State = aws.StringValue(dbClusterSnapshots[0].Status)
+			resource.CreationTime = dbClusterSnapshots[0].SnapshotCreateTime
+			resource.SizeBytes = gibToBytes(dbClusterSnapshots[0].AllocatedStorage)
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+//getClusterSnapshotsForCluster Get the RDS cluster snapshots tagged for a cluster using the resource tagging api
+func (r *RDSClusterSnapshotManager) getClusterSnapshotsForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*rdsSnapshot, error) {
+	r.logger.Debug("listing rds cluster snapshots using provided tag filters")
+	getResourcesInput := &resourcegroupstaggingapi.GetResourcesInput{
+		ResourceTypeFilters: aws.StringSlice([]string{resourceTypeRDSClusterSnapshot}),
+		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
+	}
+	resourceTagMappings, err := getTaggedResources(ctx, r.taggingClient, getResourcesInput)
+	if err != nil {
+		return nil, errors.WrapLog(err, "failed to filter cluster snapshots in aws", r.logger)
+	}
+	var snapshots []*rdsSnapshot
+	for _, resourceTagMapping := range resourceTagMappings {
+		snapshotARN := aws.StringValue(resourceTagMapping.ResourceARN)
+		//the snapshot id is the last element of the arn
+		snapshotARNElements := strings.Split(snapshotARN, ":")
+		snapshotID := snapshotARNElements[len(snapshotARNElements)-1]
+		snapshotLogger := r.logger.WithField(loggingKeySnapshot, snapshotID)
+		//the tagging api doesn't know the creation time, which the minimum age of the safety policy needs
+		dbClusterSnapshots, err := describeDBClusterSnapshots(ctx, r.rdsClient, &rds.DescribeDBClusterSnapshotsInput{
+			DBClusterSnapshotIdentifier: aws.String(snapshotID),
+		})
+		if err != nil {
+			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == rds.ErrCodeDBClusterSnapshotNotFoundFault {
+				snapshotLogger.Debug("cluster snapshot not found, assuming it's been deleted")
+				continue
+			}
+			return nil, errors.WrapLog(err, "failed to describe db cluster snapshots", snapshotLogger)
+		}
+		snapshot := &rdsSnapshot{
+			ID:   snapshotID,
+			ARN:  snapshotARN,
+			Tags: convertAWSTagsToMap(resourceTagMapping.Tags),
+		}
+		if len(dbClusterSnapshots) == 1 {
+			snapshot.CreationTime = dbClusterSnapshots[0].SnapshotCreateTime
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	r.logger.Debugf("found list of %d rds cluster snapshots for cluster", len(snapshots))
+	return snapshots, nil
+}
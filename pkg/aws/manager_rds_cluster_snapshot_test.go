@@ -0,0 +1,193 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRDSClusterSnapshotManager_DeleteResourcesForCluster(t *testing.T) {
+	fakeLogger, err := fakeLogger(func(l *logrus.Entry) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeNow := time.Date(2021, 3, 5, 12, 0, 0, 0, time.UTC)
+	fakeClusterSnapshotReportItem := func(modifyFn func(item *clusterservice.ReportItem)) *clusterservice.ReportItem {
+		return mockReportItem(func(item *clusterservice.ReportItem) {
+			item.ID = fakeARN
+			item.Name = fakeResourceIdentifier
+			item.ResourceType = resourceTypeRDSClusterSnapshot
+			item.Manager = string(managerRDSClusterSnapshot)
+			item.Tags = fakeResourceTags()
+			item.Action = clusterservice.ActionDelete
+			item.ActionStatus = clusterservice.ActionStatusInProgress
+			modifyFn(item)
+		})
+	}
+
+	tests := []struct {
+		name     string
+		modifyFn func(c *rdsClientMock)
+		options  ManagerOptions
+		dryRun   bool
+		want     []*clusterservice.ReportItem
+		wantFn   func(mock *rdsClientMock) error
+		wantErr  string
+	}{
+		{
+			name: "manual cluster snapshots are deleted",
+			want: []*clusterservice.ReportItem{
+				fakeClusterSnapshotReportItem(func(item *clusterservice.ReportItem) {}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.DeleteDBClusterSnapshotWithContextCalls()) != 1 {
+					return errors.New("delete db cluster snapshot call count should be 1")
+				}
+				return nil
+			},
+		},
+		{
+			name:   "no destructive methods are used when dry run is true",
+			dryRun: true,
+			want: []*clusterservice.ReportItem{
+				fakeClusterSnapshotReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusDryRun
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.DeleteDBClusterSnapshotWithContextCalls()) != 0 {
+					return errors.New("delete db cluster snapshot call count should be 0")
+				}
+				return nil
+			},
+		},
+		{
+			name: "automated cluster snapshots are skipped",
+			modifyFn: func(c *rdsClientMock) {
+				c.DescribeDBClusterSnapshotsWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBClusterSnapshotsInput, opts ...request.Option) (*rds.DescribeDBClusterSnapshotsOutput, error) {
+					snapshot := fakeRDSClientDBClusterSnapshot()
+					snapshot.SnapshotType = aws.String("automated")
+					return &rds.DescribeDBClusterSnapshotsOutput{DBClusterSnapshots: []*rds.DBClusterSnapshot{snapshot}}, nil
+				}
+			},
+			want: []*clusterservice.ReportItem{
+				fakeClusterSnapshotReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusSkipped
+					item.StatusReason = "unsupported snapshot type automated"
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.DeleteDBClusterSnapshotWithContextCalls()) != 0 {
+					return errors.New("delete db cluster snapshot call count should be 0")
+				}
+				return nil
+			},
+		},
+		{
+			name: "cluster snapshots which are not found are complete",
+			modifyFn: func(c *rdsClientMock) {
+				c.DescribeDBClusterSnapshotsWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBClusterSnapshotsInput, opts ...request.Option) (*rds.DescribeDBClusterSnapshotsOutput, error) {
+					//the snapshot is found while listing and removed before it's deleted
+					if len(c.DescribeDBClusterSnapshotsWithContextCalls()) == 1 {
+						return &rds.DescribeDBClusterSnapshotsOutput{DBClusterSnapshots: []*rds.DBClusterSnapshot{fakeRDSClientDBClusterSnapshot()}}, nil
+					}
+					return nil, awserr.New(rds.ErrCodeDBClusterSnapshotNotFoundFault, "not found", nil)
+				}
+			},
+			want: []*clusterservice.ReportItem{
+				fakeClusterSnapshotReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusComplete
+				}),
+			},
+		},
+		{
+			name: "cluster snapshots younger than the minimum age of the safety policy are protected",
+			modifyFn: func(c *rdsClientMock) {
+				c.DescribeDBClusterSnapshotsWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBClusterSnapshotsInput, opts ...request.Option) (*rds.DescribeDBClusterSnapshotsOutput, error) {
+					snapshot := fakeRDSClientDBClusterSnapshot()
+					snapshot.SnapshotCreateTime = aws.Time(fakeNow.Add(-time.Hour))
+					return &rds.DescribeDBClusterSnapshotsOutput{DBClusterSnapshots: []*rds.DBClusterSnapshot{snapshot}}, nil
+				}
+			},
+			options: ManagerOptions{Policy: &SafetyPolicy{MinimumAge: 24 * time.Hour, now: func() time.Time { return fakeNow }}},
+			want: []*clusterservice.ReportItem{
+				fakeClusterSnapshotReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusProtected
+					item.StatusReason = "created 1h0m0s ago, less than the minimum age of 24h0m0s"
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.DeleteDBClusterSnapshotWithContextCalls()) != 0 {
+					return errors.New("delete db cluster snapshot call count should be 0")
+				}
+				return nil
+			},
+		},
+		{
+			name: "error when delete cluster snapshot fails",
+			modifyFn: func(c *rdsClientMock) {
+				c.DeleteDBClusterSnapshotWithContextFunc = func(ctx context.Context, in1 *rds.DeleteDBClusterSnapshotInput, opts ...request.Option) (*rds.DeleteDBClusterSnapshotOutput, error) {
+					return nil, errors.New("denied")
+				}
+			},
+			want: []*clusterservice.ReportItem{
+				fakeClusterSnapshotReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusFailed
+					item.StatusReason = "failed to delete rds cluster snapshot: denied"
+				}),
+			},
+			wantErr: "failed to delete rds cluster snapshot: denied",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
+				if tt.modifyFn != nil {
+					tt.modifyFn(c)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			fakeTaggingClient, err := fakeTaggingClient(func(c *taggingClientMock) error {
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := &RDSClusterSnapshotManager{
+				configurable:  configurable{options: tt.options},
+				rdsClient:     fakeClient,
+				taggingClient: fakeTaggingClient,
+				logger:        fakeLogger,
+			}
+			got, err := r.DeleteResourcesForCluster(context.TODO(), fakeClusterId, map[string]string{}, tt.dryRun)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("DeleteResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("DeleteResourcesForCluster() unexpected error = %v", err)
+			}
+			if !equalReportItems(got, tt.want) {
+				t.Errorf("DeleteResourcesForCluster() got = %v, want %v", got, tt.want)
+			}
+			if tt.wantFn != nil {
+				if err := tt.wantFn(fakeClient); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,485 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+	"github.com/sirupsen/logrus"
+)
+
+//fakeRDSClusterClient Fake rds client describing the provided member instances of the fake database cluster
+func fakeRDSClusterClient(t *testing.T, members []*rds.DBInstance, modifyFn func(c *rdsClientMock)) *rdsClientMock {
+	fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
+		c.DescribeDBInstancesWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBInstancesInput, opts ...request.Option) (*rds.DescribeDBInstancesOutput, error) {
+			if len(in1.Filters) != 1 || aws.StringValue(in1.Filters[0].Name) != "db-cluster-id" || aws.StringValue(in1.Filters[0].Values[0]) != fakeRDSClientDBClusterIdentifier {
+				return nil, fmt.Errorf("unexpected filters %v", in1.Filters)
+			}
+			return &rds.DescribeDBInstancesOutput{DBInstances: members}, nil
+		}
+		if modifyFn != nil {
+			modifyFn(c)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fakeClient
+}
+
+func fakeRDSClusterReportItem(modifyFn func(item *clusterservice.ReportItem)) *clusterservice.ReportItem {
+	return mockReportItem(func(item *clusterservice.ReportItem) {
+		item.ID = fakeRDSClientDBClusterARN
+		item.Name = fakeRDSClientDBClusterIdentifier
+		item.ResourceType = resourceTypeRDSCluster
+		item.Account = fakeAccountId
+		item.Manager = string(managerRDSCluster)
+		item.Tags = fakeRDSClientTags()
+		item.Action = clusterservice.ActionDelete
+		modifyFn(item)
+	})
+}
+
+func fakeRDSClusterMemberReportItem(modifyFn func(item *clusterservice.ReportItem)) *clusterservice.ReportItem {
+	return mockReportItem(func(item *clusterservice.ReportItem) {
+		item.ID = fakeRDSClientClusterMemberARN
+		item.Name = fakeRDSClientClusterMemberIdentifier
+		item.ResourceType = resourceTypeRDSInstance
+		item.Account = fakeAccountId
+		item.Manager = string(managerRDSCluster)
+		item.Tags = map[string]string{}
+		item.Action = clusterservice.ActionDelete
+		modifyFn(item)
+	})
+}
+
+func TestRDSClusterManager_DeleteResourcesForCluster(t *testing.T) {
+	fakeLogger, err := fakeLogger(func(l *logrus.Entry) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		rdsClient func() *rdsClientMock
+		options   ManagerOptions
+		clusterId string
+		tags      map[string]string
+		dryRun    bool
+		want      []*clusterservice.ReportItem
+		wantFn    func(mock *rdsClientMock) error
+		wantErr   string
+	}{
+		{
+			name: "error when describing db clusters fails",
+			rdsClient: func() *rdsClientMock {
+				return fakeRDSClusterClient(t, nil, func(c *rdsClientMock) {
+					c.DescribeDBClustersWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBClustersInput, opts ...request.Option) (*rds.DescribeDBClustersOutput, error) {
+						return nil, errors.New("")
+					}
+				})
+			},
+			clusterId: fakeRDSClientTagVal,
+			wantErr:   "failed to describe database clusters: ",
+		},
+		{
+			name: "report empty when no db clusters match the cluster tags",
+			rdsClient: func() *rdsClientMock {
+				return fakeRDSClusterClient(t, nil, nil)
+			},
+			clusterId: fakeRDSClientTagVal,
+			tags:      map[string]string{"addTagKey": "addTagVal"},
+			want:      []*clusterservice.ReportItem{},
+		},
+		{
+			name: "no destructive methods are used when dry run is true",
+			rdsClient: func() *rdsClientMock {
+				return fakeRDSClusterClient(t, []*rds.DBInstance{fakeRDSClientClusterMember()}, nil)
+			},
+			clusterId: fakeRDSClientTagVal,
+			dryRun:    true,
+			want: []*clusterservice.ReportItem{
+				fakeRDSClusterReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusDryRun
+				}),
+				fakeRDSClusterMemberReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusDryRun
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.ModifyDBClusterWithContextCalls()) != 0 || len(mock.DeleteDBInstanceWithContextCalls()) != 0 || len(mock.DeleteDBClusterWithContextCalls()) != 0 {
+					return errors.New("no destructive calls should be made in dry run")
+				}
+				return nil
+			},
+		},
+		{
+			name: "deletion protection is removed and member instances are deleted before the cluster",
+			rdsClient: func() *rdsClientMock {
+				return fakeRDSClusterClient(t, []*rds.DBInstance{fakeRDSClientClusterMember()}, nil)
+			},
+			clusterId: fakeRDSClientTagVal,
+			want: []*clusterservice.ReportItem{
+				fakeRDSClusterReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.StatusReason = "waiting for 1 member instances to be deleted"
+					item.NextCheckAfter = rdsClusterDeletionCheckInterval
+				}),
+				fakeRDSClusterMemberReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.NextCheckAfter = rdsInstanceDeletionCheckInterval
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.ModifyDBClusterWithContextCalls()) != 1 {
+					return errors.New("modify db cluster call count should be 1")
+				}
+				if aws.BoolValue(mock.ModifyDBClusterWithContextCalls()[0].ModifyDBClusterInput.DeletionProtection) {
+					return errors.New("deletion protection should be disabled")
+				}
+				if len(mock.DeleteDBInstanceWithContextCalls()) != 1 {
+					return errors.New("delete db instance call count should be 1")
+				}
+				if !aws.BoolValue(mock.DeleteDBInstanceWithContextCalls()[0].DeleteDBInstanceInput.SkipFinalSnapshot) {
+					return errors.New("skip final snapshot option must be true")
+				}
+				if len(mock.DeleteDBClusterWithContextCalls()) != 0 {
+					return errors.New("db cluster should not be deleted while it has members")
+				}
+				return nil
+			},
+		},
+		{
+			name: "db cluster without member instances is deleted skipping the final snapshot",
+			rdsClient: func() *rdsClientMock {
+				return fakeRDSClusterClient(t, nil, func(c *rdsClientMock) {
+					c.DescribeDBClustersWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBClustersInput, opts ...request.Option) (*rds.DescribeDBClustersOutput, error) {
+						dbCluster := fakeRDSClientDBCluster()
+						dbCluster.DeletionProtection = aws.Bool(false)
+						return &rds.DescribeDBClustersOutput{DBClusters: []*rds.DBCluster{dbCluster}}, nil
+					}
+				})
+			},
+			clusterId: fakeRDSClientTagVal,
+			want: []*clusterservice.ReportItem{
+				fakeRDSClusterReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.NextCheckAfter = rdsClusterDeletionCheckInterval
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.ModifyDBClusterWithContextCalls()) != 0 {
+					return errors.New("modify db cluster call count should be 0")
+				}
+				if len(mock.DeleteDBClusterWithContextCalls()) != 1 {
+					return errors.New("delete db cluster call count should be 1")
+				}
+				if !aws.BoolValue(mock.DeleteDBClusterWithContextCalls()[0].DeleteDBClusterInput.SkipFinalSnapshot) {
+					return errors.New("skip final snapshot option must be true")
+				}
+				return nil
+			},
+		},
+		{
+			name: "final snapshot of db cluster is created before its member instances are deleted",
+			rdsClient: func() *rdsClientMock {
+				return fakeRDSClusterClient(t, []*rds.DBInstance{fakeRDSClientClusterMember()}, func(c *rdsClientMock) {
+					c.DescribeDBClusterSnapshotsWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBClusterSnapshotsInput, opts ...request.Option) (*rds.DescribeDBClusterSnapshotsOutput, error) {
+						return nil, awserr.New(rds.ErrCodeDBClusterSnapshotNotFoundFault, "", nil)
+					}
+					c.CreateDBClusterSnapshotWithContextFunc = func(ctx context.Context, in1 *rds.CreateDBClusterSnapshotInput, opts ...request.Option) (*rds.CreateDBClusterSnapshotOutput, error) {
+						return &rds.CreateDBClusterSnapshotOutput{}, nil
+					}
+				})
+			},
+			options:   ManagerOptions{FinalSnapshot: NewDefaultFinalSnapshotPolicy()},
+			clusterId: fakeRDSClientTagVal,
+			want: []*clusterservice.ReportItem{
+				fakeRDSClusterReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.StatusReason = "creating final snapshot testDBCluster-final"
+					item.FinalSnapshot = "testDBCluster-final"
+					item.NextCheckAfter = rdsClusterDeletionCheckInterval
+				}),
+				fakeRDSClusterMemberReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.StatusReason = "waiting for final snapshot testDBCluster-final of database cluster"
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.CreateDBClusterSnapshotWithContextCalls()) != 1 {
+					return errors.New("create db cluster snapshot call count should be 1")
+				}
+				callInput := mock.CreateDBClusterSnapshotWithContextCalls()[0].CreateDBClusterSnapshotInput
+				if aws.StringValue(callInput.DBClusterSnapshotIdentifier) != "testDBCluster-final" {
+					return fmt.Errorf("unexpected final snapshot identifier %s", aws.StringValue(callInput.DBClusterSnapshotIdentifier))
+				}
+				if len(mock.ModifyDBClusterWithContextCalls()) != 0 || len(mock.DeleteDBInstanceWithContextCalls()) != 0 || len(mock.DeleteDBClusterWithContextCalls()) != 0 {
+					return errors.New("nothing should be modified or deleted before the final snapshot is available")
+				}
+				return nil
+			},
+		},
+		{
+			name: "member instances are deleted once the final snapshot of the db cluster is available",
+			rdsClient: func() *rdsClientMock {
+				return fakeRDSClusterClient(t, []*rds.DBInstance{fakeRDSClientClusterMember()}, nil)
+			},
+			options:   ManagerOptions{FinalSnapshot: NewDefaultFinalSnapshotPolicy()},
+			clusterId: fakeRDSClientTagVal,
+			want: []*clusterservice.ReportItem{
+				fakeRDSClusterReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.StatusReason = "waiting for 1 member instances to be deleted"
+					item.FinalSnapshot = "testDBCluster-final"
+					item.NextCheckAfter = rdsClusterDeletionCheckInterval
+				}),
+				fakeRDSClusterMemberReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.NextCheckAfter = rdsInstanceDeletionCheckInterval
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.CreateDBClusterSnapshotWithContextCalls()) != 0 {
+					return errors.New("create db cluster snapshot should not be called when the final snapshot exists")
+				}
+				if len(mock.DeleteDBInstanceWithContextCalls()) != 1 {
+					return errors.New("delete db instance call count should be 1")
+				}
+				return nil
+			},
+		},
+		{
+			name: "delete is not performed if db cluster is in state deleting",
+			rdsClient: func() *rdsClientMock {
+				return fakeRDSClusterClient(t, nil, func(c *rdsClientMock) {
+					c.DescribeDBClustersWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBClustersInput, opts ...request.Option) (*rds.DescribeDBClustersOutput, error) {
+						dbCluster := fakeRDSClientDBCluster()
+						dbCluster.Status = aws.String(statusDeleting)
+						return &rds.DescribeDBClustersOutput{DBClusters: []*rds.DBCluster{dbCluster}}, nil
+					}
+				})
+			},
+			clusterId: fakeRDSClientTagVal,
+			want: []*clusterservice.ReportItem{
+				fakeRDSClusterReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.StatusReason = "deletion already in progress"
+					item.NextCheckAfter = rdsClusterDeletionCheckInterval
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.ModifyDBClusterWithContextCalls()) != 0 || len(mock.DeleteDBClusterWithContextCalls()) != 0 {
+					return errors.New("db cluster in state deleting should not be modified or deleted")
+				}
+				return nil
+			},
+		},
+		{
+			name: "failure deleting a member instance fails the db cluster",
+			rdsClient: func() *rdsClientMock {
+				return fakeRDSClusterClient(t, []*rds.DBInstance{fakeRDSClientClusterMember()}, func(c *rdsClientMock) {
+					c.DeleteDBInstanceWithContextFunc = func(ctx context.Context, in1 *rds.DeleteDBInstanceInput, opts ...request.Option) (*rds.DeleteDBInstanceOutput, error) {
+						return nil, errors.New("denied")
+					}
+				})
+			},
+			clusterId: fakeRDSClientTagVal,
+			want: []*clusterservice.ReportItem{
+				fakeRDSClusterReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusFailed
+					item.StatusReason = "failed to delete member instance of database cluster: denied"
+				}),
+				fakeRDSClusterMemberReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusFailed
+					item.StatusReason = "failed to delete member instance of database cluster: denied"
+				}),
+			},
+			wantErr: "failed to delete member instance of database cluster: denied",
+		},
+		{
+			name: "db cluster with a member protected by the safety policy is not deleted",
+			rdsClient: func() *rdsClientMock {
+				return fakeRDSClusterClient(t, []*rds.DBInstance{fakeRDSClientClusterMember()}, nil)
+			},
+			options:   ManagerOptions{Policy: &SafetyPolicy{Deny: []string{fakeRDSClientClusterMemberARN}}, FinalSnapshot: NewDefaultFinalSnapshotPolicy()},
+			clusterId: fakeRDSClientTagVal,
+			want: []*clusterservice.ReportItem{
+				fakeRDSClusterReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusProtected
+					item.StatusReason = fmt.Sprintf("member instance %s is protected", fakeRDSClientClusterMemberIdentifier)
+					//protected clusters aren't snapshotted, so no final snapshot is reported
+					item.FinalSnapshot = ""
+				}),
+				fakeRDSClusterMemberReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusProtected
+					item.StatusReason = fmt.Sprintf("matches deny rule %s", fakeRDSClientClusterMemberARN)
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.DeleteDBInstanceWithContextCalls()) != 0 || len(mock.DeleteDBClusterWithContextCalls()) != 0 {
+					return errors.New("nothing should be deleted when a member is protected")
+				}
+				return nil
+			},
+		},
+		{
+			name: "members of a db cluster protected by the safety policy are not deleted",
+			rdsClient: func() *rdsClientMock {
+				return fakeRDSClusterClient(t, []*rds.DBInstance{fakeRDSClientClusterMember()}, nil)
+			},
+			options:   ManagerOptions{Policy: &SafetyPolicy{Deny: []string{fakeRDSClientDBClusterARN}}, FinalSnapshot: NewDefaultFinalSnapshotPolicy()},
+			clusterId: fakeRDSClientTagVal,
+			want: []*clusterservice.ReportItem{
+				fakeRDSClusterReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusProtected
+					item.StatusReason = fmt.Sprintf("matches deny rule %s", fakeRDSClientDBClusterARN)
+					//protected clusters aren't snapshotted, so no final snapshot is reported
+					item.FinalSnapshot = ""
+				}),
+				fakeRDSClusterMemberReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusProtected
+					item.StatusReason = fmt.Sprintf("member of protected database cluster %s", fakeRDSClientDBClusterIdentifier)
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.DeleteDBInstanceWithContextCalls()) != 0 || len(mock.DeleteDBClusterWithContextCalls()) != 0 {
+					return errors.New("nothing should be deleted when the cluster is protected")
+				}
+				return nil
+			},
+		},
+		{
+			name: "db clusters which are not targeted are not deleted",
+			rdsClient: func() *rdsClientMock {
+				return fakeRDSClusterClient(t, []*rds.DBInstance{fakeRDSClientClusterMember()}, nil)
+			},
+			options:   ManagerOptions{Targets: map[string]bool{"arn:aws:rds:eu-west-1:123456789012:cluster:other": true}},
+			clusterId: fakeRDSClientTagVal,
+			want:      []*clusterservice.ReportItem{},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.DeleteDBInstanceWithContextCalls()) != 0 || len(mock.DeleteDBClusterWithContextCalls()) != 0 {
+					return errors.New("resources which are not targeted should not be deleted")
+				}
+				return nil
+			},
+		},
+		{
+			name: "db clusters with member instances which are not targeted are not deleted",
+			rdsClient: func() *rdsClientMock {
+				newMember := fakeRDSClientClusterMember()
+				newMember.DBInstanceIdentifier = aws.String("newDBClusterMember")
+				newMember.DBInstanceArn = aws.String("arn:aws:rds:eu-west-1:123456789012:db:newDBClusterMember")
+				return fakeRDSClusterClient(t, []*rds.DBInstance{fakeRDSClientClusterMember(), newMember}, nil)
+			},
+			options:   ManagerOptions{Targets: map[string]bool{fakeRDSClientDBClusterARN: true, fakeRDSClientClusterMemberARN: true}},
+			clusterId: fakeRDSClientTagVal,
+			want: []*clusterservice.ReportItem{
+				fakeRDSClusterReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusSkipped
+					item.StatusReason = "member instance newDBClusterMember not in plan"
+				}),
+				fakeRDSClusterMemberReportItem(func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusSkipped
+					item.StatusReason = fmt.Sprintf("member of skipped database cluster %s", fakeRDSClientDBClusterIdentifier)
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.DeleteDBInstanceWithContextCalls()) != 0 || len(mock.DeleteDBClusterWithContextCalls()) != 0 {
+					return errors.New("nothing should be deleted when a member instance is not targeted")
+				}
+				return nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := tt.rdsClient()
+			r := &RDSClusterManager{
+				configurable: configurable{options: tt.options},
+				rdsClient:    fakeClient,
+				logger:       fakeLogger,
+			}
+			got, err := r.DeleteResourcesForCluster(context.TODO(), tt.clusterId, tt.tags, tt.dryRun)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("DeleteResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+			} else if err != nil {
+				t.Fatalf("DeleteResourcesForCluster() unexpected error = %v", err)
+			}
+			if tt.want != nil && !equalReportItems(got, tt.want) {
+				t.Errorf("DeleteResourcesForCluster() got = %v, want %v", got, tt.want)
+			}
+			if tt.wantFn != nil {
+				if err := tt.wantFn(fakeClient); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
+
+func TestRDSClusterManager_ListResourcesForCluster(t *testing.T) {
+	fakeLogger, err := fakeLogger(func(l *logrus.Entry) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeCreationTime := time.Date(2021, 3, 4, 10, 30, 0, 0, time.UTC)
+	fakeClient := fakeRDSClusterClient(t, []*rds.DBInstance{fakeRDSClientClusterMember()}, func(c *rdsClientMock) {
+		c.DescribeDBClustersWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBClustersInput, opts ...request.Option) (*rds.DescribeDBClustersOutput, error) {
+			dbCluster := fakeRDSClientDBCluster()
+			dbCluster.ClusterCreateTime = aws.Time(fakeCreationTime)
+			return &rds.DescribeDBClustersOutput{DBClusters: []*rds.DBCluster{dbCluster}}, nil
+		}
+	})
+	r := &RDSClusterManager{
+		rdsClient: fakeClient,
+		logger:    fakeLogger,
+	}
+	got, err := r.ListResourcesForCluster(context.TODO(), fakeRDSClientTagVal, map[string]string{})
+	if err != nil {
+		t.Fatalf("ListResourcesForCluster() unexpected error = %v", err)
+	}
+	want := []*clusterservice.Resource{
+		{
+			ID:           fakeRDSClientDBClusterARN,
+			Name:         fakeRDSClientDBClusterIdentifier,
+			ResourceType: resourceTypeRDSCluster,
+			Account:      fakeAccountId,
+			Manager:      string(managerRDSCluster),
+			Tags:         fakeRDSClientTags(),
+			State:        "available",
+			CreationTime: aws.Time(fakeCreationTime),
+		},
+		{
+			ID:           fakeRDSClientClusterMemberARN,
+			Name:         fakeRDSClientClusterMemberIdentifier,
+			ResourceType: resourceTypeRDSInstance,
+			Account:      fakeAccountId,
+			Manager:      string(managerRDSCluster),
+			Tags:         map[string]string{},
+			State:        "available",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListResourcesForCluster() got = %v, want %v", got, want)
+	}
+	if len(fakeClient.DeleteDBInstanceWithContextCalls()) != 0 || len(fakeClient.DeleteDBClusterWithContextCalls()) != 0 || len(fakeClient.ModifyDBClusterWithContextCalls()) != 0 {
+		t.Error("ListResourcesForCluster() should not modify resources")
+	}
+}
@@ -0,0 +1,198 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+	"github.com/integr8ly/cluster-service/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	loggingKeyRDSGroup = "group-name"
+)
+
+//rdsGroupKind Describes one kind of rds configuration group, e.g. parameter groups, for rdsGroupManager
+type rdsGroupKind struct {
+	resourceType string
+	manager      ResourceManagerType
+	//description Name of the kind used in logs, errors and status reasons, e.g. db parameter group
+	description string
+	//inUseErrorCode Error code returned when deleting a group which is still used by a database
+	inUseErrorCode string
+	//notFoundErrorCode Error code returned when deleting a group which no longer exists
+	notFoundErrorCode string
+	//findReferences Map the names of groups to the databases using them, e.g. db instance my-db
+	findReferences func(ctx context.Context, client rdsClient) (map[string][]string, error)
+	deleteGroup    func(ctx context.Context, client rdsClient, name string) error
+}
+
+//rdsGroupManager Delete rds configuration groups tagged for a cluster once no database uses them
+//Groups can't be deleted while a database uses them, the managers of a kind of group depend on the managers of the databases so they usually run once those are gone
+type rdsGroupManager struct {
+	configurable
+	rdsClient     rdsClient
+	taggingClient taggingClient
+	logger        *logrus.Entry
+	region        string
+	kind          *rdsGroupKind
+}
+
+func newRDSGroupManager(session *session.Session, logger *logrus.Entry, kind *rdsGroupKind) rdsGroupManager {
+	return rdsGroupManager{
+		rdsClient:     rds.New(session),
+		taggingClient: resourcegroupstaggingapi.New(session),
+		logger:        logger.WithField(loggingKeyManager, kind.manager),
+		region:        regionFromSession(session),
+		kind:          kind,
+	}
+}
+
+func (r *rdsGroupManager) GetType() ResourceManagerType {
+	return r.kind.manager
+}
+
+//DeleteResourcesForCluster Delete the groups tagged for a specified cluster, groups still used by a database are skipped
+func (r *rdsGroupManager) DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
+	r.logger.Debugf("deleting %ss for cluster", r.kind.description)
+	groups, err := r.getGroupsForCluster(ctx, clusterId, tags)
+	if err != nil {
+		return nil, err
+	}
+	//references are only looked up when something is deleted, a dry run would report every group as in use by databases it's about to delete
+	var references map[string][]string
+	if !dryRun && len(groups) > 0 {
+		references, err = r.kind.findReferences(ctx, r.rdsClient)
+		if err != nil {
+			return nil, errors.WrapLog(err, fmt.Sprintf("failed to find databases using %ss", r.kind.description), r.logger)
+		}
+	}
+	reportItems := make([]*clusterservice.ReportItem, 0)
+	deletePool := newWorkerPool(r.options.Concurrency)
+	for _, group := range groups {
+		group := group
+		groupLogger := r.logger.WithField(loggingKeyRDSGroup, group.Name)
+		if !r.isTarget(group.ARN) {
+			groupLogger.Debug("resource is not targeted, skipping")
+			continue
+		}
+		groupLogger.Debugf("building report for %s", r.kind.description)
+		reportItem := &clusterservice.ReportItem{
+			ID:           group.ARN,
+			Name:         group.Name,
+			ResourceType: r.kind.resourceType,
+			Region:       r.region,
+			Account:      accountFromARN(group.ARN),
+			Manager:      string(r.kind.manager),
+			Tags:         group.Tags,
+			Action:       clusterservice.ActionDelete,
+			ActionStatus: clusterservice.ActionStatusEmpty,
+		}
+		reportItems = append(reportItems, reportItem)
+		if r.isProtectedWithoutCreationTime(reportItem) {
+			groupLogger.Debug("resource is protected by the safety policy, skipping")
+			continue
+		}
+		if dryRun {
+			groupLogger.Debug("dry run enabled, skipping deletion step")
+			reportItem.ActionStatus = clusterservice.ActionStatusDryRun
+			continue
+		}
+		if users := references[group.Name]; len(users) > 0 {
+			groupLogger.Debugf("%s is used by %d databases, skipping", r.kind.description, len(users))
+			reportItem.ActionStatus = clusterservice.ActionStatusSkipped
+			reportItem.StatusReason = fmt.Sprintf("in use by %s", strings.Join(users, ", "))
+			continue
+		}
+		deletePool.Go(func() error {
+			groupLogger.Debugf("performing deletion of %s", r.kind.description)
+			if err := r.kind.deleteGroup(ctx, r.rdsClient, group.Name); err != nil {
+				if awsErr, ok := err.(awserr.Error); ok {
+					switch awsErr.Code() {
+					case r.kind.inUseErrorCode:
+						//a database started using the group since the references were looked up, or a snapshot still uses it
+						groupLogger.Debugf("%s is in use, skipping", r.kind.description)
+						reportItem.ActionStatus = clusterservice.ActionStatusSkipped
+						reportItem.StatusReason = fmt.Sprintf("%s is in use", r.kind.description)
+						return nil
+					case r.kind.notFoundErrorCode:
+						groupLogger.Debugf("%s not found, assuming it's been deleted", r.kind.description)
+						reportItem.ActionStatus = clusterservice.ActionStatusComplete
+						return nil
+					}
+				}
+				return failReportItem(reportItem, errors.WrapLog(err, fmt.Sprintf("failed to delete %s", r.kind.description), groupLogger))
+			}
+			reportItem.ActionStatus = clusterservice.ActionStatusComplete
+			return nil
+		})
+	}
+	return reportItems, errors.Aggregate(deletePool.Wait())
+}
+
+//ListResourcesForCluster List the groups tagged for a specified cluster without modifying them
+func (r *rdsGroupManager) ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+	r.logger.Debugf("listing %ss for cluster", r.kind.description)
+	groups, err := r.getGroupsForCluster(ctx, clusterId, tags)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*clusterservice.Resource, 0, len(groups))
+	for _, group := range groups {
+		resources = append(resources, &clusterservice.Resource{
+			ID:           group.ARN,
+			Name:         group.Name,
+			ResourceType: r.kind.resourceType,
+			Region:       r.region,
+			Account:      accountFromARN(group.ARN),
+			Manager:      string(r.kind.manager),
+			Tags:         group.Tags,
+		})
+	}
+	return resources, nil
+}
+
+//getGroupsForCluster Get the groups tagged for a cluster using the resource tagging api
+func (r *rdsGroupManager) getGroupsForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*basicResource, error) {
+	getResourcesInput := &resourcegroupstaggingapi.GetResourcesInput{
+		ResourceTypeFilters: aws.StringSlice([]string{r.kind.resourceType}),
+		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
+	}
+	resourceTagMappings, err := getTaggedResources(ctx, r.taggingClient, getResourcesInput)
+	if err != nil {
+		return nil, errors.WrapLog(err, fmt.Sprintf("failed to filter %ss", r.kind.description), r.logger)
+	}
+	var groups []*basicResource
+	for _, resourceTagMapping := range resourceTagMappings {
+		groupARN := aws.StringValue(resourceTagMapping.ResourceARN)
+		groupARNElements := strings.Split(groupARN, ":")
+		groups = append(groups, &basicResource{
+			Name: groupARNElements[len(groupARNElements)-1],
+			ARN:  groupARN,
+			Tags: convertAWSTagsToMap(resourceTagMapping.Tags),
+		})
+	}
+	return groups, nil
+}
+
+//findDBInstanceReferences Map the names of groups to the db instances using them, the groups of an instance are picked by groupNames
+func findDBInstanceReferences(ctx context.Context, client rdsClient, groupNames func(dbInstance *rds.DBInstance) []string) (map[string][]string, error) {
+	dbInstances, err := describeDBInstances(ctx, client, &rds.DescribeDBInstancesInput{})
+	if err != nil {
+		return nil, err
+	}
+	references := map[string][]string{}
+	for _, dbInstance := range dbInstances {
+		for _, groupName := range groupNames(dbInstance) {
+			references[groupName] = append(references[groupName], fmt.Sprintf("db instance %s", aws.StringValue(dbInstance.DBInstanceIdentifier)))
+		}
+	}
+	return references, nil
+}
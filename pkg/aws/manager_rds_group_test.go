@@ -0,0 +1,304 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	fakeRDSGroupName = "testGroup"
+)
+
+//fakeRDSGroupManager Build a manager of a kind of rds group whose tagging client finds a single group of that kind
+func fakeRDSGroupManager(t *testing.T, kind *rdsGroupKind, modifyFn func(c *rdsClientMock)) rdsGroupManager {
+	fakeLogger, err := fakeLogger(func(l *logrus.Entry) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeTaggingClient, err := fakeTaggingClient(func(c *taggingClientMock) error {
+		c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+			if aws.StringValue(in1.ResourceTypeFilters[0]) != kind.resourceType {
+				return nil, fmt.Errorf("unexpected resource type filter %s", aws.StringValue(in1.ResourceTypeFilters[0]))
+			}
+			return &resourcegroupstaggingapi.GetResourcesOutput{
+				ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
+					fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
+						mapping.ResourceARN = aws.String(fakeRDSGroupARN(kind))
+					}),
+				},
+			}, nil
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
+		c.DeleteDBParameterGroupWithContextFunc = func(ctx context.Context, in1 *rds.DeleteDBParameterGroupInput, opts ...request.Option) (*rds.DeleteDBParameterGroupOutput, error) {
+			return &rds.DeleteDBParameterGroupOutput{}, nil
+		}
+		c.DeleteDBClusterParameterGroupWithContextFunc = func(ctx context.Context, in1 *rds.DeleteDBClusterParameterGroupInput, opts ...request.Option) (*rds.DeleteDBClusterParameterGroupOutput, error) {
+			return &rds.DeleteDBClusterParameterGroupOutput{}, nil
+		}
+		c.DeleteOptionGroupWithContextFunc = func(ctx context.Context, in1 *rds.DeleteOptionGroupInput, opts ...request.Option) (*rds.DeleteOptionGroupOutput, error) {
+			return &rds.DeleteOptionGroupOutput{}, nil
+		}
+		if modifyFn != nil {
+			modifyFn(c)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rdsGroupManager{
+		rdsClient:     fakeClient,
+		taggingClient: fakeTaggingClient,
+		logger:        fakeLogger,
+		kind:          kind,
+	}
+}
+
+func fakeRDSGroupARN(kind *rdsGroupKind) string {
+	return fmt.Sprintf("arn:aws:%s:%s", kind.resourceType, fakeRDSGroupName)
+}
+
+func fakeRDSGroupReportItem(kind *rdsGroupKind, modifyFn func(item *clusterservice.ReportItem)) *clusterservice.ReportItem {
+	return mockReportItem(func(item *clusterservice.ReportItem) {
+		item.ID = fakeRDSGroupARN(kind)
+		item.Name = fakeRDSGroupName
+		item.ResourceType = kind.resourceType
+		item.Manager = string(kind.manager)
+		item.Tags = fakeResourceTags()
+		item.Action = clusterservice.ActionDelete
+		modifyFn(item)
+	})
+}
+
+//fakeRDSGroupDeleteCalls Number of delete calls made for any kind of rds group
+func fakeRDSGroupDeleteCalls(mock *rdsClientMock) int {
+	return len(mock.DeleteDBParameterGroupWithContextCalls()) + len(mock.DeleteDBClusterParameterGroupWithContextCalls()) + len(mock.DeleteOptionGroupWithContextCalls())
+}
+
+func TestRDSGroupManager_DeleteResourcesForCluster(t *testing.T) {
+	fakeDBInstanceWithGroups := func() *rds.DBInstance {
+		dbInstance := fakeRDSClientDBInstance()
+		dbInstance.DBParameterGroups = []*rds.DBParameterGroupStatus{{DBParameterGroupName: aws.String(fakeRDSGroupName)}}
+		dbInstance.OptionGroupMemberships = []*rds.OptionGroupMembership{{OptionGroupName: aws.String(fakeRDSGroupName)}}
+		return dbInstance
+	}
+	fakeDBInstancesUsingGroups := func(c *rdsClientMock) {
+		c.DescribeDBInstancesWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBInstancesInput, opts ...request.Option) (*rds.DescribeDBInstancesOutput, error) {
+			return &rds.DescribeDBInstancesOutput{DBInstances: []*rds.DBInstance{fakeDBInstanceWithGroups()}}, nil
+		}
+	}
+
+	tests := []struct {
+		name     string
+		kind     *rdsGroupKind
+		modifyFn func(c *rdsClientMock)
+		dryRun   bool
+		want     []*clusterservice.ReportItem
+		wantFn   func(mock *rdsClientMock) error
+		wantErr  string
+	}{
+		{
+			name: "no destructive methods are used when dry run is true",
+			kind: rdsParameterGroupKind,
+			modifyFn: func(c *rdsClientMock) {
+				c.DescribeDBInstancesWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBInstancesInput, opts ...request.Option) (*rds.DescribeDBInstancesOutput, error) {
+					return nil, errors.New("databases should not be described in dry run")
+				}
+			},
+			dryRun: true,
+			want: []*clusterservice.ReportItem{
+				fakeRDSGroupReportItem(rdsParameterGroupKind, func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusDryRun
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if fakeRDSGroupDeleteCalls(mock) != 0 {
+					return errors.New("delete should not be called in dry run")
+				}
+				return nil
+			},
+		},
+		{
+			name: "parameter groups which are not used are deleted",
+			kind: rdsParameterGroupKind,
+			want: []*clusterservice.ReportItem{
+				fakeRDSGroupReportItem(rdsParameterGroupKind, func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusComplete
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				calls := mock.DeleteDBParameterGroupWithContextCalls()
+				if len(calls) != 1 || aws.StringValue(calls[0].DeleteDBParameterGroupInput.DBParameterGroupName) != fakeRDSGroupName {
+					return errors.New("delete db parameter group should be called once for the group")
+				}
+				return nil
+			},
+		},
+		{
+			name:     "parameter groups used by a db instance are skipped",
+			kind:     rdsParameterGroupKind,
+			modifyFn: fakeDBInstancesUsingGroups,
+			want: []*clusterservice.ReportItem{
+				fakeRDSGroupReportItem(rdsParameterGroupKind, func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusSkipped
+					item.StatusReason = fmt.Sprintf("in use by db instance %s", fakeRDSClientInstanceIdentifier)
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if fakeRDSGroupDeleteCalls(mock) != 0 {
+					return errors.New("delete should not be called for groups in use")
+				}
+				return nil
+			},
+		},
+		{
+			name: "cluster parameter groups used by a db cluster are skipped",
+			kind: rdsClusterParameterGroupKind,
+			modifyFn: func(c *rdsClientMock) {
+				c.DescribeDBClustersWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBClustersInput, opts ...request.Option) (*rds.DescribeDBClustersOutput, error) {
+					dbCluster := fakeRDSClientDBCluster()
+					dbCluster.DBClusterParameterGroup = aws.String(fakeRDSGroupName)
+					return &rds.DescribeDBClustersOutput{DBClusters: []*rds.DBCluster{dbCluster}}, nil
+				}
+			},
+			want: []*clusterservice.ReportItem{
+				fakeRDSGroupReportItem(rdsClusterParameterGroupKind, func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusSkipped
+					item.StatusReason = fmt.Sprintf("in use by db cluster %s", fakeRDSClientDBClusterIdentifier)
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if fakeRDSGroupDeleteCalls(mock) != 0 {
+					return errors.New("delete should not be called for groups in use")
+				}
+				return nil
+			},
+		},
+		{
+			name: "cluster parameter groups which are not used are deleted",
+			kind: rdsClusterParameterGroupKind,
+			want: []*clusterservice.ReportItem{
+				fakeRDSGroupReportItem(rdsClusterParameterGroupKind, func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusComplete
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.DeleteDBClusterParameterGroupWithContextCalls()) != 1 {
+					return errors.New("delete db cluster parameter group call count should be 1")
+				}
+				return nil
+			},
+		},
+		{
+			name:     "option groups used by a db instance are skipped",
+			kind:     rdsOptionGroupKind,
+			modifyFn: fakeDBInstancesUsingGroups,
+			want: []*clusterservice.ReportItem{
+				fakeRDSGroupReportItem(rdsOptionGroupKind, func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusSkipped
+					item.StatusReason = fmt.Sprintf("in use by db instance %s", fakeRDSClientInstanceIdentifier)
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if fakeRDSGroupDeleteCalls(mock) != 0 {
+					return errors.New("delete should not be called for groups in use")
+				}
+				return nil
+			},
+		},
+		{
+			name: "option groups still used by a snapshot are skipped",
+			kind: rdsOptionGroupKind,
+			modifyFn: func(c *rdsClientMock) {
+				c.DeleteOptionGroupWithContextFunc = func(ctx context.Context, in1 *rds.DeleteOptionGroupInput, opts ...request.Option) (*rds.DeleteOptionGroupOutput, error) {
+					return nil, awserr.New(rds.ErrCodeInvalidOptionGroupStateFault, "", nil)
+				}
+			},
+			want: []*clusterservice.ReportItem{
+				fakeRDSGroupReportItem(rdsOptionGroupKind, func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusSkipped
+					item.StatusReason = "option group is in use"
+				}),
+			},
+		},
+		{
+			name: "groups which no longer exist are complete",
+			kind: rdsParameterGroupKind,
+			modifyFn: func(c *rdsClientMock) {
+				c.DeleteDBParameterGroupWithContextFunc = func(ctx context.Context, in1 *rds.DeleteDBParameterGroupInput, opts ...request.Option) (*rds.DeleteDBParameterGroupOutput, error) {
+					return nil, awserr.New(rds.ErrCodeDBParameterGroupNotFoundFault, "", nil)
+				}
+			},
+			want: []*clusterservice.ReportItem{
+				fakeRDSGroupReportItem(rdsParameterGroupKind, func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusComplete
+				}),
+			},
+		},
+		{
+			name: "error when deleting a group fails",
+			kind: rdsParameterGroupKind,
+			modifyFn: func(c *rdsClientMock) {
+				c.DeleteDBParameterGroupWithContextFunc = func(ctx context.Context, in1 *rds.DeleteDBParameterGroupInput, opts ...request.Option) (*rds.DeleteDBParameterGroupOutput, error) {
+					return nil, errors.New("denied")
+				}
+			},
+			want: []*clusterservice.ReportItem{
+				fakeRDSGroupReportItem(rdsParameterGroupKind, func(item *clusterservice.ReportItem) {
+					item.ActionStatus = clusterservice.ActionStatusFailed
+					item.StatusReason = "failed to delete db parameter group: denied"
+				}),
+			},
+			wantErr: "failed to delete db parameter group: denied",
+		},
+		{
+			name: "error when describing databases fails",
+			kind: rdsParameterGroupKind,
+			modifyFn: func(c *rdsClientMock) {
+				c.DescribeDBInstancesWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBInstancesInput, opts ...request.Option) (*rds.DescribeDBInstancesOutput, error) {
+					return nil, errors.New("denied")
+				}
+			},
+			wantErr: "failed to find databases using db parameter groups: denied",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := fakeRDSGroupManager(t, tt.kind, tt.modifyFn)
+			got, err := r.DeleteResourcesForCluster(context.TODO(), fakeClusterId, nil, tt.dryRun)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("DeleteResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+			} else if err != nil {
+				t.Fatalf("DeleteResourcesForCluster() unexpected error = %v", err)
+			}
+			if tt.want != nil && !equalReportItems(got, tt.want) {
+				t.Errorf("DeleteResourcesForCluster()\n\ngot:\n\n%v\n\nwant:\n\n%v\n\n", buildReportItemsString(got), buildReportItemsString(tt.want))
+			}
+			if tt.wantFn != nil {
+				if err := tt.wantFn(r.rdsClient.(*rdsClientMock)); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
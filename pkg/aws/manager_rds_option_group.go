@@ -0,0 +1,61 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	resourceTypeRDSOptionGroup = "rds:og"
+)
+
+var _ PhasedClusterResourceManager = &RDSOptionGroupManager{}
+var _ ConfigurableClusterResourceManager = &RDSOptionGroupManager{}
+
+//rdsOptionGroupKind Custom option groups, used by db instances
+//Snapshots taken with persistent options keep using the option group too, so the manager runs after the snapshot manager, retained snapshots leave the group in use
+var rdsOptionGroupKind = &rdsGroupKind{
+	resourceType:      resourceTypeRDSOptionGroup,
+	manager:           managerRDSOptionGroup,
+	description:       "option group",
+	inUseErrorCode:    rds.ErrCodeInvalidOptionGroupStateFault,
+	notFoundErrorCode: rds.ErrCodeOptionGroupNotFoundFault,
+	findReferences: func(ctx context.Context, client rdsClient) (map[string][]string, error) {
+		return findDBInstanceReferences(ctx, client, func(dbInstance *rds.DBInstance) []string {
+			var groupNames []string
+			for _, optionGroup := range dbInstance.OptionGroupMemberships {
+				groupNames = append(groupNames, aws.StringValue(optionGroup.OptionGroupName))
+			}
+			return groupNames
+		})
+	},
+	deleteGroup: func(ctx context.Context, client rdsClient, name string) error {
+		_, err := client.DeleteOptionGroupWithContext(ctx, &rds.DeleteOptionGroupInput{
+			OptionGroupName: aws.String(name),
+		})
+		return err
+	},
+}
+
+//RDSOptionGroupManager Delete custom option groups once no db instance uses them
+type RDSOptionGroupManager struct {
+	rdsGroupManager
+}
+
+func NewDefaultRDSOptionGroupManager(session *session.Session, logger *logrus.Entry) *RDSOptionGroupManager {
+	return &RDSOptionGroupManager{
+		rdsGroupManager: newRDSGroupManager(session, logger, rdsOptionGroupKind),
+	}
+}
+
+func (r *RDSOptionGroupManager) GetName() string {
+	return "AWS RDS Option Group Manager"
+}
+
+func (r *RDSOptionGroupManager) GetDependencies() []ResourceManagerType {
+	return []ResourceManagerType{managerRDS, managerRDSCluster, managerRDSSnapshot}
+}
@@ -0,0 +1,60 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	resourceTypeRDSParameterGroup = "rds:pg"
+)
+
+var _ PhasedClusterResourceManager = &RDSParameterGroupManager{}
+var _ ConfigurableClusterResourceManager = &RDSParameterGroupManager{}
+
+//rdsParameterGroupKind Custom db parameter groups, used by db instances including the members of db clusters
+var rdsParameterGroupKind = &rdsGroupKind{
+	resourceType:      resourceTypeRDSParameterGroup,
+	manager:           managerRDSParameterGroup,
+	description:       "db parameter group",
+	inUseErrorCode:    rds.ErrCodeInvalidDBParameterGroupStateFault,
+	notFoundErrorCode: rds.ErrCodeDBParameterGroupNotFoundFault,
+	findReferences: func(ctx context.Context, client rdsClient) (map[string][]string, error) {
+		return findDBInstanceReferences(ctx, client, func(dbInstance *rds.DBInstance) []string {
+			var groupNames []string
+			for _, parameterGroup := range dbInstance.DBParameterGroups {
+				groupNames = append(groupNames, aws.StringValue(parameterGroup.DBParameterGroupName))
+			}
+			return groupNames
+		})
+	},
+	deleteGroup: func(ctx context.Context, client rdsClient, name string) error {
+		_, err := client.DeleteDBParameterGroupWithContext(ctx, &rds.DeleteDBParameterGroupInput{
+			DBParameterGroupName: aws.String(name),
+		})
+		return err
+	},
+}
+
+//RDSParameterGroupManager Delete custom db parameter groups once no db instance uses them
+type RDSParameterGroupManager struct {
+	rdsGroupManager
+}
+
+func NewDefaultRDSParameterGroupManager(session *session.Session, logger *logrus.Entry) *RDSParameterGroupManager {
+	return &RDSParameterGroupManager{
+		rdsGroupManager: newRDSGroupManager(session, logger, rdsParameterGroupKind),
+	}
+}
+
+func (r *RDSParameterGroupManager) GetName() string {
+	return "AWS RDS Parameter Group Manager"
+}
+
+func (r *RDSParameterGroupManager) GetDependencies() []ResourceManagerType {
+	return []ResourceManagerType{managerRDS, managerRDSCluster}
+}
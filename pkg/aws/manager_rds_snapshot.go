@@ -1,7 +1,10 @@
 package aws
 
 import (
+	"context"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 
@@ -22,16 +25,21 @@ const (
 )
 
 type rdsSnapshot struct {
-	ID  string
-	ARN string
+	ID           string
+	ARN          string
+	Tags         map[string]string
+	CreationTime *time.Time
 }
 
-var _ ClusterResourceManager = &RDSSnapshotManager{}
+var _ PhasedClusterResourceManager = &RDSSnapshotManager{}
+var _ ConfigurableClusterResourceManager = &RDSSnapshotManager{}
 
 type RDSSnapshotManager struct {
+	configurable
 	rdsClient     rdsClient
 	taggingClient taggingClient
 	logger        *logrus.Entry
+	region        string
 }
 
 func NewDefaultRDSSnapshotManager(session *session.Session, logger *logrus.Entry) *RDSSnapshotManager {
@@ -39,6 +47,7 @@ func NewDefaultRDSSnapshotManager(session *session.Session, logger *logrus.Entry
 		rdsClient:     rds.New(session),
 		taggingClient: resourcegroupstaggingapi.New(session),
 		logger:        logger.WithField(loggingKeyManager, managerRDSSnapshot),
+		region:        regionFromSession(session),
 	}
 }
 
@@ -46,86 +55,183 @@ func (r *RDSSnapshotManager) GetName() string {
 	return "AWS RDS Snapshot Manager"
 }
 
-func (r *RDSSnapshotManager) DeleteResourcesForCluster(clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
+func (r *RDSSnapshotManager) GetType() ResourceManagerType {
+	return managerRDSSnapshot
+}
+
+func (r *RDSSnapshotManager) GetDependencies() []ResourceManagerType {
+	return []ResourceManagerType{managerRDS}
+}
+
+func (r *RDSSnapshotManager) DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
 	r.logger.Debug("delete snapshots for cluster")
-	//filter with tags
-	r.logger.Debug("listing rds snapshots using provided tag filters")
-	getResourcesInput := &resourcegroupstaggingapi.GetResourcesInput{
-		ResourceTypeFilters: aws.StringSlice([]string{resourceTypeRDSSnapshot}),
-		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
-	}
-	getResourcesOutput, err := r.taggingClient.GetResources(getResourcesInput)
+	snapshotsToDelete, err := r.getSnapshotsForCluster(ctx, clusterId, tags)
 	if err != nil {
-		return nil, errors.WrapLog(err, "failed to filter snapshots in aws", r.logger)
-	}
-	//build list of resources to delete
-	var snapshotsToDelete []*rdsSnapshot
-	for _, resourceTagMapping := range getResourcesOutput.ResourceTagMappingList {
-		snapshotARN := aws.StringValue(resourceTagMapping.ResourceARN)
-		//get resource id from arn, should be the last element
-		//strings#Split will always return at least one element https://golang.org/pkg/strings/#Split
-		snapshotARNElements := strings.Split(snapshotARN, ":")
-		snapshotID := snapshotARNElements[len(snapshotARNElements)-1]
-		snapshotsToDelete = append(snapshotsToDelete, &rdsSnapshot{
-			ID:  snapshotID,
-			ARN: snapshotARN,
-		})
+		return nil, err
 	}
-	r.logger.Debugf("found list of %d rds snapshots to delete", len(snapshotsToDelete))
 	//delete and build report
 	var reportItems []*clusterservice.ReportItem
+	deletePool := newWorkerPool(r.options.Concurrency)
 	for _, snapshot := range snapshotsToDelete {
+		snapshot := snapshot
 		snapshotLogger := r.logger.WithField(loggingKeySnapshot, snapshot.ID)
+		if !r.isTarget(snapshot.ARN) {
+			snapshotLogger.Debug("resource is not targeted, skipping")
+			continue
+		}
 		snapshotLogger.Debug("handling deletion for snapshot")
 		//add new item to report list
 		reportItem := &clusterservice.ReportItem{
 			ID:           snapshot.ARN,
 			Name:         snapshot.ID,
+			ResourceType: resourceTypeRDSSnapshot,
+			Region:       r.region,
+			Account:      accountFromARN(snapshot.ARN),
+			Manager:      string(managerRDSSnapshot),
+			Tags:         snapshot.Tags,
 			Action:       clusterservice.ActionDelete,
 			ActionStatus: clusterservice.ActionStatusInProgress,
 		}
 		reportItems = append(reportItems, reportItem)
+		if r.isProtected(reportItem, snapshot.CreationTime) {
+			snapshotLogger.Debug("resource is protected by the safety policy, skipping")
+			continue
+		}
+		if r.isRetained(reportItem) {
+			snapshotLogger.Debug("final snapshot is still retained, skipping")
+			continue
+		}
 		//don't delete in dry run scenario
 		if dryRun {
 			snapshotLogger.Debug("dry run is enabled, skipping deletion")
 			reportItem.ActionStatus = clusterservice.ActionStatusDryRun
 			continue
 		}
-		describeSnapshotInput := &rds.DescribeDBSnapshotsInput{
-			DBSnapshotIdentifier: aws.String(snapshot.ID),
+		deletePool.Go(func() error {
+			describeSnapshotInput := &rds.DescribeDBSnapshotsInput{
+				DBSnapshotIdentifier: aws.String(snapshot.ID),
+			}
+			dbSnapshots, err := describeDBSnapshots(ctx, r.rdsClient, describeSnapshotInput)
+			if err != nil {
+				if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == rds.ErrCodeDBSnapshotNotFoundFault {
+					r.logger.Debug("snapshot not found, assuming it's been deleted")
+					reportItem.ActionStatus = clusterservice.ActionStatusComplete
+					return nil
+				}
+				return failReportItem(reportItem, errors.WrapLog(err, "failed to describe db snapshots", r.logger))
+			}
+			if len(dbSnapshots) != 1 {
+				return failReportItem(reportItem, errors.WrapLog(fmt.Errorf("found %d snapshots", len(dbSnapshots)), "unexpected number of snapshots found", r.logger))
+			}
+			foundSnapshot := dbSnapshots[0]
+			if *foundSnapshot.SnapshotType != "manual" {
+				r.logger.Debugf("unsupported snapshot type %s cannot be deleted, skipping", *foundSnapshot.SnapshotType)
+				reportItem.ActionStatus = clusterservice.ActionStatusSkipped
+				reportItem.StatusReason = fmt.Sprintf("unsupported snapshot type %s", *foundSnapshot.SnapshotType)
+				return nil
+			}
+			if *foundSnapshot.Status != "available" {
+				r.logger.Debugf("snapshot is not in an available state, current state is %s", *foundSnapshot.Status)
+				reportItem.ActionStatus = clusterservice.ActionStatusSkipped
+				reportItem.StatusReason = fmt.Sprintf("snapshot is in state %s", *foundSnapshot.Status)
+				return nil
+
+			}
+			snapshotLogger.Debug("performing deletion request")
+			deleteSnapshotInput := &rds.DeleteDBSnapshotInput{
+				DBSnapshotIdentifier: aws.String(snapshot.ID),
+			}
+			if _, err := r.rdsClient.DeleteDBSnapshotWithContext(ctx, deleteSnapshotInput); err != nil {
+				return failReportItem(reportItem, errors.WrapLog(err, "failed to delete rds snapshot", r.logger))
+			}
+			return nil
+		})
+	}
+	//return final report
+	return reportItems, errors.Aggregate(deletePool.Wait())
+}
+
+//ListResourcesForCluster List RDS snapshots for a specified cluster without modifying them
+func (r *RDSSnapshotManager) ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+	r.logger.Debug("list snapshots for cluster")
+	snapshots, err := r.getSnapshotsForCluster(ctx, clusterId, tags)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*clusterservice.Resource, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		snapshotLogger := r.logger.WithField(loggingKeySnapshot, snapshot.ID)
+		resource := &clusterservice.Resource{
+			ID:           snapshot.ARN,
+			Name:         snapshot.ID,
+			ResourceType: resourceTypeRDSSnapshot,
+			Region:       r.region,
+			Account:      accountFromARN(snapshot.ARN),
+			Manager:      string(managerRDSSnapshot),
+			Tags:         snapshot.Tags,
 		}
-		describeSnapshotOutput, err := r.rdsClient.DescribeDBSnapshots(describeSnapshotInput)
+		dbSnapshots, err := describeDBSnapshots(ctx, r.rdsClient, &rds.DescribeDBSnapshotsInput{
+			DBSnapshotIdentifier: aws.String(snapshot.ID),
+		})
 		if err != nil {
 			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == rds.ErrCodeDBSnapshotNotFoundFault {
-				r.logger.Debug("snapshot not found, assuming it's been deleted")
-				reportItem.ActionStatus = clusterservice.ActionStatusComplete
+				snapshotLogger.Debug("snapshot not found, assuming it's been deleted")
 				continue
 			}
-			return nil, errors.WrapLog(err, "failed to describe db snapshots", r.logger)
+			return nil, errors.WrapLog(err, "failed to describe db snapshots", snapshotLogger)
 		}
-		if len(describeSnapshotOutput.DBSnapshots) != 1 {
-			return nil, errors.WrapLog(err, "unexpected number of snapshots found", r.logger)
+		if len(dbSnapshots) == 1 {
+			resource.State = aws.StringValue(dbSnapshots[0].Status)
+			resource.CreationTime = dbSnapshots[0].SnapshotCreateTime
+			resource.SizeBytes = gibToBytes(dbSnapshots[0].AllocatedStorage)
 		}
-		foundSnapshot := describeSnapshotOutput.DBSnapshots[0]
-		if *foundSnapshot.SnapshotType != "manual" {
-			r.logger.Debugf("unsupported snapshot type %s cannot be deleted, skipping", *foundSnapshot.SnapshotType)
-			reportItem.ActionStatus = clusterservice.ActionStatusSkipped
-			continue
-		}
-		if *foundSnapshot.Status != "available" {
-			r.logger.Debugf("snapshot is not in an available state, current state is %s", *foundSnapshot.Status)
-			reportItem.ActionStatus = clusterservice.ActionStatusSkipped
-			continue
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
 
+//getSnapshotsForCluster Get the RDS snapshots tagged for a cluster using the resource tagging api, along with their creation time
+func (r *RDSSnapshotManager) getSnapshotsForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*rdsSnapshot, error) {
+	//filter with tags
+	r.logger.Debug("listing rds snapshots using provided tag filters")
+	getResourcesInput := &resourcegroupstaggingapi.GetResourcesInput{
+		ResourceTypeFilters: aws.StringSlice([]string{resourceTypeRDSSnapshot}),
+		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
+	}
+	resourceTagMappings, err := getTaggedResources(ctx, r.taggingClient, getResourcesInput)
+	if err != nil {
+		return nil, errors.WrapLog(err, "failed to filter snapshots in aws", r.logger)
+	}
+	//build list of snapshots
+	var snapshots []*rdsSnapshot
+	for _, resourceTagMapping := range resourceTagMappings {
+		snapshotARN := aws.StringValue(resourceTagMapping.ResourceARN)
+		//get resource id from arn, should be the last element
+		//strings#Split will always return at least one element https://golang.org/pkg/strings/#Split
+		snapshotARNElements := strings.Split(snapshotARN, ":")
+		snapshotID := snapshotARNElements[len(snapshotARNElements)-1]
+		snapshotLogger := r.logger.WithField(loggingKeySnapshot, snapshotID)
+		//the tagging api doesn't know the creation time, which the minimum age of the safety policy needs
+		dbSnapshots, err := describeDBSnapshots(ctx, r.rdsClient, &rds.DescribeDBSnapshotsInput{
+			DBSnapshotIdentifier: aws.String(snapshotID),
+		})
+		if err != nil {
+			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == rds.ErrCodeDBSnapshotNotFoundFault {
+				snapshotLogger.Debug("snapshot not found, assuming it's been deleted")
+				continue
+			}
+			return nil, errors.WrapLog(err, "failed to describe db snapshots", snapshotLogger)
 		}
-		snapshotLogger.Debug("performing deletion request")
-		deleteSnapshotInput := &rds.DeleteDBSnapshotInput{
-			DBSnapshotIdentifier: aws.String(snapshot.ID),
+		snapshot := &rdsSnapshot{
+			ID:   snapshotID,
+			ARN:  snapshotARN,
+			Tags: convertAWSTagsToMap(resourceTagMapping.Tags),
 		}
-		if _, err := r.rdsClient.DeleteDBSnapshot(deleteSnapshotInput); err != nil {
-			return nil, errors.WrapLog(err, "failed to delete rds snapshot", r.logger)
+		if len(dbSnapshots) == 1 {
+			snapshot.CreationTime = dbSnapshots[0].SnapshotCreateTime
 		}
+		snapshots = append(snapshots, snapshot)
 	}
-	//return final report
-	return reportItems, nil
+	r.logger.Debugf("found list of %d rds snapshots for cluster", len(snapshots))
+	return snapshots, nil
 }
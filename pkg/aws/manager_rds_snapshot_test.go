@@ -1,17 +1,22 @@
 package aws
 
 import (
+	"context"
 	"errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/rds"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
 	"github.com/integr8ly/cluster-service/pkg/clusterservice"
 	"github.com/sirupsen/logrus"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestRDSSnapshotManager_DeleteResourcesForCluster(t *testing.T) {
 	fakeClusterId := "testClusterId"
+	fakeNow := time.Date(2021, 3, 5, 12, 0, 0, 0, time.UTC)
 	fakeLogger, err := fakeLogger(func(l *logrus.Entry) error {
 		return nil
 	})
@@ -22,6 +27,7 @@ func TestRDSSnapshotManager_DeleteResourcesForCluster(t *testing.T) {
 		rdsClient     func() *rdsClientMock
 		taggingClient func() *taggingClientMock
 		logger        *logrus.Entry
+		options       ManagerOptions
 	}
 	type args struct {
 		clusterId string
@@ -67,6 +73,9 @@ func TestRDSSnapshotManager_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeARN
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeRDSSnapshot
+					item.Manager = string(managerRDSSnapshot)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusInProgress
 
@@ -78,7 +87,7 @@ func TestRDSSnapshotManager_DeleteResourcesForCluster(t *testing.T) {
 			fields: fields{
 				rdsClient: func() *rdsClientMock {
 					fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
-						c.DeleteDBSnapshotFunc = func(in1 *rds.DeleteDBSnapshotInput) (output *rds.DeleteDBSnapshotOutput, e error) {
+						c.DeleteDBSnapshotWithContextFunc = func(ctx context.Context, in1 *rds.DeleteDBSnapshotInput, opts ...request.Option) (output *rds.DeleteDBSnapshotOutput, e error) {
 							return nil, errors.New("")
 						}
 						return nil
@@ -103,7 +112,178 @@ func TestRDSSnapshotManager_DeleteResourcesForCluster(t *testing.T) {
 				clusterId: fakeClusterId,
 				dryRun:    false,
 			},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeARN
+					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeRDSSnapshot
+					item.Manager = string(managerRDSSnapshot)
+					item.Tags = fakeResourceTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusFailed
+					item.StatusReason = "failed to delete rds snapshot: "
+				}),
+			},
 			wantErr: true,
+		}, {
+			name: "snapshots younger than the minimum age of the safety policy are protected",
+			fields: fields{
+				rdsClient: func() *rdsClientMock {
+					fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
+						c.DescribeDBSnapshotsWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBSnapshotsInput, opts ...request.Option) (*rds.DescribeDBSnapshotsOutput, error) {
+							fakeSnapshot := fakeRDSSnapshot()
+							fakeSnapshot.SnapshotCreateTime = aws.Time(fakeNow.Add(-time.Hour))
+							return &rds.DescribeDBSnapshotsOutput{DBSnapshots: []*rds.DBSnapshot{fakeSnapshot}}, nil
+						}
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return fakeClient
+				},
+				taggingClient: func() *taggingClientMock {
+					fakeTaggingClient, err := fakeTaggingClient(func(c *taggingClientMock) error {
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return fakeTaggingClient
+				},
+				logger:  fakeLogger,
+				options: ManagerOptions{Policy: &SafetyPolicy{MinimumAge: 24 * time.Hour, now: func() time.Time { return fakeNow }}},
+			},
+			args: args{
+				clusterId: fakeClusterId,
+				dryRun:    false,
+			},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeARN
+					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeRDSSnapshot
+					item.Manager = string(managerRDSSnapshot)
+					item.Tags = fakeResourceTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusProtected
+					item.StatusReason = "created 1h0m0s ago, less than the minimum age of 24h0m0s"
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.DeleteDBSnapshotWithContextCalls()) != 0 {
+					return errors.New("delete snapshot call count should be 0 for snapshots younger than the minimum age")
+				}
+				return nil
+			},
+		}, {
+			name: "snapshots older than the minimum age of the safety policy are deleted",
+			fields: fields{
+				rdsClient: func() *rdsClientMock {
+					fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
+						c.DescribeDBSnapshotsWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBSnapshotsInput, opts ...request.Option) (*rds.DescribeDBSnapshotsOutput, error) {
+							fakeSnapshot := fakeRDSSnapshot()
+							fakeSnapshot.SnapshotCreateTime = aws.Time(fakeNow.Add(-48 * time.Hour))
+							return &rds.DescribeDBSnapshotsOutput{DBSnapshots: []*rds.DBSnapshot{fakeSnapshot}}, nil
+						}
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return fakeClient
+				},
+				taggingClient: func() *taggingClientMock {
+					fakeTaggingClient, err := fakeTaggingClient(func(c *taggingClientMock) error {
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return fakeTaggingClient
+				},
+				logger:  fakeLogger,
+				options: ManagerOptions{Policy: &SafetyPolicy{MinimumAge: 24 * time.Hour, now: func() time.Time { return fakeNow }}},
+			},
+			args: args{
+				clusterId: fakeClusterId,
+				dryRun:    false,
+			},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeARN
+					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeRDSSnapshot
+					item.Manager = string(managerRDSSnapshot)
+					item.Tags = fakeResourceTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusInProgress
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.DeleteDBSnapshotWithContextCalls()) != 1 {
+					return errors.New("delete snapshot call count should be 1")
+				}
+				return nil
+			},
+		}, {
+			name: "final snapshots are not deleted until their retention elapses",
+			fields: fields{
+				rdsClient: func() *rdsClientMock {
+					fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return fakeClient
+				},
+				taggingClient: func() *taggingClientMock {
+					fakeTaggingClient, err := fakeTaggingClient(func(c *taggingClientMock) error {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+							return &resourcegroupstaggingapi.GetResourcesOutput{
+								ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
+									fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
+										mapping.Tags = append(mapping.Tags, &resourcegroupstaggingapi.Tag{
+											Key:   aws.String(TagKeyRetainUntil),
+											Value: aws.String("2999-01-01T00:00:00Z"),
+										})
+									}),
+								},
+							}, nil
+						}
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return fakeTaggingClient
+				},
+				logger: fakeLogger,
+			},
+			args: args{
+				clusterId: fakeClusterId,
+				dryRun:    false,
+			},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeARN
+					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeRDSSnapshot
+					item.Manager = string(managerRDSSnapshot)
+					item.Tags = fakeResourceTags()
+					item.Tags[TagKeyRetainUntil] = "2999-01-01T00:00:00Z"
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusRetained
+					item.StatusReason = "retained until 2999-01-01T00:00:00Z"
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.DeleteDBSnapshotWithContextCalls()) != 0 {
+					return errors.New("delete snapshot call count should be 0 while the snapshot is retained")
+				}
+				return nil
+			},
 		}, {
 			name: "pass when no report is returned if no snapshots deleted ",
 			fields: fields{
@@ -118,7 +298,7 @@ func TestRDSSnapshotManager_DeleteResourcesForCluster(t *testing.T) {
 				},
 				taggingClient: func() *taggingClientMock {
 					fakeTaggingClient, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (output *resourcegroupstaggingapi.GetResourcesOutput, err error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (output *resourcegroupstaggingapi.GetResourcesOutput, err error) {
 							return nil, errors.New("")
 						}
 						return nil
@@ -142,7 +322,7 @@ func TestRDSSnapshotManager_DeleteResourcesForCluster(t *testing.T) {
 			fields: fields{
 				rdsClient: func() *rdsClientMock {
 					fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
-						c.DescribeDBSnapshotsFunc = func(in1 *rds.DescribeDBSnapshotsInput) (*rds.DescribeDBSnapshotsOutput, error) {
+						c.DescribeDBSnapshotsWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBSnapshotsInput, opts ...request.Option) (*rds.DescribeDBSnapshotsOutput, error) {
 							return &rds.DescribeDBSnapshotsOutput{
 								DBSnapshots: []*rds.DBSnapshot{
 									fakeRDSSnapshot(),
@@ -176,12 +356,15 @@ func TestRDSSnapshotManager_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeARN
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeRDSSnapshot
+					item.Manager = string(managerRDSSnapshot)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusDryRun
 				}),
 			},
 			wantFn: func(mock *rdsClientMock) error {
-				if len(mock.DeleteDBSnapshotCalls()) != 0 {
+				if len(mock.DeleteDBSnapshotWithContextCalls()) != 0 {
 					return errors.New("delete snapshot call count should be 0 as dry run is true")
 				}
 				return nil
@@ -192,11 +375,12 @@ func TestRDSSnapshotManager_DeleteResourcesForCluster(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			fakeClient := tt.fields.rdsClient()
 			r := &RDSSnapshotManager{
+				configurable:  configurable{options: tt.fields.options},
 				rdsClient:     fakeClient,
 				taggingClient: tt.fields.taggingClient(),
 				logger:        tt.fields.logger,
 			}
-			got, err := r.DeleteResourcesForCluster(tt.args.clusterId, tt.args.tags, tt.args.dryRun)
+			got, err := r.DeleteResourcesForCluster(context.TODO(), tt.args.clusterId, tt.args.tags, tt.args.dryRun)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("DeleteResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
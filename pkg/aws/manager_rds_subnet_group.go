@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -18,24 +19,29 @@ const (
 	resourceTypeDBSubnetGroup = "rds:subgrp"
 )
 
-var _ ClusterResourceManager = &RDSSubnetGroupManager{}
+var _ PhasedClusterResourceManager = &RDSSubnetGroupManager{}
+var _ ConfigurableClusterResourceManager = &RDSSubnetGroupManager{}
 
 type RDSSubnetGroup struct {
 	Name string
 	ARN  string
+	Tags map[string]string
 }
 
 type RDSSubnetGroupManager struct {
+	configurable
 	rdsClient     rdsClient
 	taggingClient taggingClient
 	logger        *logrus.Entry
+	region        string
 }
 
 func NewDefaultRDSSubnetGroupManager(session *session.Session, logger *logrus.Entry) *RDSSubnetGroupManager {
 	return &RDSSubnetGroupManager{
 		rdsClient:     rds.New(session),
 		taggingClient: resourcegroupstaggingapi.New(session),
-		logger:        logger.WithField("engine", managerRDS),
+		logger:        logger.WithField("engine", managerRDSSubnetGroup),
+		region:        regionFromSession(session),
 	}
 }
 
@@ -43,64 +49,138 @@ func (r *RDSSubnetGroupManager) GetName() string {
 	return "AWS RDS Subnet Group Manager"
 }
 
+func (r *RDSSubnetGroupManager) GetType() ResourceManagerType {
+	return managerRDSSubnetGroup
+}
+
+func (r *RDSSubnetGroupManager) GetDependencies() []ResourceManagerType {
+	return []ResourceManagerType{managerRDS, managerRDSCluster}
+}
+
 // Delete all RDS Subnet Groups for a specified cluster
-func (r *RDSSubnetGroupManager) DeleteResourcesForCluster(clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
+func (r *RDSSubnetGroupManager) DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
 	r.logger.Debug("deleting resources for cluster")
-	r.logger.Debug("listing rds subnet groups using provided tag filters")
-	getResourcesInput := &resourcegroupstaggingapi.GetResourcesInput{
-		ResourceTypeFilters: aws.StringSlice([]string{resourceTypeDBSubnetGroup}),
-		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
-	}
-	getResourcesOutput, err := r.taggingClient.GetResources(getResourcesInput)
+	subnetGroupsToDelete, err := r.getSubnetGroupsForCluster(ctx, clusterId, tags)
 	if err != nil {
-		return nil, errors.WrapLog(err, "failed to filter rds subnet groups", r.logger)
-	}
-
-	var subnetGroupsToDelete []*RDSSubnetGroup
-
-	for _, resourceTagMapping := range getResourcesOutput.ResourceTagMappingList {
-		subnetGroupARNElements := strings.Split(*resourceTagMapping.ResourceARN, ":")
-		subnetGroupName := subnetGroupARNElements[len(subnetGroupARNElements)-1]
-
-		subnetGroupsToDelete = append(subnetGroupsToDelete, &RDSSubnetGroup{
-			Name: subnetGroupName,
-			ARN:  *resourceTagMapping.ResourceARN,
-		})
+		return nil, err
 	}
 
 	reportItems := make([]*clusterservice.ReportItem, 0)
+	deletePool := newWorkerPool(r.options.Concurrency)
 
 	for _, dbSubnetGroup := range subnetGroupsToDelete {
+		dbSubnetGroup := dbSubnetGroup
 		subnetGroupLogger := r.logger.WithField(loggingKeySubnetGroup, dbSubnetGroup.Name)
+		if !r.isTarget(dbSubnetGroup.ARN) {
+			subnetGroupLogger.Debug("resource is not targeted, skipping")
+			continue
+		}
 		subnetGroupLogger.Debug("creating report for rds subnet group")
 
 		reportItem := &clusterservice.ReportItem{
 			ID:           dbSubnetGroup.ARN,
 			Name:         dbSubnetGroup.Name,
+			ResourceType: resourceTypeDBSubnetGroup,
+			Region:       r.region,
+			Account:      accountFromARN(dbSubnetGroup.ARN),
+			Manager:      string(managerRDSSubnetGroup),
+			Tags:         dbSubnetGroup.Tags,
 			Action:       clusterservice.ActionDelete,
 			ActionStatus: clusterservice.ActionStatusEmpty,
 		}
 		reportItems = append(reportItems, reportItem)
+		if r.isProtectedWithoutCreationTime(reportItem) {
+			subnetGroupLogger.Debug("resource is protected by the safety policy, skipping")
+			continue
+		}
 
 		if dryRun {
 			subnetGroupLogger.Debug("dry run enabled, skipping deletion step")
 			reportItem.ActionStatus = clusterservice.ActionStatusDryRun
 			continue
 		}
-		deleteInput := &rds.DeleteDBSubnetGroupInput{
-			DBSubnetGroupName: aws.String(dbSubnetGroup.Name),
-		}
+		deletePool.Go(func() error {
+			deleteInput := &rds.DeleteDBSubnetGroupInput{
+				DBSubnetGroupName: aws.String(dbSubnetGroup.Name),
+			}
 
-		_, err := r.rdsClient.DeleteDBSubnetGroup(deleteInput)
+			_, err := r.rdsClient.DeleteDBSubnetGroupWithContext(ctx, deleteInput)
+			if err != nil {
+				if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "InvalidDBSubnetGroupStateFault" {
+					subnetGroupLogger.Debug("the DB subnet group cannot be deleted because it's in use, skipping")
+					reportItem.ActionStatus = clusterservice.ActionStatusSkipped
+					reportItem.StatusReason = "subnet group is in use"
+					return nil
+				}
+				return failReportItem(reportItem, errors.WrapLog(err, "failed to delete rds db subnet group", subnetGroupLogger))
+			}
+			reportItem.ActionStatus = clusterservice.ActionStatusComplete
+			return nil
+		})
+	}
+	return reportItems, errors.Aggregate(deletePool.Wait())
+}
+
+//ListResourcesForCluster List RDS Subnet Groups for a specified cluster without modifying them
+func (r *RDSSubnetGroupManager) ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+	r.logger.Debug("listing resources for cluster")
+	subnetGroups, err := r.getSubnetGroupsForCluster(ctx, clusterId, tags)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*clusterservice.Resource, 0, len(subnetGroups))
+	for _, dbSubnetGroup := range subnetGroups {
+		subnetGroupLogger := r.logger.WithField(loggingKeySubnetGroup, dbSubnetGroup.Name)
+		resource := &clusterservice.Resource{
+			ID:           dbSubnetGroup.ARN,
+			Name:         dbSubnetGroup.Name,
+			ResourceType: resourceTypeDBSubnetGroup,
+			Region:       r.region,
+			Account:      accountFromARN(dbSubnetGroup.ARN),
+			Manager:      string(managerRDSSubnetGroup),
+			Tags:         dbSubnetGroup.Tags,
+		}
+		describeOutput, err := r.rdsClient.DescribeDBSubnetGroupsWithContext(ctx, &rds.DescribeDBSubnetGroupsInput{
+			DBSubnetGroupName: aws.String(dbSubnetGroup.Name),
+		})
 		if err != nil {
-			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "InvalidDBSubnetGroupStateFault" {
-				subnetGroupLogger.Debug("the DB subnet group cannot be deleted because it's in use, skipping")
-				reportItem.ActionStatus = clusterservice.ActionStatusSkipped
+			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == rds.ErrCodeDBSubnetGroupNotFoundFault {
+				subnetGroupLogger.Debug("subnet group not found, assuming it's been deleted")
 				continue
 			}
-			return nil, errors.WrapLog(err, "failed to delete rds db subnet group", subnetGroupLogger)
+			return nil, errors.WrapLog(err, "failed to describe rds db subnet group", subnetGroupLogger)
+		}
+		if len(describeOutput.DBSubnetGroups) == 1 {
+			resource.State = aws.StringValue(describeOutput.DBSubnetGroups[0].SubnetGroupStatus)
 		}
-		reportItem.ActionStatus = clusterservice.ActionStatusComplete
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+//getSubnetGroupsForCluster Get the RDS Subnet Groups tagged for a cluster using the resource tagging api
+func (r *RDSSubnetGroupManager) getSubnetGroupsForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*RDSSubnetGroup, error) {
+	r.logger.Debug("listing rds subnet groups using provided tag filters")
+	getResourcesInput := &resourcegroupstaggingapi.GetResourcesInput{
+		ResourceTypeFilters: aws.StringSlice([]string{resourceTypeDBSubnetGroup}),
+		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
+	}
+	resourceTagMappings, err := getTaggedResources(ctx, r.taggingClient, getResourcesInput)
+	if err != nil {
+		return nil, errors.WrapLog(err, "failed to filter rds subnet groups", r.logger)
+	}
+
+	var subnetGroups []*RDSSubnetGroup
+
+	for _, resourceTagMapping := range resourceTagMappings {
+		subnetGroupARNElements := strings.Split(*resourceTagMapping.ResourceARN, ":")
+		subnetGroupName := subnetGroupARNElements[len(subnetGroupARNElements)-1]
+
+		subnetGroups = append(subnetGroups, &RDSSubnetGroup{
+			Name: subnetGroupName,
+			ARN:  *resourceTagMapping.ResourceARN,
+			Tags: convertAWSTagsToMap(resourceTagMapping.Tags),
+		})
 	}
-	return reportItems, nil
+	return subnetGroups, nil
 }
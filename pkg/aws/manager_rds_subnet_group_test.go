@@ -1,10 +1,12 @@
 package aws
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/rds"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
 	"github.com/integr8ly/cluster-service/pkg/clusterservice"
@@ -54,7 +56,7 @@ func TestRDSSubnetGroupManager_DeleteResourcesForCluster(t *testing.T) {
 				},
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
 							return nil, errors.New("")
 						}
 						return nil
@@ -86,7 +88,7 @@ func TestRDSSubnetGroupManager_DeleteResourcesForCluster(t *testing.T) {
 				},
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
 							return &resourcegroupstaggingapi.GetResourcesOutput{}, nil
 						}
 						return nil
@@ -119,7 +121,7 @@ func TestRDSSubnetGroupManager_DeleteResourcesForCluster(t *testing.T) {
 				},
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
 							return &resourcegroupstaggingapi.GetResourcesOutput{
 								ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
 									fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {}),
@@ -144,12 +146,15 @@ func TestRDSSubnetGroupManager_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeRDSClientInstanceARN
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeDBSubnetGroup
+					item.Manager = string(managerRDSSubnetGroup)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusDryRun
 				}),
 			},
 			wantFn: func(mock *rdsClientMock) error {
-				if len(mock.DeleteDBSubnetGroupCalls()) != 0 {
+				if len(mock.DeleteDBSubnetGroupWithContextCalls()) != 0 {
 					return errors.New("delete db subnet groups call count should be 0")
 				}
 				return nil
@@ -160,7 +165,7 @@ func TestRDSSubnetGroupManager_DeleteResourcesForCluster(t *testing.T) {
 			fields: fields{
 				rdsClient: func() *rdsClientMock {
 					fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
-						c.DeleteDBSubnetGroupFunc = func(in *rds.DeleteDBSubnetGroupInput) (*rds.DeleteDBSubnetGroupOutput, error) {
+						c.DeleteDBSubnetGroupWithContextFunc = func(ctx context.Context, in *rds.DeleteDBSubnetGroupInput, opts ...request.Option) (*rds.DeleteDBSubnetGroupOutput, error) {
 							errorMsg := ""
 							return nil, awserr.New("InvalidDBSubnetGroupStateFault", errorMsg, errors.New(errorMsg))
 						}
@@ -173,7 +178,7 @@ func TestRDSSubnetGroupManager_DeleteResourcesForCluster(t *testing.T) {
 				},
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
 							return &resourcegroupstaggingapi.GetResourcesOutput{
 								ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
 									fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {}),
@@ -198,12 +203,16 @@ func TestRDSSubnetGroupManager_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeRDSClientInstanceARN
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeDBSubnetGroup
+					item.Manager = string(managerRDSSubnetGroup)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusSkipped
+					item.StatusReason = "subnet group is in use"
 				}),
 			},
 			wantFn: func(mock *rdsClientMock) error {
-				if len(mock.DeleteDBSubnetGroupCalls()) != 1 {
+				if len(mock.DeleteDBSubnetGroupWithContextCalls()) != 1 {
 					return errors.New("delete db subnet groups call count should be 1")
 				}
 				return nil
@@ -214,7 +223,7 @@ func TestRDSSubnetGroupManager_DeleteResourcesForCluster(t *testing.T) {
 			fields: fields{
 				rdsClient: func() *rdsClientMock {
 					fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
-						c.DeleteDBSubnetGroupFunc = func(in *rds.DeleteDBSubnetGroupInput) (*rds.DeleteDBSubnetGroupOutput, error) {
+						c.DeleteDBSubnetGroupWithContextFunc = func(ctx context.Context, in *rds.DeleteDBSubnetGroupInput, opts ...request.Option) (*rds.DeleteDBSubnetGroupOutput, error) {
 							return &rds.DeleteDBSubnetGroupOutput{}, nil
 						}
 						return nil
@@ -226,7 +235,7 @@ func TestRDSSubnetGroupManager_DeleteResourcesForCluster(t *testing.T) {
 				},
 				taggingClient: func() *taggingClientMock {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
 							return &resourcegroupstaggingapi.GetResourcesOutput{
 								ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
 									fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {}),
@@ -251,6 +260,9 @@ func TestRDSSubnetGroupManager_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeRDSClientInstanceARN
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeDBSubnetGroup
+					item.Manager = string(managerRDSSubnetGroup)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusComplete
 				}),
@@ -266,7 +278,7 @@ func TestRDSSubnetGroupManager_DeleteResourcesForCluster(t *testing.T) {
 				taggingClient: fakeTaggingClient,
 				logger:        tt.fields.logger,
 			}
-			got, err := r.DeleteResourcesForCluster(tt.args.clusterId, tt.args.tags, tt.args.dryRun)
+			got, err := r.DeleteResourcesForCluster(context.TODO(), tt.args.clusterId, tt.args.tags, tt.args.dryRun)
 			if tt.wantErr != "" && err.Error() != tt.wantErr {
 				t.Errorf("DeleteResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -1,12 +1,16 @@
 package aws
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 
 	"github.com/aws/aws-sdk-go/service/rds"
 	"github.com/integr8ly/cluster-service/pkg/clusterservice"
@@ -25,6 +29,7 @@ func TestRDSEngine_DeleteResourcesForCluster(t *testing.T) {
 	type fields struct {
 		rdsClient func() *rdsClientMock
 		logger    *logrus.Entry
+		options   ManagerOptions
 	}
 	type args struct {
 		clusterId string
@@ -46,7 +51,7 @@ func TestRDSEngine_DeleteResourcesForCluster(t *testing.T) {
 				logger: fakeLogger,
 				rdsClient: func() *rdsClientMock {
 					fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
-						c.DescribeDBInstancesFunc = func(in1 *rds.DescribeDBInstancesInput) (output *rds.DescribeDBInstancesOutput, e error) {
+						c.DescribeDBInstancesWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBInstancesInput, opts ...request.Option) (output *rds.DescribeDBInstancesOutput, e error) {
 							return nil, errors.New("")
 						}
 						return nil
@@ -69,7 +74,7 @@ func TestRDSEngine_DeleteResourcesForCluster(t *testing.T) {
 			fields: fields{
 				rdsClient: func() *rdsClientMock {
 					fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
-						c.ListTagsForResourceFunc = func(in1 *rds.ListTagsForResourceInput) (output *rds.ListTagsForResourceOutput, e error) {
+						c.ListTagsForResourceWithContextFunc = func(ctx context.Context, in1 *rds.ListTagsForResourceInput, opts ...request.Option) (output *rds.ListTagsForResourceOutput, e error) {
 							return nil, errors.New("")
 						}
 						return nil
@@ -157,16 +162,20 @@ func TestRDSEngine_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeRDSClientInstanceARN
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeRDSInstance
+					item.Manager = string(managerRDS)
+					item.Tags = fakeRDSClientTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.NextCheckAfter = rdsInstanceDeletionCheckInterval
 
 				}),
 			},
 			wantFn: func(mock *rdsClientMock) error {
-				if len(mock.ModifyDBInstanceCalls()) != 1 {
+				if len(mock.ModifyDBInstanceWithContextCalls()) != 1 {
 					return errors.New("modify db instance call count should be 1")
 				}
-				if len(mock.DeleteDBInstanceCalls()) != 1 {
+				if len(mock.DeleteDBInstanceWithContextCalls()) != 1 {
 					return errors.New("delete db instance call count should be 1")
 				}
 				return nil
@@ -177,7 +186,7 @@ func TestRDSEngine_DeleteResourcesForCluster(t *testing.T) {
 			fields: fields{
 				rdsClient: func() *rdsClientMock {
 					fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
-						c.DescribeDBInstancesFunc = func(in1 *rds.DescribeDBInstancesInput) (output *rds.DescribeDBInstancesOutput, e error) {
+						c.DescribeDBInstancesWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBInstancesInput, opts ...request.Option) (output *rds.DescribeDBInstancesOutput, e error) {
 							fakeDBInstance := fakeRDSClientDBInstance()
 							fakeDBInstance.DeletionProtection = aws.Bool(false)
 							return &rds.DescribeDBInstancesOutput{
@@ -204,16 +213,20 @@ func TestRDSEngine_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeRDSClientInstanceARN
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeRDSInstance
+					item.Manager = string(managerRDS)
+					item.Tags = fakeRDSClientTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.NextCheckAfter = rdsInstanceDeletionCheckInterval
 
 				}),
 			},
 			wantFn: func(mock *rdsClientMock) error {
-				if len(mock.ModifyDBInstanceCalls()) != 0 {
+				if len(mock.ModifyDBInstanceWithContextCalls()) != 0 {
 					return errors.New("modify db instance call count should be 0")
 				}
-				if len(mock.DeleteDBInstanceCalls()) != 1 {
+				if len(mock.DeleteDBInstanceWithContextCalls()) != 1 {
 					return errors.New("delete db instance call count should be 1")
 				}
 				return nil
@@ -242,20 +255,79 @@ func TestRDSEngine_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeRDSClientInstanceARN
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeRDSInstance
+					item.Manager = string(managerRDS)
+					item.Tags = fakeRDSClientTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusDryRun
 				}),
 			},
 			wantFn: func(mock *rdsClientMock) error {
-				if len(mock.ModifyDBInstanceCalls()) != 0 {
+				if len(mock.ModifyDBInstanceWithContextCalls()) != 0 {
 					return errors.New("modify db instance call count should be 0")
 				}
-				if len(mock.DeleteDBInstanceCalls()) != 0 {
+				if len(mock.DeleteDBInstanceWithContextCalls()) != 0 {
 					return errors.New("delete db instance call count should be 0")
 				}
 				return nil
 			},
 		},
+		{
+			name: "db instances on every page of the describe output are deleted",
+			fields: fields{
+				rdsClient: func() *rdsClientMock {
+					fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
+						c.DescribeDBInstancesPagesWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBInstancesInput, fn func(*rds.DescribeDBInstancesOutput, bool) bool, opts ...request.Option) error {
+							fakeDBInstance := fakeRDSClientDBInstance()
+							fakeDBInstance.DBInstanceIdentifier = aws.String("second")
+							fakeDBInstance.DBInstanceArn = aws.String("arn:aws:rds:eu-west-1:123456789012:db:second")
+							if fn(&rds.DescribeDBInstancesOutput{DBInstances: []*rds.DBInstance{fakeRDSClientDBInstance()}}, false) {
+								fn(&rds.DescribeDBInstancesOutput{DBInstances: []*rds.DBInstance{fakeDBInstance}}, true)
+							}
+							return nil
+						}
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return fakeClient
+				},
+				logger: fakeLogger,
+			},
+			args: args{
+				clusterId: fakeRDSClientTagVal,
+				tags:      map[string]string{},
+				dryRun:    true,
+			},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeRDSClientInstanceARN
+					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeRDSInstance
+					item.Manager = string(managerRDS)
+					item.Tags = fakeRDSClientTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusDryRun
+				}),
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = "arn:aws:rds:eu-west-1:123456789012:db:second"
+					item.Name = "second"
+					item.ResourceType = resourceTypeRDSInstance
+					item.Account = "123456789012"
+					item.Manager = string(managerRDS)
+					item.Tags = fakeRDSClientTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusDryRun
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.DescribeDBInstancesPagesWithContextCalls()) != 1 {
+					return errors.New("describe db instances pages call count should be 1")
+				}
+				return nil
+			},
+		},
 		{
 			name: "delete is not performed if db instance is in state deleting",
 			fields: fields{
@@ -263,7 +335,7 @@ func TestRDSEngine_DeleteResourcesForCluster(t *testing.T) {
 					fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
 						fakeDBInstance := fakeRDSClientDBInstance()
 						fakeDBInstance.DBInstanceStatus = aws.String(statusDeleting)
-						c.DescribeDBInstancesFunc = func(in1 *rds.DescribeDBInstancesInput) (output *rds.DescribeDBInstancesOutput, e error) {
+						c.DescribeDBInstancesWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBInstancesInput, opts ...request.Option) (output *rds.DescribeDBInstancesOutput, e error) {
 							return &rds.DescribeDBInstancesOutput{
 								DBInstances: []*rds.DBInstance{
 									fakeDBInstance,
@@ -288,13 +360,18 @@ func TestRDSEngine_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeRDSClientInstanceARN
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeRDSInstance
+					item.Manager = string(managerRDS)
+					item.Tags = fakeRDSClientTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.NextCheckAfter = rdsInstanceDeletionCheckInterval
+					item.StatusReason = "deletion already in progress"
 
 				}),
 			},
 			wantFn: func(mock *rdsClientMock) error {
-				if len(mock.DeleteDBInstanceCalls()) != 0 {
+				if len(mock.DeleteDBInstanceWithContextCalls()) != 0 {
 					return errors.New("delete db instance call count should be 0")
 				}
 				return nil
@@ -323,16 +400,20 @@ func TestRDSEngine_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeRDSClientInstanceARN
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeRDSInstance
+					item.Manager = string(managerRDS)
+					item.Tags = fakeRDSClientTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.NextCheckAfter = rdsInstanceDeletionCheckInterval
 				}),
 			},
 			wantFn: func(mock *rdsClientMock) error {
-				if len(mock.DeleteDBInstanceCalls()) != 1 {
+				if len(mock.DeleteDBInstanceWithContextCalls()) != 1 {
 					return errors.New("delete db instance call count should be 1")
 				}
 
-				callInput := mock.DeleteDBInstanceCalls()[0].DeleteDBInstanceInput
+				callInput := mock.DeleteDBInstanceWithContextCalls()[0].DeleteDBInstanceInput
 				if !aws.BoolValue(callInput.DeleteAutomatedBackups) {
 					return errors.New("delete automated backups option must be true when deleting db instance")
 				}
@@ -342,15 +423,269 @@ func TestRDSEngine_DeleteResourcesForCluster(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name: "db instances protected by the safety policy are reported and not deleted",
+			fields: fields{
+				rdsClient: func() *rdsClientMock {
+					fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return fakeClient
+				},
+				logger:  fakeLogger,
+				options: ManagerOptions{Policy: &SafetyPolicy{Deny: []string{fakeRDSClientInstanceARN}}},
+			},
+			args: args{
+				clusterId: fakeRDSClientTagVal,
+				tags:      map[string]string{},
+				dryRun:    false,
+			},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeRDSClientInstanceARN
+					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeRDSInstance
+					item.Manager = string(managerRDS)
+					item.Tags = fakeRDSClientTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusProtected
+					item.StatusReason = fmt.Sprintf("matches deny rule %s", fakeRDSClientInstanceARN)
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.DeleteDBInstanceWithContextCalls()) != 0 {
+					return errors.New("delete db instance should not be called for protected resources")
+				}
+				return nil
+			},
+		},
+		{
+			name: "db instances which are members of a db cluster are left to the cluster manager",
+			fields: fields{
+				rdsClient: func() *rdsClientMock {
+					fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
+						c.DescribeDBInstancesWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBInstancesInput, opts ...request.Option) (output *rds.DescribeDBInstancesOutput, e error) {
+							fakeDBInstance := fakeRDSClientDBInstance()
+							fakeDBInstance.DBClusterIdentifier = aws.String(fakeRDSClientDBClusterIdentifier)
+							return &rds.DescribeDBInstancesOutput{
+								DBInstances: []*rds.DBInstance{
+									fakeDBInstance,
+								},
+							}, nil
+						}
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return fakeClient
+				},
+				logger: fakeLogger,
+			},
+			args: args{
+				clusterId: fakeRDSClientTagVal,
+				tags:      map[string]string{},
+				dryRun:    false,
+			},
+			want: []*clusterservice.ReportItem{},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.DeleteDBInstanceWithContextCalls()) != 0 {
+					return errors.New("delete db instance should not be called for members of db clusters")
+				}
+				return nil
+			},
+		},
+		{
+			name: "db instances which are not targeted are not deleted",
+			fields: fields{
+				rdsClient: func() *rdsClientMock {
+					fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return fakeClient
+				},
+				logger:  fakeLogger,
+				options: ManagerOptions{Targets: map[string]bool{"arn:aws:rds:eu-west-1:123456789012:db:other": true}},
+			},
+			args: args{
+				clusterId: fakeRDSClientTagVal,
+				tags:      map[string]string{},
+				dryRun:    false,
+			},
+			want: []*clusterservice.ReportItem{},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.DeleteDBInstanceWithContextCalls()) != 0 {
+					return errors.New("delete db instance should not be called for resources which are not targeted")
+				}
+				return nil
+			},
+		},
+		{
+			name: "final snapshot of db instance is created before the db instance is deleted",
+			fields: fields{
+				rdsClient: func() *rdsClientMock {
+					fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
+						c.DescribeDBSnapshotsWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBSnapshotsInput, opts ...request.Option) (*rds.DescribeDBSnapshotsOutput, error) {
+							return nil, awserr.New(rds.ErrCodeDBSnapshotNotFoundFault, "", nil)
+						}
+						c.CreateDBSnapshotWithContextFunc = func(ctx context.Context, in1 *rds.CreateDBSnapshotInput, opts ...request.Option) (*rds.CreateDBSnapshotOutput, error) {
+							return &rds.CreateDBSnapshotOutput{}, nil
+						}
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return fakeClient
+				},
+				logger:  fakeLogger,
+				options: ManagerOptions{FinalSnapshot: NewDefaultFinalSnapshotPolicy()},
+			},
+			args: args{
+				clusterId: fakeRDSClientTagVal,
+				tags:      map[string]string{},
+				dryRun:    false,
+			},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeRDSClientInstanceARN
+					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeRDSInstance
+					item.Manager = string(managerRDS)
+					item.Tags = fakeRDSClientTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.StatusReason = "creating final snapshot testIdentifier-final"
+					item.FinalSnapshot = "testIdentifier-final"
+					item.NextCheckAfter = rdsInstanceDeletionCheckInterval
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.CreateDBSnapshotWithContextCalls()) != 1 {
+					return errors.New("create db snapshot call count should be 1")
+				}
+				callInput := mock.CreateDBSnapshotWithContextCalls()[0].CreateDBSnapshotInput
+				if aws.StringValue(callInput.DBSnapshotIdentifier) != "testIdentifier-final" {
+					return fmt.Errorf("unexpected final snapshot identifier %s", aws.StringValue(callInput.DBSnapshotIdentifier))
+				}
+				tagKeys := map[string]bool{}
+				for _, tag := range callInput.Tags {
+					tagKeys[aws.StringValue(tag.Key)] = true
+				}
+				if !tagKeys[TagKeyRetainUntil] || !tagKeys[fakeRDSClientTagKey] {
+					return errors.New("final snapshot must be tagged with the retain until tag and the tags of the db instance")
+				}
+				if len(mock.DeleteDBInstanceWithContextCalls()) != 0 {
+					return errors.New("delete db instance should not be called before the final snapshot is available")
+				}
+				return nil
+			},
+		},
+		{
+			name: "db instance is not deleted while its final snapshot is being created",
+			fields: fields{
+				rdsClient: func() *rdsClientMock {
+					fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
+						c.DescribeDBSnapshotsWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBSnapshotsInput, opts ...request.Option) (*rds.DescribeDBSnapshotsOutput, error) {
+							fakeSnapshot := fakeRDSSnapshot()
+							fakeSnapshot.Status = aws.String("creating")
+							return &rds.DescribeDBSnapshotsOutput{DBSnapshots: []*rds.DBSnapshot{fakeSnapshot}}, nil
+						}
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return fakeClient
+				},
+				logger:  fakeLogger,
+				options: ManagerOptions{FinalSnapshot: NewDefaultFinalSnapshotPolicy()},
+			},
+			args: args{
+				clusterId: fakeRDSClientTagVal,
+				tags:      map[string]string{},
+				dryRun:    false,
+			},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeRDSClientInstanceARN
+					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeRDSInstance
+					item.Manager = string(managerRDS)
+					item.Tags = fakeRDSClientTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.StatusReason = "waiting for final snapshot testIdentifier-final in state creating"
+					item.FinalSnapshot = "testIdentifier-final"
+					item.NextCheckAfter = rdsInstanceDeletionCheckInterval
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.DeleteDBInstanceWithContextCalls()) != 0 {
+					return errors.New("delete db instance should not be called before the final snapshot is available")
+				}
+				return nil
+			},
+		},
+		{
+			name: "db instance is deleted once its final snapshot is available",
+			fields: fields{
+				rdsClient: func() *rdsClientMock {
+					fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return fakeClient
+				},
+				logger:  fakeLogger,
+				options: ManagerOptions{FinalSnapshot: NewDefaultFinalSnapshotPolicy()},
+			},
+			args: args{
+				clusterId: fakeRDSClientTagVal,
+				tags:      map[string]string{},
+				dryRun:    false,
+			},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeRDSClientInstanceARN
+					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeRDSInstance
+					item.Manager = string(managerRDS)
+					item.Tags = fakeRDSClientTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusInProgress
+					item.FinalSnapshot = "testIdentifier-final"
+					item.NextCheckAfter = rdsInstanceDeletionCheckInterval
+				}),
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.CreateDBSnapshotWithContextCalls()) != 0 {
+					return errors.New("create db snapshot should not be called when the final snapshot exists")
+				}
+				if len(mock.DeleteDBInstanceWithContextCalls()) != 1 {
+					return errors.New("delete db instance call count should be 1")
+				}
+				return nil
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			fakeClient := tt.fields.rdsClient()
 			r := &RDSInstanceManager{
-				rdsClient: fakeClient,
-				logger:    tt.fields.logger,
+				configurable: configurable{options: tt.fields.options},
+				rdsClient:    fakeClient,
+				logger:       tt.fields.logger,
 			}
-			got, err := r.DeleteResourcesForCluster(tt.args.clusterId, tt.args.tags, tt.args.dryRun)
+			got, err := r.DeleteResourcesForCluster(context.TODO(), tt.args.clusterId, tt.args.tags, tt.args.dryRun)
 			if tt.wantErr != "" && err.Error() != tt.wantErr {
 				t.Errorf("DeleteResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -428,3 +763,121 @@ func equalReportItems(a, b []*clusterservice.ReportItem) bool {
 	}
 	return true
 }
+
+func TestRDSEngine_ListResourcesForCluster(t *testing.T) {
+	fakeLogger, err := fakeLogger(func(l *logrus.Entry) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeCreationTime := time.Date(2021, 3, 4, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		rdsClient func() *rdsClientMock
+		clusterId string
+		want      []*clusterservice.Resource
+		wantFn    func(mock *rdsClientMock) error
+		wantErr   string
+	}{
+		{
+			name: "error when describing db instances fails",
+			rdsClient: func() *rdsClientMock {
+				fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
+					c.DescribeDBInstancesWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBInstancesInput, opts ...request.Option) (*rds.DescribeDBInstancesOutput, error) {
+						return nil, errors.New("")
+					}
+					return nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				return fakeClient
+			},
+			clusterId: fakeRDSClientTagVal,
+			wantErr:   "failed to describe database clusters: ",
+		},
+		{
+			name: "empty when no db instances match cluster id tag",
+			rdsClient: func() *rdsClientMock {
+				fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
+					return nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				return fakeClient
+			},
+			clusterId: fmt.Sprintf("%s-modified", fakeRDSClientTagVal),
+			want:      []*clusterservice.Resource{},
+		},
+		{
+			name: "db instances are listed with state, creation time and size without being modified",
+			rdsClient: func() *rdsClientMock {
+				fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
+					c.DescribeDBInstancesWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBInstancesInput, opts ...request.Option) (*rds.DescribeDBInstancesOutput, error) {
+						dbInstance := fakeRDSClientDBInstance()
+						dbInstance.DBInstanceStatus = aws.String("available")
+						dbInstance.InstanceCreateTime = aws.Time(fakeCreationTime)
+						dbInstance.AllocatedStorage = aws.Int64(20)
+						return &rds.DescribeDBInstancesOutput{
+							DBInstances: []*rds.DBInstance{dbInstance},
+						}, nil
+					}
+					return nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				return fakeClient
+			},
+			clusterId: fakeRDSClientTagVal,
+			want: []*clusterservice.Resource{
+				{
+					ID:           fakeRDSClientInstanceARN,
+					Name:         fakeResourceIdentifier,
+					ResourceType: resourceTypeRDSInstance,
+					Manager:      string(managerRDS),
+					Tags:         fakeRDSClientTags(),
+					State:        "available",
+					CreationTime: aws.Time(fakeCreationTime),
+					SizeBytes:    aws.Int64(20 * bytesPerGiB),
+				},
+			},
+			wantFn: func(mock *rdsClientMock) error {
+				if len(mock.ModifyDBInstanceWithContextCalls()) != 0 || len(mock.DeleteDBInstanceWithContextCalls()) != 0 {
+					return errors.New("db instances should not be modified or deleted when listing")
+				}
+				return nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := tt.rdsClient()
+			r := &RDSInstanceManager{
+				rdsClient: fakeClient,
+				logger:    fakeLogger,
+			}
+			got, err := r.ListResourcesForCluster(context.TODO(), tt.clusterId, map[string]string{})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ListResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListResourcesForCluster() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListResourcesForCluster() got = %v, want %v", got, tt.want)
+			}
+			if tt.wantFn != nil {
+				if err := tt.wantFn(fakeClient); err != nil {
+					t.Errorf("ListResourcesForCluster() err = %v", err)
+				}
+			}
+		})
+	}
+}
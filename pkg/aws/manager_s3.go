@@ -1,7 +1,9 @@
 package aws
 
 import (
+	"context"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -19,19 +21,24 @@ const (
 	resourceTypeS3 = "s3"
 )
 
-var _ ClusterResourceManager = &S3Manager{}
+var _ PhasedClusterResourceManager = &S3Manager{}
+var _ ConfigurableClusterResourceManager = &S3Manager{}
 
 type S3Manager struct {
+	configurable
 	s3Client            s3Client
 	s3BatchDeleteClient s3BatchDeleteClient
 	taggingClient       taggingClient
 	logger              *logrus.Entry
+	region              string
 }
 
 //s3Bucket internal representation of an s3 bucket containing only information required for reporting
 type s3Bucket struct {
-	ID  string
-	ARN string
+	ID           string
+	ARN          string
+	Tags         map[string]string
+	CreationDate *time.Time
 }
 
 func NewDefaultS3Engine(session *session.Session, logger *logrus.Entry) *S3Manager {
@@ -41,6 +48,7 @@ func NewDefaultS3Engine(session *session.Session, logger *logrus.Entry) *S3Manag
 		s3BatchDeleteClient: s3manager.NewBatchDeleteWithClient(s3Client),
 		taggingClient:       resourcegroupstaggingapi.New(session),
 		logger:              logger.WithField(loggingKeyManager, managerS3),
+		region:              regionFromSession(session),
 	}
 }
 
@@ -48,68 +56,140 @@ func (r *S3Manager) GetName() string {
 	return "AWS S3 Manager"
 }
 
-func (s *S3Manager) DeleteResourcesForCluster(clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
+func (r *S3Manager) GetType() ResourceManagerType {
+	return managerS3
+}
+
+func (r *S3Manager) GetDependencies() []ResourceManagerType {
+	return nil
+}
+
+func (s *S3Manager) DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
 	s.logger.Debug("delete s3 resources for cluster")
-	//filter s3 buckets with correct tags
-	s.logger.Debug("listing s3 buckets using provided tag filters")
-	getResourcesInput := &resourcegroupstaggingapi.GetResourcesInput{
-		ResourceTypeFilters: aws.StringSlice([]string{resourceTypeS3}),
-		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
-	}
-	getResourcesOutput, err := s.taggingClient.GetResources(getResourcesInput)
+	bucketsToDelete, err := s.getBucketsForCluster(ctx, clusterId, tags)
 	if err != nil {
-		return nil, errors.WrapLog(err, "failed to filter s3 buckets in aws", s.logger)
-	}
-	//build list of s3 buckets to delete
-	var bucketsToDelete []*s3Bucket
-	for _, resourceTagMapping := range getResourcesOutput.ResourceTagMappingList {
-		bucketARN := aws.StringValue(resourceTagMapping.ResourceARN)
-		//get bucket id from arn, should be the last element
-		//strings#Split will always return at least one element https://golang.org/pkg/strings/#Split
-		bucketARNElements := strings.Split(bucketARN, ":")
-		bucketID := bucketARNElements[len(bucketARNElements)-1]
-		bucketsToDelete = append(bucketsToDelete, &s3Bucket{
-			ID:  bucketID,
-			ARN: bucketARN,
-		})
+		return nil, err
 	}
-	s.logger.Debugf("found list of %d s3 buckets to delete", len(bucketsToDelete))
 	//delete s3 buckets and build report
 	var reportItems []*clusterservice.ReportItem
+	deletePool := newWorkerPool(s.options.Concurrency)
 	for _, bucket := range bucketsToDelete {
+		bucket := bucket
 		bucketLogger := s.logger.WithField(loggingKeyBucket, bucket.ID)
+		if !s.isTarget(bucket.ARN) {
+			bucketLogger.Debug("resource is not targeted, skipping")
+			continue
+		}
 		bucketLogger.Debug("handling deletion for bucket")
 		//add new item to report list for bucket
 		reportItem := &clusterservice.ReportItem{
 			ID:           bucket.ARN,
 			Name:         bucket.ID,
+			ResourceType: resourceTypeS3,
+			Region:       s.region,
+			Account:      accountFromARN(bucket.ARN),
+			Manager:      string(managerS3),
+			Tags:         bucket.Tags,
 			Action:       clusterservice.ActionDelete,
 			ActionStatus: clusterservice.ActionStatusInProgress,
 		}
 		reportItems = append(reportItems, reportItem)
+		if s.isProtected(reportItem, bucket.CreationDate) {
+			bucketLogger.Debug("resource is protected by the safety policy, skipping")
+			continue
+		}
 		//don't delete in dry run scenario
 		if dryRun {
 			bucketLogger.Debug("dry run is enabled, skipping deletion")
 			reportItem.ActionStatus = clusterservice.ActionStatusDryRun
 			continue
 		}
-		//empty the bucket before performing the release
-		bucketLogger.Debug("emptying all content from bucket before deletion")
-		deleteIterator := s3manager.NewDeleteListIterator(s.s3Client, &s3.ListObjectsInput{
-			Bucket: aws.String(bucket.ID),
+		deletePool.Go(func() error {
+			//empty the bucket before performing the release
+			bucketLogger.Debug("emptying all content from bucket before deletion")
+			deleteIterator := s3manager.NewDeleteListIterator(s.s3Client, &s3.ListObjectsInput{
+				Bucket: aws.String(bucket.ID),
+			})
+			if err := s.s3BatchDeleteClient.Delete(ctx, deleteIterator); err != nil {
+				return failReportItem(reportItem, errors.WrapLog(err, "failed to empty bucket contents", bucketLogger))
+			}
+			//once the bucket is empty it can be deleted
+			bucketLogger.Debug("performing bucket deletion")
+			deleteBucketInput := &s3.DeleteBucketInput{
+				Bucket: aws.String(bucket.ID),
+			}
+			if _, err := s.s3Client.DeleteBucketWithContext(ctx, deleteBucketInput); err != nil {
+				return failReportItem(reportItem, errors.WrapLog(err, "failed to delete bucket", bucketLogger))
+			}
+			return nil
 		})
-		if err := s.s3BatchDeleteClient.Delete(aws.BackgroundContext(), deleteIterator); err != nil {
-			return nil, errors.WrapLog(err, "failed to empty bucket contents", bucketLogger)
-		}
-		//once the bucket is empty it can be deleted
-		bucketLogger.Debug("performing bucket deletion")
-		deleteBucketInput := &s3.DeleteBucketInput{
-			Bucket: aws.String(bucket.ID),
-		}
-		if _, err := s.s3Client.DeleteBucket(deleteBucketInput); err != nil {
-			return nil, errors.WrapLog(err, "failed to delete bucket", bucketLogger)
-		}
 	}
 	//return final report
-	return reportItems, nil
+	return reportItems, errors.Aggregate(deletePool.Wait())
+}
+
+//ListResourcesForCluster List s3 buckets for a specified cluster without modifying them
+func (s *S3Manager) ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+	s.logger.Debug("list s3 resources for cluster")
+	buckets, err := s.getBucketsForCluster(ctx, clusterId, tags)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*clusterservice.Resource, 0, len(buckets))
+	for _, bucket := range buckets {
+		resources = append(resources, &clusterservice.Resource{
+			ID:           bucket.ARN,
+			Name:         bucket.ID,
+			ResourceType: resourceTypeS3,
+			Region:       s.region,
+			Account:      accountFromARN(bucket.ARN),
+			Manager:      string(managerS3),
+			Tags:         bucket.Tags,
+			CreationTime: bucket.CreationDate,
+		})
+	}
+	return resources, nil
+}
+
+//getBucketsForCluster Get the s3 buckets tagged for a cluster using the resource tagging api, along with their creation dates
+func (s *S3Manager) getBucketsForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*s3Bucket, error) {
+	//filter s3 buckets with correct tags
+	s.logger.Debug("listing s3 buckets using provided tag filters")
+	getResourcesInput := &resourcegroupstaggingapi.GetResourcesInput{
+		ResourceTypeFilters: aws.StringSlice([]string{resourceTypeS3}),
+		TagFilters:          convertClusterTagsToAWSTagFilter(clusterId, tags),
+	}
+	resourceTagMappings, err := getTaggedResources(ctx, s.taggingClient, getResourcesInput)
+	if err != nil {
+		return nil, errors.WrapLog(err, "failed to filter s3 buckets in aws", s.logger)
+	}
+	if len(resourceTagMappings) == 0 {
+		return nil, nil
+	}
+	//the tagging api doesn't provide creation dates, these are only available when listing every bucket
+	listBucketsOutput, err := s.s3Client.ListBucketsWithContext(ctx, &s3.ListBucketsInput{})
+	if err != nil {
+		return nil, errors.WrapLog(err, "failed to list s3 buckets", s.logger)
+	}
+	bucketCreationDates := map[string]*time.Time{}
+	for _, listedBucket := range listBucketsOutput.Buckets {
+		bucketCreationDates[aws.StringValue(listedBucket.Name)] = listedBucket.CreationDate
+	}
+	//build list of s3 buckets
+	var buckets []*s3Bucket
+	for _, resourceTagMapping := range resourceTagMappings {
+		bucketARN := aws.StringValue(resourceTagMapping.ResourceARN)
+		//get bucket id from arn, should be the last element
+		//strings#Split will always return at least one element https://golang.org/pkg/strings/#Split
+		bucketARNElements := strings.Split(bucketARN, ":")
+		bucketID := bucketARNElements[len(bucketARNElements)-1]
+		buckets = append(buckets, &s3Bucket{
+			ID:           bucketID,
+			ARN:          bucketARN,
+			Tags:         convertAWSTagsToMap(resourceTagMapping.Tags),
+			CreationDate: bucketCreationDates[bucketID],
+		})
+	}
+	s.logger.Debugf("found list of %d s3 buckets for cluster", len(buckets))
+	return buckets, nil
 }
@@ -5,7 +5,10 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
 
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
@@ -23,6 +26,23 @@ func TestS3Engine_DeleteResourcesForCluster(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	fakeNow := time.Date(2021, 3, 5, 12, 0, 0, 0, time.UTC)
+	fakeS3ClientWithCreationDate := func(creationDate time.Time) func() s3Client {
+		return func() s3Client {
+			client, err := fakeS3Client(func(c *s3ClientMock) error {
+				c.ListBucketsWithContextFunc = func(ctx context.Context, in1 *s3.ListBucketsInput, opts ...request.Option) (*s3.ListBucketsOutput, error) {
+					return &s3.ListBucketsOutput{
+						Buckets: []*s3.Bucket{{Name: aws.String(fakeResourceIdentifier), CreationDate: aws.Time(creationDate)}},
+					}, nil
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return client
+		}
+	}
 
 	type fields struct {
 		s3Client            func() s3Client
@@ -39,6 +59,7 @@ func TestS3Engine_DeleteResourcesForCluster(t *testing.T) {
 		name    string
 		fields  fields
 		args    args
+		options ManagerOptions
 		want    []*clusterservice.ReportItem
 		wantErr string
 	}{
@@ -53,7 +74,7 @@ func TestS3Engine_DeleteResourcesForCluster(t *testing.T) {
 				},
 				taggingClient: func() taggingClient {
 					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
-						c.GetResourcesFunc = func(in1 *resourcegroupstaggingapi.GetResourcesInput) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
+						c.GetResourcesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
 							return nil, errors.New("")
 						}
 						return nil
@@ -112,6 +133,18 @@ func TestS3Engine_DeleteResourcesForCluster(t *testing.T) {
 				tags:      map[string]string{},
 				dryRun:    false,
 			},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeResourceTagMappingARN
+					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeS3
+					item.Manager = string(managerS3)
+					item.Tags = fakeResourceTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusFailed
+					item.StatusReason = "failed to empty bucket contents: "
+				}),
+			},
 			wantErr: "failed to empty bucket contents: ",
 		},
 		{
@@ -119,7 +152,7 @@ func TestS3Engine_DeleteResourcesForCluster(t *testing.T) {
 			fields: fields{
 				s3Client: func() s3Client {
 					client, err := fakeS3Client(func(c *s3ClientMock) error {
-						c.DeleteBucketFunc = func(in1 *s3.DeleteBucketInput) (output *s3.DeleteBucketOutput, e error) {
+						c.DeleteBucketWithContextFunc = func(ctx context.Context, in1 *s3.DeleteBucketInput, opts ...request.Option) (output *s3.DeleteBucketOutput, e error) {
 							return nil, errors.New("")
 						}
 						return nil
@@ -149,6 +182,18 @@ func TestS3Engine_DeleteResourcesForCluster(t *testing.T) {
 				},
 				logger: fakeLogger,
 			},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeResourceTagMappingARN
+					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeS3
+					item.Manager = string(managerS3)
+					item.Tags = fakeResourceTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusFailed
+					item.StatusReason = "failed to delete bucket: "
+				}),
+			},
 			wantErr: "failed to delete bucket: ",
 		},
 		{
@@ -192,6 +237,9 @@ func TestS3Engine_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeResourceTagMappingARN
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeS3
+					item.Manager = string(managerS3)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusInProgress
 				}),
@@ -238,21 +286,108 @@ func TestS3Engine_DeleteResourcesForCluster(t *testing.T) {
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeResourceTagMappingARN
 					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeS3
+					item.Manager = string(managerS3)
+					item.Tags = fakeResourceTags()
 					item.Action = clusterservice.ActionDelete
 					item.ActionStatus = clusterservice.ActionStatusDryRun
 				}),
 			},
 		},
+		{
+			name: "buckets older than the minimum age are deleted",
+			fields: fields{
+				s3Client: fakeS3ClientWithCreationDate(fakeNow.Add(-7 * 24 * time.Hour)),
+				s3BatchDeleteClient: func() s3BatchDeleteClient {
+					client, err := fakeS3BatchClient(func(c *s3BatchDeleteClientMock) error {
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return client
+				},
+				taggingClient: func() taggingClient {
+					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return client
+				},
+				logger: fakeLogger,
+			},
+			args: args{
+				clusterId: fakeClusterId,
+				tags:      map[string]string{},
+			},
+			options: ManagerOptions{Policy: &SafetyPolicy{MinimumAge: 24 * time.Hour, now: func() time.Time { return fakeNow }}},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeResourceTagMappingARN
+					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeS3
+					item.Manager = string(managerS3)
+					item.Tags = fakeResourceTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusInProgress
+				}),
+			},
+		},
+		{
+			name: "buckets younger than the minimum age are protected",
+			fields: fields{
+				s3Client: fakeS3ClientWithCreationDate(fakeNow.Add(-time.Hour)),
+				s3BatchDeleteClient: func() s3BatchDeleteClient {
+					client, err := fakeS3BatchClient(func(c *s3BatchDeleteClientMock) error {
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return client
+				},
+				taggingClient: func() taggingClient {
+					client, err := fakeTaggingClient(func(c *taggingClientMock) error {
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return client
+				},
+				logger: fakeLogger,
+			},
+			args: args{
+				clusterId: fakeClusterId,
+				tags:      map[string]string{},
+			},
+			options: ManagerOptions{Policy: &SafetyPolicy{MinimumAge: 24 * time.Hour, now: func() time.Time { return fakeNow }}},
+			want: []*clusterservice.ReportItem{
+				mockReportItem(func(item *clusterservice.ReportItem) {
+					item.ID = fakeResourceTagMappingARN
+					item.Name = fakeResourceIdentifier
+					item.ResourceType = resourceTypeS3
+					item.Manager = string(managerS3)
+					item.Tags = fakeResourceTags()
+					item.Action = clusterservice.ActionDelete
+					item.ActionStatus = clusterservice.ActionStatusProtected
+					item.StatusReason = "created 1h0m0s ago, less than the minimum age of 24h0m0s"
+				}),
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := &S3Manager{
+				configurable:        configurable{options: tt.options},
 				s3Client:            tt.fields.s3Client(),
 				s3BatchDeleteClient: tt.fields.s3BatchDeleteClient(),
 				taggingClient:       tt.fields.taggingClient(),
 				logger:              tt.fields.logger,
 			}
-			got, err := s.DeleteResourcesForCluster(tt.args.clusterId, tt.args.tags, tt.args.dryRun)
+			got, err := s.DeleteResourcesForCluster(context.TODO(), tt.args.clusterId, tt.args.tags, tt.args.dryRun)
 			if tt.wantErr != "" && err.Error() != tt.wantErr {
 				t.Errorf("DeleteResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -263,3 +398,98 @@ func TestS3Engine_DeleteResourcesForCluster(t *testing.T) {
 		})
 	}
 }
+
+func TestS3Engine_ListResourcesForCluster(t *testing.T) {
+	fakeLogger, err := fakeLogger(func(l *logrus.Entry) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeCreationDate := time.Date(2021, 3, 4, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		s3Client func() s3Client
+		want     []*clusterservice.Resource
+		wantErr  string
+	}{
+		{
+			name: "fail when listing buckets returns an error",
+			s3Client: func() s3Client {
+				client, err := fakeS3Client(func(c *s3ClientMock) error {
+					c.ListBucketsWithContextFunc = func(ctx context.Context, in1 *s3.ListBucketsInput, opts ...request.Option) (*s3.ListBucketsOutput, error) {
+						return nil, errors.New("")
+					}
+					return nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				return client
+			},
+			wantErr: "failed to list s3 buckets: ",
+		},
+		{
+			name: "buckets are listed with their creation date without being deleted",
+			s3Client: func() s3Client {
+				client, err := fakeS3Client(func(c *s3ClientMock) error {
+					c.ListBucketsWithContextFunc = func(ctx context.Context, in1 *s3.ListBucketsInput, opts ...request.Option) (*s3.ListBucketsOutput, error) {
+						return &s3.ListBucketsOutput{
+							Buckets: []*s3.Bucket{
+								{Name: aws.String("unrelated"), CreationDate: aws.Time(time.Now())},
+								{Name: aws.String(fakeResourceIdentifier), CreationDate: aws.Time(fakeCreationDate)},
+							},
+						}, nil
+					}
+					c.DeleteBucketWithContextFunc = func(ctx context.Context, in1 *s3.DeleteBucketInput, opts ...request.Option) (*s3.DeleteBucketOutput, error) {
+						return nil, errors.New("delete bucket should not be called when listing")
+					}
+					return nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				return client
+			},
+			want: []*clusterservice.Resource{
+				{
+					ID:           fakeResourceTagMappingARN,
+					Name:         fakeResourceIdentifier,
+					ResourceType: resourceTypeS3,
+					Manager:      string(managerS3),
+					Tags:         fakeResourceTags(),
+					CreationTime: aws.Time(fakeCreationDate),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taggingClient, err := fakeTaggingClient(func(c *taggingClientMock) error {
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := &S3Manager{
+				s3Client:      tt.s3Client(),
+				taggingClient: taggingClient,
+				logger:        fakeLogger,
+			}
+			got, err := s.ListResourcesForCluster(context.TODO(), fakeClusterId, map[string]string{})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ListResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListResourcesForCluster() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListResourcesForCluster() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
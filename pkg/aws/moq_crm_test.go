@@ -4,6 +4,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/integr8ly/cluster-service/pkg/clusterservice"
 	"sync"
 )
@@ -18,12 +19,15 @@ var _ ClusterResourceManager = &ClusterResourceManagerMock{}
 //
 // 		// make and configure a mocked ClusterResourceManager
 // 		mockedClusterResourceManager := &ClusterResourceManagerMock{
-// 			DeleteResourcesForClusterFunc: func(clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
+// 			DeleteResourcesForClusterFunc: func(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
 // 				panic("mock out the DeleteResourcesForCluster method")
 // 			},
 // 			GetNameFunc: func() string {
 // 				panic("mock out the GetName method")
 // 			},
+// 			ListResourcesForClusterFunc: func(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+// 				panic("mock out the ListResourcesForCluster method")
+// 			},
 // 		}
 //
 // 		// use mockedClusterResourceManager in code that requires ClusterResourceManager
@@ -32,15 +36,20 @@ var _ ClusterResourceManager = &ClusterResourceManagerMock{}
 // 	}
 type ClusterResourceManagerMock struct {
 	// DeleteResourcesForClusterFunc mocks the DeleteResourcesForCluster method.
-	DeleteResourcesForClusterFunc func(clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error)
+	DeleteResourcesForClusterFunc func(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error)
 
 	// GetNameFunc mocks the GetName method.
 	GetNameFunc func() string
 
+	// ListResourcesForClusterFunc mocks the ListResourcesForCluster method.
+	ListResourcesForClusterFunc func(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// DeleteResourcesForCluster holds details about calls to the DeleteResourcesForCluster method.
 		DeleteResourcesForCluster []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// ClusterId is the clusterId argument value.
 			ClusterId string
 			// Tags is the tags argument value.
@@ -51,21 +60,33 @@ type ClusterResourceManagerMock struct {
 		// GetName holds details about calls to the GetName method.
 		GetName []struct {
 		}
+		// ListResourcesForCluster holds details about calls to the ListResourcesForCluster method.
+		ListResourcesForCluster []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ClusterId is the clusterId argument value.
+			ClusterId string
+			// Tags is the tags argument value.
+			Tags map[string]string
+		}
 	}
 	lockDeleteResourcesForCluster sync.RWMutex
 	lockGetName                   sync.RWMutex
+	lockListResourcesForCluster   sync.RWMutex
 }
 
 // DeleteResourcesForCluster calls DeleteResourcesForClusterFunc.
-func (mock *ClusterResourceManagerMock) DeleteResourcesForCluster(clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
+func (mock *ClusterResourceManagerMock) DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error) {
 	if mock.DeleteResourcesForClusterFunc == nil {
 		panic("ClusterResourceManagerMock.DeleteResourcesForClusterFunc: method is nil but ClusterResourceManager.DeleteResourcesForCluster was just called")
 	}
 	callInfo := struct {
+		Ctx       context.Context
 		ClusterId string
 		Tags      map[string]string
 		DryRun    bool
 	}{
+		Ctx:       ctx,
 		ClusterId: clusterId,
 		Tags:      tags,
 		DryRun:    dryRun,
@@ -73,18 +94,20 @@ func (mock *ClusterResourceManagerMock) DeleteResourcesForCluster(clusterId stri
 	mock.lockDeleteResourcesForCluster.Lock()
 	mock.calls.DeleteResourcesForCluster = append(mock.calls.DeleteResourcesForCluster, callInfo)
 	mock.lockDeleteResourcesForCluster.Unlock()
-	return mock.DeleteResourcesForClusterFunc(clusterId, tags, dryRun)
+	return mock.DeleteResourcesForClusterFunc(ctx, clusterId, tags, dryRun)
 }
 
 // DeleteResourcesForClusterCalls gets all the calls that were made to DeleteResourcesForCluster.
 // Check the length with:
 //     len(mockedClusterResourceManager.DeleteResourcesForClusterCalls())
 func (mock *ClusterResourceManagerMock) DeleteResourcesForClusterCalls() []struct {
+	Ctx       context.Context
 	ClusterId string
 	Tags      map[string]string
 	DryRun    bool
 } {
 	var calls []struct {
+		Ctx       context.Context
 		ClusterId string
 		Tags      map[string]string
 		DryRun    bool
@@ -120,3 +143,42 @@ func (mock *ClusterResourceManagerMock) GetNameCalls() []struct {
 	mock.lockGetName.RUnlock()
 	return calls
 }
+
+// ListResourcesForCluster calls ListResourcesForClusterFunc.
+func (mock *ClusterResourceManagerMock) ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+	if mock.ListResourcesForClusterFunc == nil {
+		panic("ClusterResourceManagerMock.ListResourcesForClusterFunc: method is nil but ClusterResourceManager.ListResourcesForCluster was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		ClusterId string
+		Tags      map[string]string
+	}{
+		Ctx:       ctx,
+		ClusterId: clusterId,
+		Tags:      tags,
+	}
+	mock.lockListResourcesForCluster.Lock()
+	mock.calls.ListResourcesForCluster = append(mock.calls.ListResourcesForCluster, callInfo)
+	mock.lockListResourcesForCluster.Unlock()
+	return mock.ListResourcesForClusterFunc(ctx, clusterId, tags)
+}
+
+// ListResourcesForClusterCalls gets all the calls that were made to ListResourcesForCluster.
+// Check the length with:
+//     len(mockedClusterResourceManager.ListResourcesForClusterCalls())
+func (mock *ClusterResourceManagerMock) ListResourcesForClusterCalls() []struct {
+	Ctx       context.Context
+	ClusterId string
+	Tags      map[string]string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		ClusterId string
+		Tags      map[string]string
+	}
+	mock.lockListResourcesForCluster.RLock()
+	calls = mock.calls.ListResourcesForCluster
+	mock.lockListResourcesForCluster.RUnlock()
+	return calls
+}
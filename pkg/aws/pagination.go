@@ -0,0 +1,129 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/elasticache/elasticacheiface"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi/resourcegroupstaggingapiiface"
+)
+
+//getTaggedResources Get every resource matching the input from the resource tagging api, reading every page
+func getTaggedResources(ctx context.Context, client resourcegroupstaggingapiiface.ResourceGroupsTaggingAPIAPI, input *resourcegroupstaggingapi.GetResourcesInput) ([]*resourcegroupstaggingapi.ResourceTagMapping, error) {
+	var resourceTagMappings []*resourcegroupstaggingapi.ResourceTagMapping
+	err := client.GetResourcesPagesWithContext(ctx, input, func(page *resourcegroupstaggingapi.GetResourcesOutput, lastPage bool) bool {
+		resourceTagMappings = append(resourceTagMappings, page.ResourceTagMappingList...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resourceTagMappings, nil
+}
+
+//describeDBInstances Get every database instance matching the input, reading every page
+func describeDBInstances(ctx context.Context, client rdsiface.RDSAPI, input *rds.DescribeDBInstancesInput) ([]*rds.DBInstance, error) {
+	var dbInstances []*rds.DBInstance
+	err := client.DescribeDBInstancesPagesWithContext(ctx, input, func(page *rds.DescribeDBInstancesOutput, lastPage bool) bool {
+		dbInstances = append(dbInstances, page.DBInstances...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dbInstances, nil
+}
+
+//describeCacheClusters Get every cache cluster matching the input, reading every page
+func describeCacheClusters(ctx context.Context, client elasticacheiface.ElastiCacheAPI, input *elasticache.DescribeCacheClustersInput) ([]*elasticache.CacheCluster, error) {
+	var cacheClusters []*elasticache.CacheCluster
+	err := client.DescribeCacheClustersPagesWithContext(ctx, input, func(page *elasticache.DescribeCacheClustersOutput, lastPage bool) bool {
+		cacheClusters = append(cacheClusters, page.CacheClusters...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cacheClusters, nil
+}
+
+//describeReplicationGroups Get every replication group matching the input, reading every page
+func describeReplicationGroups(ctx context.Context, client elasticacheiface.ElastiCacheAPI, input *elasticache.DescribeReplicationGroupsInput) ([]*elasticache.ReplicationGroup, error) {
+	var replicationGroups []*elasticache.ReplicationGroup
+	err := client.DescribeReplicationGroupsPagesWithContext(ctx, input, func(page *elasticache.DescribeReplicationGroupsOutput, lastPage bool) bool {
+		replicationGroups = append(replicationGroups, page.ReplicationGroups...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return replicationGroups, nil
+}
+
+//describeCacheSnapshots Get every cache snapshot matching the input, reading every page
+func describeCacheSnapshots(ctx context.Context, client elasticacheiface.ElastiCacheAPI, input *elasticache.DescribeSnapshotsInput) ([]*elasticache.Snapshot, error) {
+	var snapshots []*elasticache.Snapshot
+	err := client.DescribeSnapshotsPagesWithContext(ctx, input, func(page *elasticache.DescribeSnapshotsOutput, lastPage bool) bool {
+		snapshots = append(snapshots, page.Snapshots...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+//describeDBSnapshots Get every database snapshot matching the input, reading every page
+func describeDBSnapshots(ctx context.Context, client rdsiface.RDSAPI, input *rds.DescribeDBSnapshotsInput) ([]*rds.DBSnapshot, error) {
+	var dbSnapshots []*rds.DBSnapshot
+	err := client.DescribeDBSnapshotsPagesWithContext(ctx, input, func(page *rds.DescribeDBSnapshotsOutput, lastPage bool) bool {
+		dbSnapshots = append(dbSnapshots, page.DBSnapshots...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dbSnapshots, nil
+}
+
+//describeDBClusters Get every database cluster matching the input, reading every page
+func describeDBClusters(ctx context.Context, client rdsiface.RDSAPI, input *rds.DescribeDBClustersInput) ([]*rds.DBCluster, error) {
+	var dbClusters []*rds.DBCluster
+	err := client.DescribeDBClustersPagesWithContext(ctx, input, func(page *rds.DescribeDBClustersOutput, lastPage bool) bool {
+		dbClusters = append(dbClusters, page.DBClusters...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dbClusters, nil
+}
+
+//describeDBClusterSnapshots Get every database cluster snapshot matching the input, reading every page
+func describeDBClusterSnapshots(ctx context.Context, client rdsiface.RDSAPI, input *rds.DescribeDBClusterSnapshotsInput) ([]*rds.DBClusterSnapshot, error) {
+	var dbClusterSnapshots []*rds.DBClusterSnapshot
+	err := client.DescribeDBClusterSnapshotsPagesWithContext(ctx, input, func(page *rds.DescribeDBClusterSnapshotsOutput, lastPage bool) bool {
+		dbClusterSnapshots = append(dbClusterSnapshots, page.DBClusterSnapshots...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dbClusterSnapshots, nil
+}
+
+//describeDBInstanceAutomatedBackups Get every database instance automated backup matching the input, reading every page
+func describeDBInstanceAutomatedBackups(ctx context.Context, client rdsiface.RDSAPI, input *rds.DescribeDBInstanceAutomatedBackupsInput) ([]*rds.DBInstanceAutomatedBackup, error) {
+	var automatedBackups []*rds.DBInstanceAutomatedBackup
+	err := client.DescribeDBInstanceAutomatedBackupsPagesWithContext(ctx, input, func(page *rds.DescribeDBInstanceAutomatedBackupsOutput, lastPage bool) bool {
+		automatedBackups = append(automatedBackups, page.DBInstanceAutomatedBackups...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return automatedBackups, nil
+}
@@ -0,0 +1,132 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+)
+
+func TestGetTaggedResources(t *testing.T) {
+	fakeSecondARN := "arn:aws:s3:::second"
+	tests := []struct {
+		name    string
+		pageErr error
+		want    []*resourcegroupstaggingapi.ResourceTagMapping
+		wantErr string
+	}{
+		{
+			name: "resources on every page are returned",
+			want: []*resourcegroupstaggingapi.ResourceTagMapping{
+				fakeResourceTagMapping(nil),
+				fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
+					mapping.ResourceARN = aws.String(fakeSecondARN)
+				}),
+			},
+		},
+		{
+			name:    "error is returned when a page fails",
+			pageErr: errors.New("page error"),
+			wantErr: "page error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient, err := fakeTaggingClient(func(c *taggingClientMock) error {
+				c.GetResourcesPagesWithContextFunc = func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, fn func(*resourcegroupstaggingapi.GetResourcesOutput, bool) bool, opts ...request.Option) error {
+					if !fn(&resourcegroupstaggingapi.GetResourcesOutput{ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{fakeResourceTagMapping(nil)}}, false) {
+						return nil
+					}
+					if tt.pageErr != nil {
+						return tt.pageErr
+					}
+					fn(&resourcegroupstaggingapi.GetResourcesOutput{ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {
+						mapping.ResourceARN = aws.String(fakeSecondARN)
+					})}}, true)
+					return nil
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := getTaggedResources(context.TODO(), fakeClient, &resourcegroupstaggingapi.GetResourcesInput{
+				ResourceTypeFilters: aws.StringSlice([]string{"s3"}),
+			})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr || got != nil {
+					t.Errorf("getTaggedResources() got = %v, error = %v, wantErr %v", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getTaggedResources() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getTaggedResources() got = %v, want %v", got, tt.want)
+			}
+			calls := fakeClient.GetResourcesPagesWithContextCalls()
+			if len(calls) != 1 || !reflect.DeepEqual(calls[0].GetResourcesInput.ResourceTypeFilters, aws.StringSlice([]string{"s3"})) {
+				t.Errorf("getTaggedResources() should read the pages of the provided input once, calls = %v", calls)
+			}
+		})
+	}
+}
+
+func TestDescribeDBInstances(t *testing.T) {
+	fakeClient, err := fakeRDSClient(func(c *rdsClientMock) error {
+		c.DescribeDBInstancesPagesWithContextFunc = func(ctx context.Context, in1 *rds.DescribeDBInstancesInput, fn func(*rds.DescribeDBInstancesOutput, bool) bool, opts ...request.Option) error {
+			if fn(&rds.DescribeDBInstancesOutput{DBInstances: []*rds.DBInstance{{DBInstanceIdentifier: aws.String("first")}}}, false) {
+				fn(&rds.DescribeDBInstancesOutput{DBInstances: []*rds.DBInstance{{DBInstanceIdentifier: aws.String("second")}}}, true)
+			}
+			return nil
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := describeDBInstances(context.TODO(), fakeClient, &rds.DescribeDBInstancesInput{})
+	if err != nil {
+		t.Fatalf("describeDBInstances() unexpected error = %v", err)
+	}
+	want := []*rds.DBInstance{
+		{DBInstanceIdentifier: aws.String("first")},
+		{DBInstanceIdentifier: aws.String("second")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("describeDBInstances() got = %v, want %v", got, want)
+	}
+}
+
+func TestDescribeCacheClusters(t *testing.T) {
+	fakeClient, err := fakeElasticacheClient(func(c *elasticacheClientMock) error {
+		c.DescribeCacheClustersPagesWithContextFunc = func(ctx context.Context, in1 *elasticache.DescribeCacheClustersInput, fn func(*elasticache.DescribeCacheClustersOutput, bool) bool, opts ...request.Option) error {
+			if fn(&elasticache.DescribeCacheClustersOutput{CacheClusters: []*elasticache.CacheCluster{{CacheClusterId: aws.String("first")}}}, false) {
+				fn(&elasticache.DescribeCacheClustersOutput{CacheClusters: []*elasticache.CacheCluster{{CacheClusterId: aws.String("second")}}}, true)
+			}
+			return nil
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := describeCacheClusters(context.TODO(), fakeClient, &elasticache.DescribeCacheClustersInput{})
+	if err != nil {
+		t.Fatalf("describeCacheClusters() unexpected error = %v", err)
+	}
+	want := []*elasticache.CacheCluster{
+		{CacheClusterId: aws.String("first")},
+		{CacheClusterId: aws.String("second")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("describeCacheClusters() got = %v, want %v", got, want)
+	}
+}
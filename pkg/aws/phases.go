@@ -0,0 +1,69 @@
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//buildPhases Order resource managers into phases so every manager runs in a later phase than the managers it depends on
+//Dependencies on managers that are not in the list are ignored, the order of the list is kept within each phase
+func buildPhases(managers []ClusterResourceManager) ([][]ClusterResourceManager, error) {
+	managerIndexesByType := map[ResourceManagerType][]int{}
+	for i, manager := range managers {
+		if phased, ok := manager.(PhasedClusterResourceManager); ok {
+			managerIndexesByType[phased.GetType()] = append(managerIndexesByType[phased.GetType()], i)
+		}
+	}
+	//build the dag, edges point from a manager to the managers that depend on it
+	dependents := make([][]int, len(managers))
+	dependencyCounts := make([]int, len(managers))
+	for i, manager := range managers {
+		phased, ok := manager.(PhasedClusterResourceManager)
+		if !ok {
+			continue
+		}
+		for _, dependency := range phased.GetDependencies() {
+			for _, dependencyIndex := range managerIndexesByType[dependency] {
+				dependents[dependencyIndex] = append(dependents[dependencyIndex], i)
+				dependencyCounts[i]++
+			}
+		}
+	}
+	var phases [][]ClusterResourceManager
+	var current []int
+	for i := range managers {
+		if dependencyCounts[i] == 0 {
+			current = append(current, i)
+		}
+	}
+	ordered := 0
+	for len(current) > 0 {
+		phase := make([]ClusterResourceManager, 0, len(current))
+		var next []int
+		for _, i := range current {
+			phase = append(phase, managers[i])
+			for _, dependent := range dependents[i] {
+				dependencyCounts[dependent]--
+				if dependencyCounts[dependent] == 0 {
+					next = append(next, dependent)
+				}
+			}
+		}
+		ordered += len(current)
+		phases = append(phases, phase)
+		//keep the order the managers were provided in within each phase
+		sort.Ints(next)
+		current = next
+	}
+	if ordered != len(managers) {
+		var cyclic []string
+		for i, manager := range managers {
+			if dependencyCounts[i] > 0 {
+				cyclic = append(cyclic, manager.GetName())
+			}
+		}
+		return nil, fmt.Errorf("dependency cycle between resource managers: %s", strings.Join(cyclic, ", "))
+	}
+	return phases, nil
+}
@@ -0,0 +1,121 @@
+package aws
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/sirupsen/logrus"
+)
+
+func TestBuildPhases(t *testing.T) {
+	fakeLogger, err := fakeLogger(func(l *logrus.Entry) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeManager := func(managerType ResourceManagerType, dependencies ...ResourceManagerType) ClusterResourceManager {
+		manager, err := fakePhasedClusterManager(managerType, dependencies, func(e *ClusterResourceManagerMock) error {
+			e.GetNameFunc = func() string {
+				return string(managerType)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return manager
+	}
+	fakeSession, err := session.NewSession(&aws.Config{Region: aws.String("eu-west-1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		managers []ClusterResourceManager
+		want     [][]string
+		wantErr  string
+	}{
+		{
+			name: "managers without dependencies run in a single phase",
+			managers: []ClusterResourceManager{
+				fakeManager(managerS3),
+				fakeManager(managerRDS),
+			},
+			want: [][]string{
+				{string(managerS3), string(managerRDS)},
+			},
+		},
+		{
+			name: "managers run in a later phase than their dependencies",
+			managers: []ClusterResourceManager{
+				fakeManager(managerVpc, managerSubnet),
+				fakeManager(managerRDSSubnetGroup, managerRDS),
+				fakeManager(managerSubnet, managerRDS),
+				fakeManager(managerRDS),
+			},
+			want: [][]string{
+				{string(managerRDS)},
+				{string(managerRDSSubnetGroup), string(managerSubnet)},
+				{string(managerVpc)},
+			},
+		},
+		{
+			name: "dependencies on managers that are not provided are ignored",
+			managers: []ClusterResourceManager{
+				fakeManager(managerVpc, managerSubnet, managerRouteTable),
+			},
+			want: [][]string{
+				{string(managerVpc)},
+			},
+		},
+		{
+			name: "error when dependencies are cyclic",
+			managers: []ClusterResourceManager{
+				fakeManager(managerS3),
+				fakeManager(managerSubnet, managerVpc),
+				fakeManager(managerVpc, managerSubnet),
+			},
+			wantErr: "dependency cycle between resource managers: aws_ec2_subnet, aws_ec2_vpc",
+		},
+		{
+			name:     "default managers delete the vpc last",
+			managers: NewDefaultClient(fakeSession, fakeLogger).ResourceManagers,
+			want: [][]string{
+				{"AWS RDS Manager", "AWS RDS Cluster Manager", "AWS ElastiCache Manager", "AWS S3 Manager", "AWS EC2 Vpc Peering Connection Manager"},
+				{"AWS RDS Subnet Group Manager", "AWS RDS Parameter Group Manager", "AWS RDS Cluster Parameter Group Manager", "AWS RDS Snapshot Manager", "AWS RDS Cluster Snapshot Manager", "AWS RDS Automated Backup Manager", "AWS ElastiCache Snapshot Manager", "AWS EC2 SecurityGroup Manager"},
+				{"AWS RDS Option Group Manager", "AWS EC2 Subnet Manager"},
+				{"AWS EC2 RouteTable Manager"},
+				{"AWS EC2 Vpc Manager"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildPhases(tt.managers)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("buildPhases() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildPhases() unexpected error = %v", err)
+			}
+			var gotNames [][]string
+			for _, phase := range got {
+				var phaseNames []string
+				for _, manager := range phase {
+					phaseNames = append(phaseNames, manager.GetName())
+				}
+				gotNames = append(gotNames, phaseNames)
+			}
+			if !reflect.DeepEqual(gotNames, tt.want) {
+				t.Errorf("buildPhases() got = %v, want %v", gotNames, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package aws
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+)
+
+const (
+	//DefaultProtectionTagKey Resources carrying this tag are never deleted by the default safety policy
+	DefaultProtectionTagKey = "integreatly.org/do-not-delete"
+)
+
+//SafetyPolicy Rules evaluated before every deletion, resources the rules forbid deleting are reported as protected
+//Rules are evaluated in order, deny globs first, then the protection tag, the allow globs and finally the minimum age
+type SafetyPolicy struct {
+	//ProtectionTagKey Resources carrying this tag with any value except false are protected, no tag is checked when empty
+	ProtectionTagKey string
+	//Allow Globs matched against the id and name of resources, when set only matching resources may be deleted
+	Allow []string
+	//Deny Globs matched against the id and name of resources, matching resources are protected
+	Deny []string
+	//MinimumAge Resources created more recently are protected, as are resources whose creation time is unknown when they're discovered
+	//The rule isn't applied to resource types which have no creation time, e.g. vpcs, these are reported as age not applicable
+	MinimumAge time.Duration
+	//now Current time, overridden in tests
+	now func() time.Time
+}
+
+//NewDefaultSafetyPolicy Build a safety policy protecting resources with the default protection tag
+func NewDefaultSafetyPolicy() *SafetyPolicy {
+	return &SafetyPolicy{
+		ProtectionTagKey: DefaultProtectionTagKey,
+	}
+}
+
+//Evaluate Check whether the resource of a report item may be deleted, returning the reason when it's protected
+func (p *SafetyPolicy) Evaluate(reportItem *clusterservice.ReportItem, creationTime *time.Time) (bool, string) {
+	if p == nil {
+		return false, ""
+	}
+	if protected, reason := p.evaluateRules(reportItem); protected || p.MinimumAge <= 0 {
+		return protected, reason
+	}
+	//an unknown age can't be proven old enough, a missing timestamp must not disable the rule
+	if creationTime == nil {
+		return true, "creation time unknown"
+	}
+	now := time.Now
+	if p.now != nil {
+		now = p.now
+	}
+	if age := now().Sub(*creationTime); age < p.MinimumAge {
+		return true, fmt.Sprintf("created %s ago, less than the minimum age of %s", age.Round(time.Second), p.MinimumAge)
+	}
+	return false, ""
+}
+
+//EvaluateWithoutCreationTime Check whether the resource of a report item may be deleted, for resource types which have no creation time
+//The minimum age rule isn't applied, when it's set the returned reason records that it didn't apply to an unprotected resource
+func (p *SafetyPolicy) EvaluateWithoutCreationTime(reportItem *clusterservice.ReportItem) (bool, string) {
+	if p == nil {
+		return false, ""
+	}
+	if protected, reason := p.evaluateRules(reportItem); protected || p.MinimumAge <= 0 {
+		return protected, reason
+	}
+	return false, "age not applicable, the resource type has no creation time"
+}
+
+//evaluateRules Evaluate every rule except the minimum age
+func (p *SafetyPolicy) evaluateRules(reportItem *clusterservice.ReportItem) (bool, string) {
+	if pattern, ok := matchAnyGlob(p.Deny, reportItem.ID, reportItem.Name); ok {
+		return true, fmt.Sprintf("matches deny rule %s", pattern)
+	}
+	if p.ProtectionTagKey != "" {
+		if value, ok := reportItem.Tags[p.ProtectionTagKey]; ok && !strings.EqualFold(value, "false") {
+			return true, fmt.Sprintf("has protection tag %s", p.ProtectionTagKey)
+		}
+	}
+	if len(p.Allow) > 0 {
+		if _, ok := matchAnyGlob(p.Allow, reportItem.ID, reportItem.Name); !ok {
+			return true, "does not match any allow rule"
+		}
+	}
+	return false, ""
+}
+
+//matchAnyGlob Find the first glob matching any of the values
+func matchAnyGlob(patterns []string, values ...string) (string, bool) {
+	for _, pattern := range patterns {
+		for _, value := range values {
+			if value != "" && matchGlob(pattern, value) {
+				return pattern, true
+			}
+		}
+	}
+	return "", false
+}
+
+//matchGlob Match a value against a glob, * matches any characters including the / and : separators of arns and ? matches a single character
+func matchGlob(pattern string, value string) bool {
+	expression := regexp.QuoteMeta(pattern)
+	expression = strings.ReplaceAll(expression, `\*`, ".*")
+	expression = strings.ReplaceAll(expression, `\?`, ".")
+	return regexp.MustCompile("^" + expression + "$").MatchString(value)
+}
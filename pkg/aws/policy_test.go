@@ -0,0 +1,156 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+)
+
+func TestSafetyPolicy_Evaluate(t *testing.T) {
+	now := time.Date(2021, 3, 5, 12, 0, 0, 0, time.UTC)
+	hourAgo := now.Add(-time.Hour)
+	weekAgo := now.Add(-7 * 24 * time.Hour)
+	fakeItem := func(tags map[string]string) *clusterservice.ReportItem {
+		return &clusterservice.ReportItem{
+			ID:   "arn:aws:rds:eu-west-1:123456789012:snapshot:test-final",
+			Name: "test-final",
+			Tags: tags,
+		}
+	}
+	tests := []struct {
+		name          string
+		policy        *SafetyPolicy
+		item          *clusterservice.ReportItem
+		creationTime  *time.Time
+		wantProtected bool
+		wantReason    string
+	}{
+		{
+			name:   "nothing is protected without a policy",
+			policy: nil,
+			item:   fakeItem(map[string]string{DefaultProtectionTagKey: "true"}),
+		},
+		{
+			name:          "resources with the protection tag are protected",
+			policy:        NewDefaultSafetyPolicy(),
+			item:          fakeItem(map[string]string{DefaultProtectionTagKey: "yes"}),
+			wantProtected: true,
+			wantReason:    "has protection tag integreatly.org/do-not-delete",
+		},
+		{
+			name:   "protection tag set to false does not protect",
+			policy: NewDefaultSafetyPolicy(),
+			item:   fakeItem(map[string]string{DefaultProtectionTagKey: "False"}),
+		},
+		{
+			name:          "deny globs match arns across separators",
+			policy:        &SafetyPolicy{Deny: []string{"arn:aws:rds:*:snapshot:*"}},
+			item:          fakeItem(nil),
+			wantProtected: true,
+			wantReason:    "matches deny rule arn:aws:rds:*:snapshot:*",
+		},
+		{
+			name:          "deny rules win over allow rules",
+			policy:        &SafetyPolicy{Allow: []string{"test-*"}, Deny: []string{"*-final"}},
+			item:          fakeItem(nil),
+			wantProtected: true,
+			wantReason:    "matches deny rule *-final",
+		},
+		{
+			name:   "resources matching an allow glob by name are not protected",
+			policy: &SafetyPolicy{Allow: []string{"test-????l"}},
+			item:   fakeItem(nil),
+		},
+		{
+			name:          "resources matching no allow glob are protected",
+			policy:        &SafetyPolicy{Allow: []string{"other-*"}},
+			item:          fakeItem(nil),
+			wantProtected: true,
+			wantReason:    "does not match any allow rule",
+		},
+		{
+			name:          "resources younger than the minimum age are protected",
+			policy:        &SafetyPolicy{MinimumAge: 24 * time.Hour, now: func() time.Time { return now }},
+			item:          fakeItem(nil),
+			creationTime:  &hourAgo,
+			wantProtected: true,
+			wantReason:    "created 1h0m0s ago, less than the minimum age of 24h0m0s",
+		},
+		{
+			name:         "resources older than the minimum age are not protected",
+			policy:       &SafetyPolicy{MinimumAge: 24 * time.Hour, now: func() time.Time { return now }},
+			item:         fakeItem(nil),
+			creationTime: &weekAgo,
+		},
+		{
+			name:          "resources without a creation time are protected by the minimum age",
+			policy:        &SafetyPolicy{MinimumAge: 24 * time.Hour, now: func() time.Time { return now }},
+			item:          fakeItem(nil),
+			wantProtected: true,
+			wantReason:    "creation time unknown",
+		},
+		{
+			name:   "resources without a creation time are not protected without a minimum age",
+			policy: NewDefaultSafetyPolicy(),
+			item:   fakeItem(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotProtected, gotReason := tt.policy.Evaluate(tt.item, tt.creationTime)
+			if gotProtected != tt.wantProtected || gotReason != tt.wantReason {
+				t.Errorf("Evaluate() got = %v %q, want %v %q", gotProtected, gotReason, tt.wantProtected, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestSafetyPolicy_EvaluateWithoutCreationTime(t *testing.T) {
+	fakeItem := func(tags map[string]string) *clusterservice.ReportItem {
+		return &clusterservice.ReportItem{
+			ID:   "arn:aws:ec2:eu-west-1:123456789012:vpc/vpc-test",
+			Name: "vpc-test",
+			Tags: tags,
+		}
+	}
+	tests := []struct {
+		name          string
+		policy        *SafetyPolicy
+		item          *clusterservice.ReportItem
+		wantProtected bool
+		wantReason    string
+	}{
+		{
+			name:   "nothing is protected without a policy",
+			policy: nil,
+			item:   fakeItem(nil),
+		},
+		{
+			name:       "the minimum age doesn't apply to resource types without a creation time",
+			policy:     &SafetyPolicy{MinimumAge: 24 * time.Hour},
+			item:       fakeItem(nil),
+			wantReason: "age not applicable, the resource type has no creation time",
+		},
+		{
+			name:          "the other rules still apply",
+			policy:        &SafetyPolicy{ProtectionTagKey: DefaultProtectionTagKey, MinimumAge: 24 * time.Hour},
+			item:          fakeItem(map[string]string{DefaultProtectionTagKey: "true"}),
+			wantProtected: true,
+			wantReason:    "has protection tag integreatly.org/do-not-delete",
+		},
+		{
+			name:   "no reason is given without a minimum age",
+			policy: NewDefaultSafetyPolicy(),
+			item:   fakeItem(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotProtected, gotReason := tt.policy.EvaluateWithoutCreationTime(tt.item)
+			if gotProtected != tt.wantProtected || gotReason != tt.wantReason {
+				t.Errorf("EvaluateWithoutCreationTime() got = %v %q, want %v %q", gotProtected, gotReason, tt.wantProtected, tt.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package aws
+
+import "sync"
+
+//workerPool Run functions in goroutines, with at most a fixed number running at the same time
+type workerPool struct {
+	wg        sync.WaitGroup
+	semaphore chan struct{}
+	mu        sync.Mutex
+	errs      []error
+}
+
+//newWorkerPool Create a pool running at most concurrency functions at once, anything below one runs functions one at a time
+func newWorkerPool(concurrency int) *workerPool {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &workerPool{
+		semaphore: make(chan struct{}, concurrency),
+	}
+}
+
+//Go Run fn once a worker is free, blocking until then
+func (p *workerPool) Go(fn func() error) {
+	p.mu.Lock()
+	index := len(p.errs)
+	p.errs = append(p.errs, nil)
+	p.mu.Unlock()
+	p.wg.Add(1)
+	p.semaphore <- struct{}{}
+	go func() {
+		defer p.wg.Done()
+		defer func() { <-p.semaphore }()
+		err := fn()
+		p.mu.Lock()
+		p.errs[index] = err
+		p.mu.Unlock()
+	}()
+}
+
+//Wait Wait for every function to return, errors are returned in the order the functions were added
+func (p *workerPool) Wait() []error {
+	p.wg.Wait()
+	var errs []error
+	for _, err := range p.errs {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,79 @@
+package aws
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestWorkerPool(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+		fns         int
+		failing     map[int]bool
+		wantErrs    []error
+	}{
+		{
+			name:        "functions run one at a time when concurrency is not set",
+			concurrency: 0,
+			fns:         5,
+		},
+		{
+			name:        "functions run concurrently up to the concurrency limit",
+			concurrency: 3,
+			fns:         10,
+		},
+		{
+			name:        "errors are returned in the order functions were added",
+			concurrency: 4,
+			fns:         6,
+			failing:     map[int]bool{1: true, 4: true},
+			wantErrs:    []error{errors.New("error 1"), errors.New("error 4")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := newWorkerPool(tt.concurrency)
+			wantMax := tt.concurrency
+			if wantMax < 1 {
+				wantMax = 1
+			}
+			var mu sync.Mutex
+			running, maxRunning, ran := 0, 0, 0
+			for i := 0; i < tt.fns; i++ {
+				i := i
+				pool.Go(func() error {
+					mu.Lock()
+					running++
+					ran++
+					if running > maxRunning {
+						maxRunning = running
+					}
+					mu.Unlock()
+					defer func() {
+						mu.Lock()
+						running--
+						mu.Unlock()
+					}()
+					if tt.failing[i] {
+						return fmt.Errorf("error %d", i)
+					}
+					return nil
+				})
+			}
+			gotErrs := pool.Wait()
+			if ran != tt.fns {
+				t.Errorf("Wait() returned after %d functions ran, want %d", ran, tt.fns)
+			}
+			if maxRunning > wantMax {
+				t.Errorf("Go() ran %d functions at the same time, want at most %d", maxRunning, wantMax)
+			}
+			if !reflect.DeepEqual(gotErrs, tt.wantErrs) {
+				t.Errorf("Wait() got = %v, want %v", gotErrs, tt.wantErrs)
+			}
+		})
+	}
+}
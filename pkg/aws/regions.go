@@ -0,0 +1,28 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+)
+
+//ListEnabledRegions List the regions enabled for the account, sorted by name
+//Opt-in regions are only included once the account has opted in to them
+func ListEnabledRegions(ctx context.Context, ec2Client ec2iface.EC2API) ([]string, error) {
+	output, err := ec2Client.DescribeRegionsWithContext(ctx, &ec2.DescribeRegionsInput{
+		AllRegions: aws.Bool(false),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe regions: %w", err)
+	}
+	regions := make([]string, 0, len(output.Regions))
+	for _, region := range output.Regions {
+		regions = append(regions, aws.StringValue(region.RegionName))
+	}
+	sort.Strings(regions)
+	return regions, nil
+}
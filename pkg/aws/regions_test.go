@@ -0,0 +1,64 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestListEnabledRegions(t *testing.T) {
+	tests := []struct {
+		name    string
+		ec2     *mockEc2Client
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "enabled regions are sorted",
+			ec2: buildMockEc2Client(func(c *mockEc2Client) {
+				c.describeRegionsFn = func(input *ec2.DescribeRegionsInput) (*ec2.DescribeRegionsOutput, error) {
+					if aws.BoolValue(input.AllRegions) {
+						return nil, errors.New("regions the account has not opted in to should not be requested")
+					}
+					return &ec2.DescribeRegionsOutput{
+						Regions: []*ec2.Region{
+							{RegionName: aws.String("us-east-1")},
+							{RegionName: aws.String("eu-west-1")},
+						},
+					}, nil
+				}
+			}),
+			want: []string{"eu-west-1", "us-east-1"},
+		},
+		{
+			name: "error when regions can't be described",
+			ec2: buildMockEc2Client(func(c *mockEc2Client) {
+				c.describeRegionsFn = func(input *ec2.DescribeRegionsInput) (*ec2.DescribeRegionsOutput, error) {
+					return nil, errors.New("access denied")
+				}
+			}),
+			wantErr: "failed to describe regions: access denied",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ListEnabledRegions(context.TODO(), tt.ec2)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ListEnabledRegions() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListEnabledRegions() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListEnabledRegions() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
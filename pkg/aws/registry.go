@@ -0,0 +1,232 @@
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/sirupsen/logrus"
+)
+
+// ManagerFactory Build a resource manager using a session
+type ManagerFactory func(awsSession *session.Session, logger *logrus.Entry) ClusterResourceManager
+
+// RegisteredManager Resource manager registered under a canonical type name
+type RegisteredManager struct {
+	//Name Canonical type name used to select the manager, e.g. rds:instance
+	Name string
+	//Type Type of the manager, used to order managers by their dependencies and to key their state
+	Type ResourceManagerType
+	//ResourceTypes Tagging api resource types of the manager, searched for the cluster id tag when finding clusters
+	ResourceTypes []string
+	Description   string
+	New           ManagerFactory
+}
+
+// managerRegistry Every resource manager, in the order the default client runs them
+var managerRegistry = []*RegisteredManager{
+	{
+		Name:          "rds:instance",
+		Type:          managerRDS,
+		ResourceTypes: []string{resourceTypeRDSInstance},
+		Description:   "rds db instances which aren't members of a db cluster, deletion protection is disabled before deleting them",
+		New: func(awsSession *session.Session, logger *logrus.Entry) ClusterResourceManager {
+			return NewDefaultRDSInstanceManager(awsSession, logger)
+		},
+	},
+	{
+		Name:          "rds:cluster",
+		Type:          managerRDSCluster,
+		ResourceTypes: []string{resourceTypeRDSCluster},
+		Description:   "rds db clusters such as aurora, their member instances are deleted first and deletion protection is disabled",
+		New: func(awsSession *session.Session, logger *logrus.Entry) ClusterResourceManager {
+			return NewDefaultRDSClusterManager(awsSession, logger)
+		},
+	},
+	{
+		Name:          "rds:subnetgroup",
+		Type:          managerRDSSubnetGroup,
+		ResourceTypes: []string{resourceTypeDBSubnetGroup},
+		Description:   "rds db subnet groups, once the instances and clusters using them are deleted",
+		New: func(awsSession *session.Session, logger *logrus.Entry) ClusterResourceManager {
+			return NewDefaultRDSSubnetGroupManager(awsSession, logger)
+		},
+	},
+	{
+		Name:          "rds:parameter-group",
+		Type:          managerRDSParameterGroup,
+		ResourceTypes: []string{resourceTypeRDSParameterGroup},
+		Description:   "custom rds db parameter groups, once no db instance uses them",
+		New: func(awsSession *session.Session, logger *logrus.Entry) ClusterResourceManager {
+			return NewDefaultRDSParameterGroupManager(awsSession, logger)
+		},
+	},
+	{
+		Name:          "rds:cluster-parameter-group",
+		Type:          managerRDSClusterParameterGroup,
+		ResourceTypes: []string{resourceTypeRDSClusterParameterGroup},
+		Description:   "custom rds db cluster parameter groups, once no db cluster uses them",
+		New: func(awsSession *session.Session, logger *logrus.Entry) ClusterResourceManager {
+			return NewDefaultRDSClusterParameterGroupManager(awsSession, logger)
+		},
+	},
+	{
+		Name:          "rds:option-group",
+		Type:          managerRDSOptionGroup,
+		ResourceTypes: []string{resourceTypeRDSOptionGroup},
+		Description:   "custom rds option groups, once no db instance uses them",
+		New: func(awsSession *session.Session, logger *logrus.Entry) ClusterResourceManager {
+			return NewDefaultRDSOptionGroupManager(awsSession, logger)
+		},
+	},
+	{
+		Name:          "elasticache:replicationgroup",
+		Type:          managerElasticache,
+		ResourceTypes: []string{resourceTypeElasticacheCluster},
+		Description:   "elasticache replication groups along with their subnet groups",
+		New: func(awsSession *session.Session, logger *logrus.Entry) ClusterResourceManager {
+			return NewDefaultElasticacheManager(awsSession, logger)
+		},
+	},
+	{
+		Name:          "s3",
+		Type:          managerS3,
+		ResourceTypes: []string{resourceTypeS3},
+		Description:   "s3 buckets, emptied before they're deleted",
+		New: func(awsSession *session.Session, logger *logrus.Entry) ClusterResourceManager {
+			return NewDefaultS3Engine(awsSession, logger)
+		},
+	},
+	{
+		Name:          "rds:snapshot",
+		Type:          managerRDSSnapshot,
+		ResourceTypes: []string{resourceTypeRDSSnapshot},
+		Description:   "manual rds db snapshots",
+		New: func(awsSession *session.Session, logger *logrus.Entry) ClusterResourceManager {
+			return NewDefaultRDSSnapshotManager(awsSession, logger)
+		},
+	},
+	{
+		Name:          "rds:cluster-snapshot",
+		Type:          managerRDSClusterSnapshot,
+		ResourceTypes: []string{resourceTypeRDSClusterSnapshot},
+		Description:   "manual rds db cluster snapshots",
+		New: func(awsSession *session.Session, logger *logrus.Entry) ClusterResourceManager {
+			return NewDefaultRDSClusterSnapshotManager(awsSession, logger)
+		},
+	},
+	{
+		Name:        "rds:automated-backup",
+		Type:        managerRDSAutomatedBackup,
+		Description: "rds automated backups retained after their db instance was deleted",
+		New: func(awsSession *session.Session, logger *logrus.Entry) ClusterResourceManager {
+			return NewDefaultRDSAutomatedBackupManager(awsSession, logger)
+		},
+	},
+	{
+		Name:          "elasticache:snapshot",
+		Type:          managerElasticacheSnapshot,
+		ResourceTypes: []string{resourceTypeElasticacheSnapshot},
+		Description:   "elasticache snapshots",
+		New: func(awsSession *session.Session, logger *logrus.Entry) ClusterResourceManager {
+			return NewDefaultElasticacheSnapshotManager(awsSession, logger)
+		},
+	},
+	{
+		Name:          "ec2:vpc-peering-connection",
+		Type:          managerVpcPeering,
+		ResourceTypes: []string{resourceTypeVpcPeeringConnection},
+		Description:   "ec2 vpc peering connections",
+		New: func(awsSession *session.Session, logger *logrus.Entry) ClusterResourceManager {
+			return NewDefaultVpcPeeringManager(awsSession, logger)
+		},
+	},
+	{
+		Name:          "ec2:subnet",
+		Type:          managerSubnet,
+		ResourceTypes: []string{resourceTypeSubnet},
+		Description:   "ec2 subnets",
+		New: func(awsSession *session.Session, logger *logrus.Entry) ClusterResourceManager {
+			return NewDefaultSubnetManager(awsSession, logger)
+		},
+	},
+	{
+		Name:          "ec2:security-group",
+		Type:          managerSecurityGroup,
+		ResourceTypes: []string{resourceTypeSecurityGroup},
+		Description:   "ec2 security groups",
+		New: func(awsSession *session.Session, logger *logrus.Entry) ClusterResourceManager {
+			return NewDefaultSecurityGroupManager(awsSession, logger)
+		},
+	},
+	{
+		Name:          "ec2:route-table",
+		Type:          managerRouteTable,
+		ResourceTypes: []string{resourceTypeRouteTable},
+		Description:   "ec2 route tables",
+		New: func(awsSession *session.Session, logger *logrus.Entry) ClusterResourceManager {
+			return NewDefaultRouteTableManager(awsSession, logger)
+		},
+	},
+	{
+		Name:          "ec2:vpc",
+		Type:          managerVpc,
+		ResourceTypes: []string{resourceTypeVpc},
+		Description:   "ec2 vpcs, once their subnets, security groups, route tables and peering connections are deleted",
+		New: func(awsSession *session.Session, logger *logrus.Entry) ClusterResourceManager {
+			return NewDefaultVpcManager(awsSession, logger)
+		},
+	},
+}
+
+// RegisteredManagers Every registered resource manager, in the order the default client runs them
+func RegisteredManagers() []*RegisteredManager {
+	managers := make([]*RegisteredManager, len(managerRegistry))
+	copy(managers, managerRegistry)
+	return managers
+}
+
+// LookupManager Find the registered manager with a canonical type name
+func LookupManager(name string) (*RegisteredManager, bool) {
+	for _, manager := range managerRegistry {
+		if manager.Name == name {
+			return manager, true
+		}
+	}
+	return nil, false
+}
+
+// SelectManagers Select the registered managers matching any of the include globs and none of the exclude globs, e.g. ec2:*
+// Every manager is included when no include globs are provided, a glob matching no manager is an error so typos aren't silently ignored
+func SelectManagers(include []string, exclude []string) ([]*RegisteredManager, error) {
+	names := registeredManagerNames()
+	for _, pattern := range append(append([]string{}, include...), exclude...) {
+		if _, ok := matchAnyGlob([]string{pattern}, names...); !ok {
+			return nil, fmt.Errorf("unknown resource type %s", pattern)
+		}
+	}
+	var selected []*RegisteredManager
+	for _, manager := range managerRegistry {
+		if len(include) > 0 {
+			if _, ok := matchAnyGlob(include, manager.Name); !ok {
+				continue
+			}
+		}
+		if _, ok := matchAnyGlob(exclude, manager.Name); ok {
+			continue
+		}
+		selected = append(selected, manager)
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no resource types left to select after excluding %s", strings.Join(exclude, ", "))
+	}
+	return selected, nil
+}
+
+func registeredManagerNames() []string {
+	names := make([]string, 0, len(managerRegistry))
+	for _, manager := range managerRegistry {
+		names = append(names, manager.Name)
+	}
+	return names
+}
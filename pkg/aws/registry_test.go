@@ -0,0 +1,124 @@
+package aws
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRegisteredManagers(t *testing.T) {
+	awsSession := session.Must(session.NewSession())
+	logger := logrus.NewEntry(logrus.StandardLogger())
+	names := map[string]bool{}
+	types := map[ResourceManagerType]bool{}
+	for _, manager := range RegisteredManagers() {
+		if names[manager.Name] {
+			t.Errorf("RegisteredManagers() name %s registered twice", manager.Name)
+		}
+		names[manager.Name] = true
+		if types[manager.Type] {
+			t.Errorf("RegisteredManagers() type %s registered twice", manager.Type)
+		}
+		types[manager.Type] = true
+		phased, ok := manager.New(awsSession, logger).(PhasedClusterResourceManager)
+		if !ok {
+			t.Errorf("RegisteredManagers() %s doesn't build a phased manager", manager.Name)
+			continue
+		}
+		if got := phased.GetType(); got != manager.Type {
+			t.Errorf("RegisteredManagers() %s builds a manager of type %s, want %s", manager.Name, got, manager.Type)
+		}
+	}
+	if _, err := buildPhases(NewDefaultClient(awsSession, logger).ResourceManagers); err != nil {
+		t.Errorf("RegisteredManagers() managers can't be ordered into phases: %v", err)
+	}
+}
+
+func TestSelectManagers(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "every manager when nothing is included",
+			want: registeredManagerNames(),
+		},
+		{
+			name:    "managers matching a glob in registry order",
+			include: []string{"ec2:*", "s3"},
+			want:    []string{"s3", "ec2:vpc-peering-connection", "ec2:subnet", "ec2:security-group", "ec2:route-table", "ec2:vpc"},
+		},
+		{
+			name:    "excluded managers are removed",
+			include: []string{"rds:*"},
+			exclude: []string{"*snapshot", "rds:automated-backup", "*-group"},
+			want:    []string{"rds:instance", "rds:cluster", "rds:subnetgroup"},
+		},
+		{
+			name:    "error on unknown included type",
+			include: []string{"rds:instances"},
+			wantErr: "unknown resource type rds:instances",
+		},
+		{
+			name:    "error on unknown excluded type",
+			exclude: []string{"ec3:*"},
+			wantErr: "unknown resource type ec3:*",
+		},
+		{
+			name:    "error when every selected manager is excluded",
+			include: []string{"s3"},
+			exclude: []string{"s3"},
+			wantErr: "no resource types left to select after excluding s3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectManagers(tt.include, tt.exclude)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("SelectManagers() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectManagers() unexpected error = %v", err)
+			}
+			var gotNames []string
+			for _, manager := range got {
+				gotNames = append(gotNames, manager.Name)
+			}
+			if !reflect.DeepEqual(gotNames, tt.want) {
+				t.Errorf("SelectManagers() got = %v, want %v", gotNames, tt.want)
+			}
+		})
+	}
+}
+
+func TestClusterResourceTypes(t *testing.T) {
+	want := []string{
+		resourceTypeRDSInstance,
+		resourceTypeRDSCluster,
+		resourceTypeDBSubnetGroup,
+		resourceTypeRDSParameterGroup,
+		resourceTypeRDSClusterParameterGroup,
+		resourceTypeRDSOptionGroup,
+		resourceTypeElasticacheCluster,
+		resourceTypeS3,
+		resourceTypeRDSSnapshot,
+		resourceTypeRDSClusterSnapshot,
+		resourceTypeElasticacheSnapshot,
+		resourceTypeVpcPeeringConnection,
+		resourceTypeSubnet,
+		resourceTypeSecurityGroup,
+		resourceTypeRouteTable,
+		resourceTypeVpc,
+	}
+	if got := clusterResourceTypes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("clusterResourceTypes() got = %v, want %v", got, want)
+	}
+}
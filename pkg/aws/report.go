@@ -0,0 +1,13 @@
+package aws
+
+import (
+	"github.com/integr8ly/cluster-service/pkg/clusterservice"
+)
+
+//failReportItem Mark a report item as failed with the error as the reason, returning the error so it can be aggregated
+func failReportItem(reportItem *clusterservice.ReportItem, err error) error {
+	reportItem.ActionStatus = clusterservice.ActionStatusFailed
+	reportItem.StatusReason = err.Error()
+	reportItem.NextCheckAfter = 0
+	return err
+}
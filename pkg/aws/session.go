@@ -0,0 +1,61 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+const (
+	//DefaultSessionName Name of the role session when a role is assumed without a session name
+	DefaultSessionName = "cluster-service"
+)
+
+//SessionOptions Where the credentials of an aws session come from
+type SessionOptions struct {
+	Region string
+	//Profile Shared config profile to use, the AWS_PROFILE env var or the default profile when empty
+	Profile string
+	//RoleARN Role to assume with the credentials found by the credential chain, those credentials are used directly when empty
+	RoleARN string
+	//ExternalID External id required by the trust policy of the role
+	ExternalID string
+	//SessionName Name of the role session, shown in cloudtrail, DefaultSessionName when empty
+	SessionName string
+}
+
+//NewSession Build an aws session using the standard credential chain, env vars including session tokens, shared config profiles, sso, web identity and instance roles
+//When a role arn is set the role is assumed using the credentials found by the chain, e.g. to reach a customer account from a central tooling account
+func NewSession(opts SessionOptions) (*session.Session, error) {
+	if opts.RoleARN == "" && (opts.ExternalID != "" || opts.SessionName != "") {
+		return nil, fmt.Errorf("external id and session name can only be set when assuming a role")
+	}
+	baseSession, err := session.NewSessionWithOptions(session.Options{
+		Config: aws.Config{
+			Region: aws.String(opts.Region),
+		},
+		Profile:           opts.Profile,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create aws session: %w", err)
+	}
+	if opts.RoleARN == "" {
+		return baseSession, nil
+	}
+	sessionName := opts.SessionName
+	if sessionName == "" {
+		sessionName = DefaultSessionName
+	}
+	roleCredentials := stscreds.NewCredentials(baseSession, opts.RoleARN, func(p *stscreds.AssumeRoleProvider) {
+		p.RoleSessionName = sessionName
+		if opts.ExternalID != "" {
+			p.ExternalID = aws.String(opts.ExternalID)
+		}
+	})
+	return baseSession.Copy(&aws.Config{
+		Credentials: roleCredentials,
+	}), nil
+}
@@ -0,0 +1,68 @@
+package aws
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestNewSession(t *testing.T) {
+	//keep the shared config of the machine running the tests out of the credential chain
+	configDir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(configDir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(configDir, "credentials"))
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "fakeKey")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "fakeSecret")
+	tests := []struct {
+		name           string
+		opts           SessionOptions
+		wantAssumeRole bool
+		wantErr        string
+	}{
+		{
+			name: "credentials are found through the credential chain",
+			opts: SessionOptions{Region: "eu-west-1"},
+		},
+		{
+			name:           "role is assumed with the credentials found",
+			opts:           SessionOptions{Region: "eu-west-1", RoleARN: "arn:aws:iam::123456789012:role/cleanup", ExternalID: "fakeExternalId"},
+			wantAssumeRole: true,
+		},
+		{
+			name:    "error when an external id is set without a role",
+			opts:    SessionOptions{Region: "eu-west-1", ExternalID: "fakeExternalId"},
+			wantErr: "external id and session name can only be set when assuming a role",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSession(tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewSession() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewSession() unexpected error = %v", err)
+			}
+			if region := aws.StringValue(got.Config.Region); region != tt.opts.Region {
+				t.Errorf("NewSession() region = %s, want %s", region, tt.opts.Region)
+			}
+			//assumed role credentials expire, checking doesn't make the request to assume the role
+			if tt.wantAssumeRole {
+				if _, err := got.Config.Credentials.ExpiresAt(); err != nil {
+					t.Errorf("NewSession() credentials of an assumed role should expire, got error %v", err)
+				}
+				return
+			}
+			value, err := got.Config.Credentials.Get()
+			if err != nil || value.AccessKeyID != "fakeKey" {
+				t.Errorf("NewSession() credentials = %v, %v, want the env credentials", value.AccessKeyID, err)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package aws
 
 import (
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
 )
 
@@ -20,3 +21,34 @@ func convertClusterTagsToAWSTagFilter(clusterId string, additionalTags map[strin
 	}
 	return tagFilters
 }
+
+//convertAWSTagsToMap Convert tags returned by the resource tagging api to a map of tag keys to values
+func convertAWSTagsToMap(tags []*resourcegroupstaggingapi.Tag) map[string]string {
+	tagMap := map[string]string{}
+	for _, tag := range tags {
+		tagMap[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tagMap
+}
+
+//convertRDSTagsToMap Convert tags returned by the rds api to a map of tag keys to values
+func convertRDSTagsToMap(tags []*rds.Tag) map[string]string {
+	tagMap := map[string]string{}
+	for _, tag := range tags {
+		tagMap[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tagMap
+}
+
+//hasRDSClusterTags Whether tags returned by the rds api contain the cluster id tag and every additional tag
+func hasRDSClusterTags(clusterId string, additionalTags map[string]string, tags []*rds.Tag) bool {
+	if findTag(tagKeyClusterId, clusterId, tags) == nil {
+		return false
+	}
+	for tagKey, tagVal := range additionalTags {
+		if findTag(tagKey, tagVal, tags) == nil {
+			return false
+		}
+	}
+	return true
+}
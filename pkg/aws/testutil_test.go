@@ -13,10 +13,13 @@ import (
 
 	"github.com/aws/aws-sdk-go/service/rds"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	"github.com/integr8ly/cluster-service/pkg/clusterservice"
 	"github.com/sirupsen/logrus"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 )
 
 const (
@@ -25,6 +28,8 @@ const (
 	fakeArnWithSlash       = "arn:fake:resourceType/testIdentifier"
 	fakeResourceIdentifier = "testIdentifier"
 	fakeClusterId          = "clusterId"
+	fakeAccountId          = "123456789012"
+	fakeCallerARN          = "arn:aws:iam::123456789012:user/fake"
 
 	//ec2-specific
 	fakeEc2ClientInstanceArn = fakeArnWithSlash
@@ -36,9 +41,16 @@ const (
 	fakeRDSClientInstanceARN                = fakeARN
 	fakeRDSClientInstanceDeletionProtection = true
 	fakeRDSClientDBSubnetGroupARN           = fakeARN
+	fakeRDSClientDBClusterIdentifier        = "testDBCluster"
+	fakeRDSClientDBClusterARN               = "arn:aws:rds:eu-west-1:123456789012:cluster:testDBCluster"
+	fakeRDSClientClusterMemberIdentifier    = "testDBClusterMember"
+	fakeRDSClientClusterMemberARN           = "arn:aws:rds:eu-west-1:123456789012:db:testDBClusterMember"
+	fakeRDSClientAutomatedBackupARN         = "arn:aws:rds:eu-west-1:123456789012:auto-backup:ab-test"
+	fakeRDSClientDbiResourceId              = "db-TEST"
+
+	fakeRegion = "eu-west-1"
 
 	//ELasticache-specific
-	fakeElasticacheClientName               = "elasticache Replication group"
 	fakeElasticacheClientReplicationGroupId = "testRepGroupID"
 	fakeElasticacheClientDescription        = "TestDescription"
 	fakeElasticacheClientEngine             = "redis"
@@ -48,9 +60,9 @@ const (
 	fakeCacheClusterStatus                  = "available"
 	fakeElasticacheSnapshotName             = "elasticache snapshot"
 	fakeElasticacheSnapshotStatus           = "available"
-	fakeElasticacheSubnetGroupName          = "elasticache subnet group"
 	fakeElasticacheSubnetGroupNameValue     = "testCacheSubnetGroup"
-	fakeElasticacheSubnetGroupID            = "subnetgroup:testCacheSubnetGroup"
+	fakeElasticacheSubnetGroupID            = fakeRegion + ":subnetgroup:testCacheSubnetGroup"
+	fakeElasticacheReplicationGroupID       = fakeRegion + ":replicationgroup:testRepGroupID"
 
 	//resource tagging-specific
 	fakeResourceTagMappingARN = fakeARN
@@ -79,6 +91,12 @@ func fakeRDSClientTag() *rds.Tag {
 	}
 }
 
+func fakeRDSClientTags() map[string]string {
+	return map[string]string{
+		fakeRDSClientTagKey: fakeRDSClientTagVal,
+	}
+}
+
 func fakeRDSClientDBInstance() *rds.DBInstance {
 	return &rds.DBInstance{
 		DBInstanceIdentifier: aws.String(fakeRDSClientInstanceIdentifier),
@@ -87,6 +105,43 @@ func fakeRDSClientDBInstance() *rds.DBInstance {
 	}
 }
 
+func fakeRDSClientDBCluster() *rds.DBCluster {
+	return &rds.DBCluster{
+		DBClusterIdentifier: aws.String(fakeRDSClientDBClusterIdentifier),
+		DBClusterArn:        aws.String(fakeRDSClientDBClusterARN),
+		DeletionProtection:  aws.Bool(true),
+		Status:              aws.String("available"),
+		TagList:             []*rds.Tag{fakeRDSClientTag()},
+	}
+}
+
+func fakeRDSClientClusterMember() *rds.DBInstance {
+	return &rds.DBInstance{
+		DBInstanceIdentifier: aws.String(fakeRDSClientClusterMemberIdentifier),
+		DBInstanceArn:        aws.String(fakeRDSClientClusterMemberARN),
+		DBClusterIdentifier:  aws.String(fakeRDSClientDBClusterIdentifier),
+		DBInstanceStatus:     aws.String("available"),
+	}
+}
+
+func fakeRDSClientDBClusterSnapshot() *rds.DBClusterSnapshot {
+	return &rds.DBClusterSnapshot{
+		DBClusterIdentifier:         aws.String(fakeRDSClientDBClusterIdentifier),
+		DBClusterSnapshotIdentifier: aws.String(fakeResourceIdentifier),
+		Status:                      aws.String(fakeSnapshotStatus),
+		SnapshotType:                aws.String(fakeSnapshotType),
+	}
+}
+
+func fakeRDSClientAutomatedBackup() *rds.DBInstanceAutomatedBackup {
+	return &rds.DBInstanceAutomatedBackup{
+		DBInstanceAutomatedBackupsArn: aws.String(fakeRDSClientAutomatedBackupARN),
+		DBInstanceIdentifier:          aws.String(fakeResourceIdentifier),
+		DbiResourceId:                 aws.String(fakeRDSClientDbiResourceId),
+		Status:                        aws.String(automatedBackupStatusRetained),
+	}
+}
+
 func fakeResourceTagMappingTag() *resourcegroupstaggingapi.Tag {
 	return &resourcegroupstaggingapi.Tag{
 		Key:   aws.String(tagKeyClusterId),
@@ -94,6 +149,12 @@ func fakeResourceTagMappingTag() *resourcegroupstaggingapi.Tag {
 	}
 }
 
+func fakeResourceTags() map[string]string {
+	return map[string]string{
+		tagKeyClusterId: fakeClusterId,
+	}
+}
+
 func fakeResourceTagMapping(modifyFn func(*resourcegroupstaggingapi.ResourceTagMapping)) *resourcegroupstaggingapi.ResourceTagMapping {
 	mock := &resourcegroupstaggingapi.ResourceTagMapping{
 		ComplianceDetails: nil,
@@ -131,44 +192,91 @@ func fakeRDSSubnetGroup() *rds.DBSubnetGroup {
 	}
 }
 
+//singlePage Paginator passing the output of a request as the only page
+func singlePage[Input any, Output any](send func(context.Context, Input, ...request.Option) (Output, error)) func(context.Context, Input, func(Output, bool) bool, ...request.Option) error {
+	return func(ctx context.Context, input Input, fn func(Output, bool) bool, opts ...request.Option) error {
+		output, err := send(ctx, input, opts...)
+		if err != nil {
+			return err
+		}
+		fn(output, true)
+		return nil
+	}
+}
+
 func fakeRDSClient(modifyFn func(c *rdsClientMock) error) (*rdsClientMock, error) {
 	if modifyFn == nil {
 		return nil, errorMustBeDefined("modifyFn")
 	}
 	client := &rdsClientMock{
-		DescribeDBInstancesFunc: func(in1 *rds.DescribeDBInstancesInput) (output *rds.DescribeDBInstancesOutput, e error) {
+		DescribeDBInstancesWithContextFunc: func(ctx context.Context, in1 *rds.DescribeDBInstancesInput, opts ...request.Option) (output *rds.DescribeDBInstancesOutput, e error) {
 			return &rds.DescribeDBInstancesOutput{
 				DBInstances: []*rds.DBInstance{
 					fakeRDSClientDBInstance(),
 				},
 			}, nil
 		},
-		ListTagsForResourceFunc: func(in1 *rds.ListTagsForResourceInput) (output *rds.ListTagsForResourceOutput, e error) {
+		ListTagsForResourceWithContextFunc: func(ctx context.Context, in1 *rds.ListTagsForResourceInput, opts ...request.Option) (output *rds.ListTagsForResourceOutput, e error) {
 			return &rds.ListTagsForResourceOutput{
 				TagList: []*rds.Tag{
 					fakeRDSClientTag(),
 				},
 			}, nil
 		},
-		ModifyDBInstanceFunc: func(in1 *rds.ModifyDBInstanceInput) (output *rds.ModifyDBInstanceOutput, e error) {
+		ModifyDBInstanceWithContextFunc: func(ctx context.Context, in1 *rds.ModifyDBInstanceInput, opts ...request.Option) (output *rds.ModifyDBInstanceOutput, e error) {
 			return &rds.ModifyDBInstanceOutput{
 				DBInstance: fakeRDSClientDBInstance(),
 			}, nil
 		},
-		DeleteDBInstanceFunc: func(in1 *rds.DeleteDBInstanceInput) (output *rds.DeleteDBInstanceOutput, e error) {
+		DeleteDBInstanceWithContextFunc: func(ctx context.Context, in1 *rds.DeleteDBInstanceInput, opts ...request.Option) (output *rds.DeleteDBInstanceOutput, e error) {
 			return &rds.DeleteDBInstanceOutput{
 				DBInstance: fakeRDSClientDBInstance(),
 			}, nil
 		},
-		DescribeDBSnapshotsFunc: func(in1 *rds.DescribeDBSnapshotsInput) (*rds.DescribeDBSnapshotsOutput, error) {
+		DescribeDBSnapshotsWithContextFunc: func(ctx context.Context, in1 *rds.DescribeDBSnapshotsInput, opts ...request.Option) (*rds.DescribeDBSnapshotsOutput, error) {
 			return &rds.DescribeDBSnapshotsOutput{
 				DBSnapshots: fakeRDSClientDBSnapshots(),
 			}, nil
 		},
-		DeleteDBSnapshotFunc: func(in1 *rds.DeleteDBSnapshotInput) (*rds.DeleteDBSnapshotOutput, error) {
+		DeleteDBSnapshotWithContextFunc: func(ctx context.Context, in1 *rds.DeleteDBSnapshotInput, opts ...request.Option) (*rds.DeleteDBSnapshotOutput, error) {
 			return &rds.DeleteDBSnapshotOutput{}, nil
 		},
-		DescribeDBSubnetGroupsFunc: func(in1 *rds.DescribeDBSubnetGroupsInput) (*rds.DescribeDBSubnetGroupsOutput, error) {
+		DescribeDBClustersWithContextFunc: func(ctx context.Context, in1 *rds.DescribeDBClustersInput, opts ...request.Option) (*rds.DescribeDBClustersOutput, error) {
+			return &rds.DescribeDBClustersOutput{
+				DBClusters: []*rds.DBCluster{
+					fakeRDSClientDBCluster(),
+				},
+			}, nil
+		},
+		ModifyDBClusterWithContextFunc: func(ctx context.Context, in1 *rds.ModifyDBClusterInput, opts ...request.Option) (*rds.ModifyDBClusterOutput, error) {
+			return &rds.ModifyDBClusterOutput{
+				DBCluster: fakeRDSClientDBCluster(),
+			}, nil
+		},
+		DeleteDBClusterWithContextFunc: func(ctx context.Context, in1 *rds.DeleteDBClusterInput, opts ...request.Option) (*rds.DeleteDBClusterOutput, error) {
+			return &rds.DeleteDBClusterOutput{}, nil
+		},
+		DescribeDBClusterSnapshotsWithContextFunc: func(ctx context.Context, in1 *rds.DescribeDBClusterSnapshotsInput, opts ...request.Option) (*rds.DescribeDBClusterSnapshotsOutput, error) {
+			return &rds.DescribeDBClusterSnapshotsOutput{
+				DBClusterSnapshots: []*rds.DBClusterSnapshot{
+					fakeRDSClientDBClusterSnapshot(),
+				},
+			}, nil
+		},
+		DeleteDBClusterSnapshotWithContextFunc: func(ctx context.Context, in1 *rds.DeleteDBClusterSnapshotInput, opts ...request.Option) (*rds.DeleteDBClusterSnapshotOutput, error) {
+			return &rds.DeleteDBClusterSnapshotOutput{}, nil
+		},
+		DescribeDBInstanceAutomatedBackupsWithContextFunc: func(ctx context.Context, in1 *rds.DescribeDBInstanceAutomatedBackupsInput, opts ...request.Option) (*rds.DescribeDBInstanceAutomatedBackupsOutput, error) {
+			return &rds.DescribeDBInstanceAutomatedBackupsOutput{
+				DBInstanceAutomatedBackups: []*rds.DBInstanceAutomatedBackup{
+					fakeRDSClientAutomatedBackup(),
+				},
+			}, nil
+		},
+		DeleteDBInstanceAutomatedBackupWithContextFunc: func(ctx context.Context, in1 *rds.DeleteDBInstanceAutomatedBackupInput, opts ...request.Option) (*rds.DeleteDBInstanceAutomatedBackupOutput, error) {
+			return &rds.DeleteDBInstanceAutomatedBackupOutput{}, nil
+		},
+		DescribeDBSubnetGroupsWithContextFunc: func(ctx context.Context, in1 *rds.DescribeDBSubnetGroupsInput, opts ...request.Option) (*rds.DescribeDBSubnetGroupsOutput, error) {
 			return &rds.DescribeDBSubnetGroupsOutput{
 				DBSubnetGroups: []*rds.DBSubnetGroup{
 					fakeRDSSubnetGroup(),
@@ -176,6 +284,12 @@ func fakeRDSClient(modifyFn func(c *rdsClientMock) error) (*rdsClientMock, error
 			}, nil
 		},
 	}
+	//the paginators pass what the mocked requests return as the only page, tests mock the requests
+	client.DescribeDBInstancesPagesWithContextFunc = singlePage(client.DescribeDBInstancesWithContext)
+	client.DescribeDBSnapshotsPagesWithContextFunc = singlePage(client.DescribeDBSnapshotsWithContext)
+	client.DescribeDBClustersPagesWithContextFunc = singlePage(client.DescribeDBClustersWithContext)
+	client.DescribeDBClusterSnapshotsPagesWithContextFunc = singlePage(client.DescribeDBClusterSnapshotsWithContext)
+	client.DescribeDBInstanceAutomatedBackupsPagesWithContextFunc = singlePage(client.DescribeDBInstanceAutomatedBackupsWithContext)
 	if err := modifyFn(client); err != nil {
 		return nil, errorModifyFailed(err)
 	}
@@ -189,6 +303,7 @@ type mockEc2Client struct {
 	deleteSubnetFn               func(*ec2.DeleteSubnetInput) (*ec2.DeleteSubnetOutput, error)
 	deleteSecurityGroupFn        func(*ec2.DeleteSecurityGroupInput) (*ec2.DeleteSecurityGroupOutput, error)
 	deleteRouteTableFn           func(*ec2.DeleteRouteTableInput) (*ec2.DeleteRouteTableOutput, error)
+	describeRegionsFn            func(*ec2.DescribeRegionsInput) (*ec2.DescribeRegionsOutput, error)
 }
 
 func buildMockEc2Client(modifyFn func(*mockEc2Client)) *mockEc2Client {
@@ -199,34 +314,65 @@ func buildMockEc2Client(modifyFn func(*mockEc2Client)) *mockEc2Client {
 	return mock
 }
 
-func (m *mockEc2Client) DeleteVpc(input *ec2.DeleteVpcInput) (*ec2.DeleteVpcOutput, error) {
+func (m *mockEc2Client) DeleteVpcWithContext(ctx context.Context, input *ec2.DeleteVpcInput, opts ...request.Option) (*ec2.DeleteVpcOutput, error) {
 	return m.deleteVpcFn(input)
 }
 
-func (m *mockEc2Client) DeleteVpcPeeringConnection(input *ec2.DeleteVpcPeeringConnectionInput) (*ec2.DeleteVpcPeeringConnectionOutput, error) {
+func (m *mockEc2Client) DeleteVpcPeeringConnectionWithContext(ctx context.Context, input *ec2.DeleteVpcPeeringConnectionInput, opts ...request.Option) (*ec2.DeleteVpcPeeringConnectionOutput, error) {
 	return m.deleteVpcPeeringConnectionFn(input)
 }
 
-func (m *mockEc2Client) DeleteSubnet(input *ec2.DeleteSubnetInput) (*ec2.DeleteSubnetOutput, error) {
+func (m *mockEc2Client) DeleteSubnetWithContext(ctx context.Context, input *ec2.DeleteSubnetInput, opts ...request.Option) (*ec2.DeleteSubnetOutput, error) {
 	return m.deleteSubnetFn(input)
 }
 
-func (m *mockEc2Client) DeleteSecurityGroup(input *ec2.DeleteSecurityGroupInput) (*ec2.DeleteSecurityGroupOutput, error) {
+func (m *mockEc2Client) DeleteSecurityGroupWithContext(ctx context.Context, input *ec2.DeleteSecurityGroupInput, opts ...request.Option) (*ec2.DeleteSecurityGroupOutput, error) {
 	return m.deleteSecurityGroupFn(input)
 }
 
-func (m *mockEc2Client) DeleteRouteTable(input *ec2.DeleteRouteTableInput) (*ec2.DeleteRouteTableOutput, error) {
+func (m *mockEc2Client) DeleteRouteTableWithContext(ctx context.Context, input *ec2.DeleteRouteTableInput, opts ...request.Option) (*ec2.DeleteRouteTableOutput, error) {
 	return m.deleteRouteTableFn(input)
 }
 
+func (m *mockEc2Client) DescribeRegionsWithContext(ctx context.Context, input *ec2.DescribeRegionsInput, opts ...request.Option) (*ec2.DescribeRegionsOutput, error) {
+	return m.describeRegionsFn(input)
+}
+
+type mockSTSClient struct {
+	stsiface.STSAPI
+	getCallerIdentityFn func(*sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error)
+}
+
+func buildMockSTSClient(modifyFn func(*mockSTSClient)) *mockSTSClient {
+	mock := &mockSTSClient{
+		getCallerIdentityFn: func(input *sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
+			return &sts.GetCallerIdentityOutput{
+				Account: aws.String(fakeAccountId),
+				Arn:     aws.String(fakeCallerARN),
+			}, nil
+		},
+	}
+	if modifyFn != nil {
+		modifyFn(mock)
+	}
+	return mock
+}
+
+func (m *mockSTSClient) GetCallerIdentityWithContext(ctx context.Context, input *sts.GetCallerIdentityInput, opts ...request.Option) (*sts.GetCallerIdentityOutput, error) {
+	return m.getCallerIdentityFn(input)
+}
+
 func fakeS3Client(modifyFn func(c *s3ClientMock) error) (*s3ClientMock, error) {
 	if modifyFn == nil {
 		return nil, errorMustBeDefined("modifyFn")
 	}
 	client := &s3ClientMock{
-		DeleteBucketFunc: func(in1 *s3.DeleteBucketInput) (output *s3.DeleteBucketOutput, e error) {
+		DeleteBucketWithContextFunc: func(ctx context.Context, in1 *s3.DeleteBucketInput, opts ...request.Option) (output *s3.DeleteBucketOutput, e error) {
 			return &s3.DeleteBucketOutput{}, nil
 		},
+		ListBucketsWithContextFunc: func(ctx context.Context, in1 *s3.ListBucketsInput, opts ...request.Option) (*s3.ListBucketsOutput, error) {
+			return &s3.ListBucketsOutput{}, nil
+		},
 	}
 	if err := modifyFn(client); err != nil {
 		return nil, errorModifyFailed(err)
@@ -239,7 +385,7 @@ func fakeTaggingClient(modifyFn func(c *taggingClientMock) error) (*taggingClien
 		return nil, errorMustBeDefined("modifyFn")
 	}
 	client := &taggingClientMock{
-		GetResourcesFunc: func(in1 *resourcegroupstaggingapi.GetResourcesInput) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
+		GetResourcesWithContextFunc: func(ctx context.Context, in1 *resourcegroupstaggingapi.GetResourcesInput, opts ...request.Option) (output *resourcegroupstaggingapi.GetResourcesOutput, e error) {
 			return &resourcegroupstaggingapi.GetResourcesOutput{
 				ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
 					fakeResourceTagMapping(func(mapping *resourcegroupstaggingapi.ResourceTagMapping) {}),
@@ -247,6 +393,8 @@ func fakeTaggingClient(modifyFn func(c *taggingClientMock) error) (*taggingClien
 			}, nil
 		},
 	}
+	//the paginator passes what the mocked request returns as the only page, tests mock the request
+	client.GetResourcesPagesWithContextFunc = singlePage(client.GetResourcesWithContext)
 	if err := modifyFn(client); err != nil {
 		return nil, fmt.Errorf("error occurred in modify function: %w", err)
 	}
@@ -303,38 +451,42 @@ func fakeElasticacheClient(modifyFn func(c *elasticacheClientMock) error) (*elas
 		return nil, fmt.Errorf("modifyFn must be defined")
 	}
 	client := &elasticacheClientMock{
-		DescribeReplicationGroupsFunc: func(in1 *elasticache.DescribeReplicationGroupsInput) (output *elasticache.DescribeReplicationGroupsOutput, e error) {
+		DescribeReplicationGroupsWithContextFunc: func(ctx context.Context, in1 *elasticache.DescribeReplicationGroupsInput, opts ...request.Option) (output *elasticache.DescribeReplicationGroupsOutput, e error) {
 			return &elasticache.DescribeReplicationGroupsOutput{
 				ReplicationGroups: []*elasticache.ReplicationGroup{
 					fakeElasticacheReplicationGroup(),
 				}}, nil
 		},
-		DescribeSnapshotsFunc: func(in1 *elasticache.DescribeSnapshotsInput) (output *elasticache.DescribeSnapshotsOutput, e error) {
+		DescribeSnapshotsWithContextFunc: func(ctx context.Context, in1 *elasticache.DescribeSnapshotsInput, opts ...request.Option) (output *elasticache.DescribeSnapshotsOutput, e error) {
 			return &elasticache.DescribeSnapshotsOutput{
 				Snapshots: []*elasticache.Snapshot{
 					fakeElasticacheSnapshot(),
 				}}, nil
 		},
-		DescribeCacheClustersFunc: func(in1 *elasticache.DescribeCacheClustersInput) (output *elasticache.DescribeCacheClustersOutput, e error) {
+		DescribeCacheClustersWithContextFunc: func(ctx context.Context, in1 *elasticache.DescribeCacheClustersInput, opts ...request.Option) (output *elasticache.DescribeCacheClustersOutput, e error) {
 			return &elasticache.DescribeCacheClustersOutput{
 				CacheClusters: []*elasticache.CacheCluster{
 					fakeElasticacheCacheCluster(),
 				}}, nil
 		},
-		DeleteReplicationGroupFunc: func(in1 *elasticache.DeleteReplicationGroupInput) (output *elasticache.DeleteReplicationGroupOutput, e error) {
+		DeleteReplicationGroupWithContextFunc: func(ctx context.Context, in1 *elasticache.DeleteReplicationGroupInput, opts ...request.Option) (output *elasticache.DeleteReplicationGroupOutput, e error) {
 			return &elasticache.DeleteReplicationGroupOutput{
 				ReplicationGroup: fakeElasticacheReplicationGroup(),
 			}, nil
 		},
-		DeleteSnapshotFunc: func(in1 *elasticache.DeleteSnapshotInput) (output *elasticache.DeleteSnapshotOutput, e error) {
+		DeleteSnapshotWithContextFunc: func(ctx context.Context, in1 *elasticache.DeleteSnapshotInput, opts ...request.Option) (output *elasticache.DeleteSnapshotOutput, e error) {
 			return &elasticache.DeleteSnapshotOutput{
 				Snapshot: fakeElasticacheSnapshot(),
 			}, nil
 		},
-		DeleteCacheSubnetGroupFunc: func(in1 *elasticache.DeleteCacheSubnetGroupInput) (out *elasticache.DeleteCacheSubnetGroupOutput, err error) {
+		DeleteCacheSubnetGroupWithContextFunc: func(ctx context.Context, in1 *elasticache.DeleteCacheSubnetGroupInput, opts ...request.Option) (out *elasticache.DeleteCacheSubnetGroupOutput, err error) {
 			return &elasticache.DeleteCacheSubnetGroupOutput{}, nil
 		},
 	}
+	//the paginators pass what the mocked requests return as the only page, tests mock the requests
+	client.DescribeCacheClustersPagesWithContextFunc = singlePage(client.DescribeCacheClustersWithContext)
+	client.DescribeReplicationGroupsPagesWithContextFunc = singlePage(client.DescribeReplicationGroupsWithContext)
+	client.DescribeSnapshotsPagesWithContextFunc = singlePage(client.DescribeSnapshotsWithContext)
 	if err := modifyFn(client); err != nil {
 		return nil, fmt.Errorf("error occurred in modify function: %w", err)
 	}
@@ -357,7 +509,7 @@ func fakeClusterManager(modifyFn func(e *ClusterResourceManagerMock) error) (*Cl
 		return nil, errorMustBeDefined("modifyFn")
 	}
 	clusterManager := &ClusterResourceManagerMock{
-		DeleteResourcesForClusterFunc: func(clusterId string, tags map[string]string, dryRun bool) (items []*clusterservice.ReportItem, e error) {
+		DeleteResourcesForClusterFunc: func(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) (items []*clusterservice.ReportItem, e error) {
 			return []*clusterservice.ReportItem{
 				mockReportItem(func(item *clusterservice.ReportItem) {
 					item.ID = fakeARN
@@ -370,6 +522,14 @@ func fakeClusterManager(modifyFn func(e *ClusterResourceManagerMock) error) (*Cl
 		GetNameFunc: func() string {
 			return fakeResourceManagerName
 		},
+		ListResourcesForClusterFunc: func(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error) {
+			return []*clusterservice.Resource{
+				{
+					ID:   fakeARN,
+					Name: fakeResourceIdentifier,
+				},
+			}, nil
+		},
 	}
 	if err := modifyFn(clusterManager); err != nil {
 		return nil, errorModifyFailed(err)
@@ -377,6 +537,77 @@ func fakeClusterManager(modifyFn func(e *ClusterResourceManagerMock) error) (*Cl
 	return clusterManager, nil
 }
 
+//fakePhasedManager Cluster resource manager mock declaring a manager type and dependencies
+type fakePhasedManager struct {
+	*ClusterResourceManagerMock
+	managerType  ResourceManagerType
+	dependencies []ResourceManagerType
+}
+
+func (m *fakePhasedManager) GetType() ResourceManagerType {
+	return m.managerType
+}
+
+func (m *fakePhasedManager) GetDependencies() []ResourceManagerType {
+	return m.dependencies
+}
+
+func fakePhasedClusterManager(managerType ResourceManagerType, dependencies []ResourceManagerType, modifyFn func(e *ClusterResourceManagerMock) error) (*fakePhasedManager, error) {
+	clusterManager, err := fakeClusterManager(modifyFn)
+	if err != nil {
+		return nil, err
+	}
+	return &fakePhasedManager{
+		ClusterResourceManagerMock: clusterManager,
+		managerType:                managerType,
+		dependencies:               dependencies,
+	}, nil
+}
+
+//fakeConfigurableManager Cluster resource manager mock accepting settings from the client
+type fakeConfigurableManager struct {
+	*ClusterResourceManagerMock
+	configurable
+}
+
+//fakeStatefulManager Phased cluster resource manager mock remembering pending resources
+//fakeTypedConfigurableManager Configurable cluster resource manager mock declaring a manager type
+type fakeTypedConfigurableManager struct {
+	*fakePhasedManager
+	configurable
+}
+
+type fakeStatefulManager struct {
+	*fakePhasedManager
+	pending []string
+}
+
+func (m *fakeStatefulManager) GetPendingResources() []string {
+	return m.pending
+}
+
+func (m *fakeStatefulManager) RestorePendingResources(pending []string) {
+	for _, resource := range pending {
+		m.pending = appendIfUnique(m.pending, resource)
+	}
+}
+
+//fakeStateStore In memory state store counting the number of saves
+type fakeStateStore struct {
+	state *clusterservice.State
+	saves int
+}
+
+func (f *fakeStateStore) Load(ctx context.Context) (*clusterservice.State, error) {
+	return f.state, nil
+}
+
+func (f *fakeStateStore) Save(ctx context.Context, state *clusterservice.State) error {
+	f.saves++
+	f.state = state
+	return nil
+}
+
 func errorMustBeDefined(varName string) error {
 	return fmt.Errorf("%s must be defined", varName)
 }
@@ -1,6 +1,9 @@
 package aws
 
 import (
+	"context"
+	"time"
+
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/elasticache/elasticacheiface"
 	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
@@ -16,33 +19,125 @@ const (
 	tagKeyClusterId = "integreatly.org/clusterID"
 	statusDeleting  = "deleting"
 
-	managerRDS                 ResourceManagerType = "aws_rds"
-	managerS3                  ResourceManagerType = "aws_s3"
-	managerSubnet              ResourceManagerType = "aws_ec2_subnet"
-	managerVpc                 ResourceManagerType = "aws_ec2_vpc"
-	managerVpcPeering          ResourceManagerType = "aws_ec2_vpc_peering"
-	managerRDSSnapshot         ResourceManagerType = "aws_rds_snapshot"
-	managerElasticache         ResourceManagerType = "aws_elasticache"
-	managerElasticacheSnapshot ResourceManagerType = "aws_elasticache_snapshot"
-	managerSecurityGroup       ResourceManagerType = "aws_ec2_security_group"
-	managerRouteTable          ResourceManagerType = "aws_ec2_route_table"
+	managerRDS                      ResourceManagerType = "aws_rds"
+	managerRDSSubnetGroup           ResourceManagerType = "aws_rds_subnet_group"
+	managerRDSCluster               ResourceManagerType = "aws_rds_cluster"
+	managerRDSParameterGroup        ResourceManagerType = "aws_rds_parameter_group"
+	managerRDSClusterParameterGroup ResourceManagerType = "aws_rds_cluster_parameter_group"
+	managerRDSOptionGroup           ResourceManagerType = "aws_rds_option_group"
+	managerS3                       ResourceManagerType = "aws_s3"
+	managerSubnet                   ResourceManagerType = "aws_ec2_subnet"
+	managerVpc                      ResourceManagerType = "aws_ec2_vpc"
+	managerVpcPeering               ResourceManagerType = "aws_ec2_vpc_peering"
+	managerRDSSnapshot              ResourceManagerType = "aws_rds_snapshot"
+	managerRDSClusterSnapshot       ResourceManagerType = "aws_rds_cluster_snapshot"
+	managerRDSAutomatedBackup       ResourceManagerType = "aws_rds_automated_backup"
+	managerElasticache              ResourceManagerType = "aws_elasticache"
+	managerElasticacheSnapshot      ResourceManagerType = "aws_elasticache_snapshot"
+	managerSecurityGroup            ResourceManagerType = "aws_ec2_security_group"
+	managerRouteTable               ResourceManagerType = "aws_ec2_route_table"
 
 	loggingKeyClusterID = "cluster-id"
 	loggingKeyDryRun    = "dry-run"
 	loggingKeyManager   = "manager"
+	loggingKeyPhase     = "phase"
 )
 
 //go:generate moq -out moq_crm_test.go . ClusterResourceManager
 //ClusterResourceManager Perform actions for a specific resource
 type ClusterResourceManager interface {
 	GetName() string
-	DeleteResourcesForCluster(clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error)
+	DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) ([]*clusterservice.ReportItem, error)
+	ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) ([]*clusterservice.Resource, error)
+}
+
+//PhasedClusterResourceManager Resource manager that declares which managers must finish before it can run
+//Managers that don't implement this interface have no dependencies and run in the first phase
+type PhasedClusterResourceManager interface {
+	ClusterResourceManager
+	GetType() ResourceManagerType
+	GetDependencies() []ResourceManagerType
+}
+
+//StatefulClusterResourceManager Resource manager that remembers resources it can't discover again until they're deleted
+//The client persists these pending resources when a state store is configured, so they aren't leaked if the process stops
+type StatefulClusterResourceManager interface {
+	ClusterResourceManager
+	GetType() ResourceManagerType
+	//GetPendingResources Resources discovered by earlier runs which haven't been deleted yet
+	GetPendingResources() []string
+	//RestorePendingResources Add resources left pending by a previous process
+	RestorePendingResources(pending []string)
+}
+
+//ManagerOptions Settings provided by the client to every resource manager it runs
+type ManagerOptions struct {
+	//Concurrency Maximum number of items a manager performs actions on at the same time
+	Concurrency int
+	//Targets IDs of the only resources a manager may act on, every discovered resource when nil
+	Targets map[string]bool
+	//Policy Safety policy evaluated before every deletion, nothing is protected when nil
+	Policy *SafetyPolicy
+	//FinalSnapshot Snapshot data stores before deleting them, no snapshots are taken when nil
+	FinalSnapshot *FinalSnapshotPolicy
+}
+
+//ManagerSettings Client settings overridden for a single resource manager
+type ManagerSettings struct {
+	//Concurrency Maximum number of items the manager performs actions on at the same time, the client concurrency when zero
+	Concurrency int
+	//Timeout Deadline for a single run of the manager, the client manager timeout when zero
+	Timeout time.Duration
+}
+
+//ConfigurableClusterResourceManager Resource manager that accepts settings from the client running it
+type ConfigurableClusterResourceManager interface {
+	ClusterResourceManager
+	Configure(options ManagerOptions)
+}
+
+//configurable Provides ConfigurableClusterResourceManager to resource managers that embed it
+type configurable struct {
+	options ManagerOptions
+}
+
+func (c *configurable) Configure(options ManagerOptions) {
+	c.options = options
+}
+
+//isTarget Whether the manager may act on the resource with the provided id
+func (c *configurable) isTarget(id string) bool {
+	return c.options.Targets == nil || c.options.Targets[id]
+}
+
+//isProtected Whether the safety policy forbids deleting the resource of the report item, which is then marked protected
+func (c *configurable) isProtected(reportItem *clusterservice.ReportItem, creationTime *time.Time) bool {
+	protected, reason := c.options.Policy.Evaluate(reportItem, creationTime)
+	if protected {
+		reportItem.ActionStatus = clusterservice.ActionStatusProtected
+		reportItem.StatusReason = reason
+	}
+	return protected
+}
+
+//isProtectedWithoutCreationTime Whether the safety policy forbids deleting the resource of a report item whose type has no creation time
+//The item is marked protected, or records that the minimum age didn't apply to it
+func (c *configurable) isProtectedWithoutCreationTime(reportItem *clusterservice.ReportItem) bool {
+	protected, reason := c.options.Policy.EvaluateWithoutCreationTime(reportItem)
+	if protected {
+		reportItem.ActionStatus = clusterservice.ActionStatusProtected
+	}
+	if reason != "" {
+		reportItem.StatusReason = reason
+	}
+	return protected
 }
 
 //basicResource Representation of basic AWS resource information
 type basicResource struct {
 	Name string
 	ARN  string
+	Tags map[string]string
 }
 
 //go:generate moq -out moq_rdsclient_test.go . rdsClient
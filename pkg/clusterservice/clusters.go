@@ -0,0 +1,91 @@
+package clusterservice
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//ClusterSummary Footprint of a single cluster found in an account
+type ClusterSummary struct {
+	ClusterID string `json:"clusterId"`
+	//ResourceCount Total number of resources found for the cluster
+	ResourceCount int `json:"resourceCount"`
+	//ResourceCounts Number of resources found for the cluster keyed by resource type
+	ResourceCounts map[string]int `json:"resourceCounts"`
+	//OldestCreationTime Creation time of the oldest resource that exposes one
+	OldestCreationTime *time.Time `json:"oldestCreationTime,omitempty"`
+	//TotalSizeBytes Sum of the sizes of the resources that expose one
+	TotalSizeBytes int64 `json:"totalSizeBytes"`
+}
+
+//ClusterList Clusters found in an account, identified by the cluster id tag on their resources
+type ClusterList struct {
+	Clusters []*ClusterSummary `json:"clusters"`
+}
+
+//NewClusterSummary Summarise the inventory of a cluster
+func NewClusterSummary(clusterId string, inventory *Inventory) *ClusterSummary {
+	summary := &ClusterSummary{
+		ClusterID:      clusterId,
+		ResourceCounts: map[string]int{},
+	}
+	for _, resource := range inventory.Resources {
+		summary.ResourceCount++
+		summary.ResourceCounts[resource.ResourceType]++
+		if resource.CreationTime != nil && (summary.OldestCreationTime == nil || resource.CreationTime.Before(*summary.OldestCreationTime)) {
+			summary.OldestCreationTime = resource.CreationTime
+		}
+		if resource.SizeBytes != nil {
+			summary.TotalSizeBytes += *resource.SizeBytes
+		}
+	}
+	return summary
+}
+
+var _ Document = &ClusterListDocument{}
+
+//ClusterListDocument Renderable representation of the clusters found in an account
+type ClusterListDocument struct {
+	Clusters []*ClusterSummary `json:"clusters"`
+}
+
+//NewClusterListDocument Build a renderable document from a list of clusters
+func NewClusterListDocument(clusterList *ClusterList) *ClusterListDocument {
+	clusters := clusterList.Clusters
+	if clusters == nil {
+		clusters = []*ClusterSummary{}
+	}
+	return &ClusterListDocument{
+		Clusters: clusters,
+	}
+}
+
+func (c *ClusterListDocument) Columns() []string {
+	return []string{"Cluster ID", "Resources", "Types", "Oldest", "Size"}
+}
+
+func (c *ClusterListDocument) Rows() [][]string {
+	rows := make([][]string, 0, len(c.Clusters))
+	for _, cluster := range c.Clusters {
+		totalSize := cluster.TotalSizeBytes
+		rows = append(rows, []string{cluster.ClusterID, strconv.Itoa(cluster.ResourceCount), formatResourceCounts(cluster.ResourceCounts), formatTime(cluster.OldestCreationTime), formatSize(&totalSize)})
+	}
+	return rows
+}
+
+//formatResourceCounts Format resource counts as type=count pairs ordered by type
+func formatResourceCounts(resourceCounts map[string]int) string {
+	resourceTypes := make([]string, 0, len(resourceCounts))
+	for resourceType := range resourceCounts {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	sort.Strings(resourceTypes)
+	counts := make([]string, 0, len(resourceTypes))
+	for _, resourceType := range resourceTypes {
+		counts = append(counts, fmt.Sprintf("%s=%d", resourceType, resourceCounts[resourceType]))
+	}
+	return strings.Join(counts, " ")
+}
@@ -0,0 +1,77 @@
+package clusterservice
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewClusterSummary(t *testing.T) {
+	older := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	size := func(sizeBytes int64) *int64 {
+		return &sizeBytes
+	}
+	tests := []struct {
+		name      string
+		inventory *Inventory
+		want      *ClusterSummary
+	}{
+		{
+			name:      "empty inventory has no resources",
+			inventory: &Inventory{},
+			want: &ClusterSummary{
+				ClusterID:      "test",
+				ResourceCounts: map[string]int{},
+			},
+		},
+		{
+			name: "resources are counted per type with the oldest creation time and total size",
+			inventory: &Inventory{
+				Resources: []*Resource{
+					{ResourceType: "rds:db", CreationTime: &newer, SizeBytes: size(1024)},
+					{ResourceType: "rds:db", CreationTime: &older, SizeBytes: size(2048)},
+					{ResourceType: "ec2:vpc"},
+				},
+			},
+			want: &ClusterSummary{
+				ClusterID:          "test",
+				ResourceCount:      3,
+				ResourceCounts:     map[string]int{"rds:db": 2, "ec2:vpc": 1},
+				OldestCreationTime: &older,
+				TotalSizeBytes:     3072,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewClusterSummary("test", tt.inventory); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewClusterSummary() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClusterListDocument_Rows(t *testing.T) {
+	oldest := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	doc := NewClusterListDocument(&ClusterList{
+		Clusters: []*ClusterSummary{
+			{
+				ClusterID:          "test",
+				ResourceCount:      3,
+				ResourceCounts:     map[string]int{"s3": 1, "ec2:vpc": 2},
+				OldestCreationTime: &oldest,
+				TotalSizeBytes:     2048,
+			},
+		},
+	})
+	want := [][]string{
+		{"test", "3", "ec2:vpc=2 s3=1", "2020-01-01T00:00:00Z", "2.0 KiB"},
+	}
+	if got := doc.Rows(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Rows() got = %v, want %v", got, want)
+	}
+	if got := NewClusterListDocument(&ClusterList{}).Clusters; got == nil {
+		t.Error("NewClusterListDocument() clusters should be an empty list when there are no clusters")
+	}
+}
@@ -1,7 +1,15 @@
 package clusterservice
 
+import "context"
+
 //Client Client for handling extra resources cleanup for a cluster
 type Client interface {
 	//DeleteResources delete resources belonging to a cluster based on filters from additional tags
-	DeleteResourcesForCluster(clusterId string, tags map[string]string, dryRun bool) (*Report, error)
+	DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) (*Report, error)
+	//ListResourcesForCluster list resources belonging to a cluster based on filters from additional tags, without modifying them
+	ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) (*Inventory, error)
+	//ListClusters list every cluster with resources in the account, along with a summary of their resources
+	ListClusters(ctx context.Context) (*ClusterList, error)
+	//ApplyPlan delete only the resources recorded in a plan, refusing resources which changed since the plan was made
+	ApplyPlan(ctx context.Context, plan *Plan) (*Report, error)
 }
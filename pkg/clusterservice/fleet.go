@@ -0,0 +1,245 @@
+package clusterservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	clusterserviceerrors "github.com/integr8ly/cluster-service/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	//DefaultFleetConcurrency Number of accounts cleaned up at the same time when a fleet doesn't set it
+	DefaultFleetConcurrency = 4
+)
+
+//Fleet Accounts cleaned up together, read from a yaml or json file
+type Fleet struct {
+	//Concurrency Number of accounts cleaned up at the same time
+	Concurrency int `json:"concurrency,omitempty"`
+	//LiveClusters File or http(s) url listing the clusters that still exist, required when an account discovers orphans
+	LiveClusters string          `json:"liveClusters,omitempty"`
+	Accounts     []*FleetAccount `json:"accounts"`
+}
+
+//FleetAccount Account in a fleet along with the clusters to clean up in it
+type FleetAccount struct {
+	//Name Unique name of the account, used to name its report
+	Name string `json:"name"`
+	//AccountID Account the credentials must belong to, not verified when empty
+	AccountID string `json:"accountId,omitempty"`
+	//RoleARN Role assumed to reach the account, the credentials are used directly when empty
+	RoleARN    string   `json:"roleArn,omitempty"`
+	ExternalID string   `json:"externalId,omitempty"`
+	Regions    []string `json:"regions"`
+	//ClusterIDs Clusters to clean up in the account
+	ClusterIDs []string `json:"clusterIds,omitempty"`
+	//DiscoverOrphans Clean up every cluster in the account which is not in the live clusters, instead of listing cluster ids
+	DiscoverOrphans bool `json:"discoverOrphans,omitempty"`
+}
+
+//LoadFleet Read a fleet from a yaml or json file, unknown fields and invalid accounts are refused
+func LoadFleet(path string) (*Fleet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fleet file %s: %w", path, err)
+	}
+	fleet := &Fleet{}
+	if err := yaml.UnmarshalStrict(data, fleet); err != nil {
+		return nil, fmt.Errorf("failed to parse fleet file %s: %w", path, err)
+	}
+	if err := fleet.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid fleet file %s: %w", path, err)
+	}
+	return fleet, nil
+}
+
+//Validate Check every account names its regions and either lists clusters or discovers orphans
+func (f *Fleet) Validate() error {
+	if len(f.Accounts) == 0 {
+		return errors.New("no accounts found")
+	}
+	if f.Concurrency < 0 {
+		return errors.New("concurrency must not be negative")
+	}
+	names := map[string]bool{}
+	for i, account := range f.Accounts {
+		if account.Name == "" {
+			return fmt.Errorf("account %d has no name", i)
+		}
+		//the name is used to name the report file of the account
+		if strings.ContainsAny(account.Name, `/\`) {
+			return fmt.Errorf("account name %s must not contain path separators", account.Name)
+		}
+		if names[account.Name] {
+			return fmt.Errorf("account name %s is used more than once", account.Name)
+		}
+		names[account.Name] = true
+		if len(account.Regions) == 0 {
+			return fmt.Errorf("account %s has no regions", account.Name)
+		}
+		if account.DiscoverOrphans == (len(account.ClusterIDs) > 0) {
+			return fmt.Errorf("account %s must either list cluster ids or discover orphans", account.Name)
+		}
+		if account.DiscoverOrphans && f.LiveClusters == "" {
+			return fmt.Errorf("account %s discovers orphans, which requires live clusters to be set", account.Name)
+		}
+	}
+	return nil
+}
+
+//FleetAccountReport Result of cleaning up a single account of a fleet
+type FleetAccountReport struct {
+	Account string  `json:"account"`
+	Report  *Report `json:"report"`
+	//Error Failure which stopped the cleanup of the account, failures of single resources are only recorded in the report
+	Error string `json:"error,omitempty"`
+}
+
+//FleetAccountReportDocument Report of a single account of a fleet, including a summary of the item statuses
+type FleetAccountReportDocument struct {
+	Account string `json:"account"`
+	Error   string `json:"error,omitempty"`
+	*ReportDocument
+}
+
+//NewFleetAccountReportDocument Build a document from the report of an account
+func NewFleetAccountReportDocument(accountReport *FleetAccountReport) *FleetAccountReportDocument {
+	return &FleetAccountReportDocument{
+		Account:        accountReport.Account,
+		Error:          accountReport.Error,
+		ReportDocument: NewReportDocument(accountReport.Report),
+	}
+}
+
+//FleetReport Results of cleaning up every account of a fleet, in the order of the fleet file
+type FleetReport struct {
+	Accounts []*FleetAccountReport `json:"accounts"`
+}
+
+//Combined Combine the reports of every account into a single report
+func (f *FleetReport) Combined() *Report {
+	report := &Report{}
+	for _, accountReport := range f.Accounts {
+		report.Items = append(report.Items, accountReport.Report.Items...)
+	}
+	return report
+}
+
+//FailedAccounts Accounts whose cleanup stopped with an error
+func (f *FleetReport) FailedAccounts() []*FleetAccountReport {
+	var failedAccounts []*FleetAccountReport
+	for _, accountReport := range f.Accounts {
+		if accountReport.Error != "" {
+			failedAccounts = append(failedAccounts, accountReport)
+		}
+	}
+	return failedAccounts
+}
+
+//FleetClientFunc Build the client cleaning up an account of a fleet
+type FleetClientFunc func(ctx context.Context, account *FleetAccount) (Client, error)
+
+//FleetReconcilerFunc Build the reconciler driving a cluster of an account until its cleanup is complete
+type FleetReconcilerFunc func(client Client, clusterId string, dryRun bool) *Reconciler
+
+//CleanupFleet Clean up every account of a fleet, running up to the concurrency of the fleet at the same time
+//Each cluster is reconciled until its cleanup is complete when a reconciler func is provided, otherwise it's cleaned up once
+//A failing account never stops the cleanup of the other accounts, its error is recorded in its report
+func CleanupFleet(ctx context.Context, fleet *Fleet, clientFn FleetClientFunc, reconcilerFn FleetReconcilerFunc, liveClusterIds []string, dryRun bool, continueOnError bool) *FleetReport {
+	concurrency := fleet.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultFleetConcurrency
+	}
+	fleetReport := &FleetReport{
+		Accounts: make([]*FleetAccountReport, len(fleet.Accounts)),
+	}
+	slots := make(chan struct{}, concurrency)
+	wg := sync.WaitGroup{}
+	for i, account := range fleet.Accounts {
+		wg.Add(1)
+		go func(i int, account *FleetAccount) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			report, err := cleanupFleetAccount(ctx, account, clientFn, reconcilerFn, liveClusterIds, dryRun, continueOnError)
+			accountReport := &FleetAccountReport{
+				Account: account.Name,
+				Report:  report,
+			}
+			if err != nil {
+				accountReport.Error = err.Error()
+			}
+			//every account writes to its own index, so no lock is needed
+			fleetReport.Accounts[i] = accountReport
+		}(i, account)
+	}
+	wg.Wait()
+	return fleetReport
+}
+
+//cleanupFleetAccount Clean up the listed clusters of an account, or its orphaned clusters, the report is never nil
+func cleanupFleetAccount(ctx context.Context, account *FleetAccount, clientFn FleetClientFunc, reconcilerFn FleetReconcilerFunc, liveClusterIds []string, dryRun bool, continueOnError bool) (*Report, error) {
+	if ctx.Err() != nil {
+		return &Report{}, fmt.Errorf("cleanup of account %s interrupted: %w", account.Name, ctx.Err())
+	}
+	client, err := clientFn(ctx, account)
+	if err != nil {
+		return &Report{}, fmt.Errorf("failed to build client for account %s: %w", account.Name, err)
+	}
+	clusters := &ClusterList{}
+	if account.DiscoverOrphans {
+		clusterList, err := client.ListClusters(ctx)
+		if err != nil {
+			return &Report{}, fmt.Errorf("failed to list clusters of account %s: %w", account.Name, err)
+		}
+		clusters, err = FindOrphanedClusters(clusterList, liveClusterIds)
+		if err != nil {
+			return &Report{}, err
+		}
+	} else {
+		for _, clusterId := range account.ClusterIDs {
+			clusters.Clusters = append(clusters.Clusters, &ClusterSummary{ClusterID: clusterId})
+		}
+	}
+	var report *Report
+	if reconcilerFn != nil {
+		report, err = reconcileClusters(ctx, client, clusters, reconcilerFn, dryRun, continueOnError)
+	} else {
+		report, err = DeleteClusters(ctx, client, clusters, dryRun, continueOnError)
+	}
+	if err != nil {
+		return report, fmt.Errorf("failed to cleanup account %s: %w", account.Name, err)
+	}
+	return report, nil
+}
+
+//reconcileClusters Reconcile every cluster in the list until its cleanup is complete, one cluster after another
+func reconcileClusters(ctx context.Context, client Client, clusters *ClusterList, reconcilerFn FleetReconcilerFunc, dryRun bool, continueOnError bool) (*Report, error) {
+	report := &Report{}
+	var reconcileErrors []error
+	for _, cluster := range clusters.Clusters {
+		if ctx.Err() != nil {
+			reconcileErrors = append(reconcileErrors, fmt.Errorf("cleanup of clusters interrupted: %w", ctx.Err()))
+			break
+		}
+		clusterReport, err := reconcilerFn(client, cluster.ClusterID, dryRun).Reconcile(ctx)
+		if clusterReport != nil {
+			if clusterReport.Identity != nil {
+				report.Identity = clusterReport.Identity
+			}
+			report.Items = append(report.Items, clusterReport.Items...)
+		}
+		if err != nil {
+			if !continueOnError {
+				return report, err
+			}
+			reconcileErrors = append(reconcileErrors, err)
+		}
+	}
+	return report, clusterserviceerrors.Aggregate(reconcileErrors)
+}
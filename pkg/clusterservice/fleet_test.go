@@ -0,0 +1,248 @@
+package clusterservice
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLoadFleet(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    *Fleet
+		wantErr string
+	}{
+		{
+			name: "yaml fleet is loaded",
+			data: `
+concurrency: 2
+liveClusters: clusters.json
+accounts:
+- name: customer-a
+  accountId: "123456789012"
+  roleArn: arn:aws:iam::123456789012:role/cleanup
+  externalId: external
+  regions: [eu-west-1, us-east-1]
+  clusterIds: [cluster-a]
+- name: customer-b
+  regions: [eu-west-1]
+  discoverOrphans: true
+`,
+			want: &Fleet{
+				Concurrency:  2,
+				LiveClusters: "clusters.json",
+				Accounts: []*FleetAccount{
+					{
+						Name:       "customer-a",
+						AccountID:  "123456789012",
+						RoleARN:    "arn:aws:iam::123456789012:role/cleanup",
+						ExternalID: "external",
+						Regions:    []string{"eu-west-1", "us-east-1"},
+						ClusterIDs: []string{"cluster-a"},
+					},
+					{
+						Name:            "customer-b",
+						Regions:         []string{"eu-west-1"},
+						DiscoverOrphans: true,
+					},
+				},
+			},
+		},
+		{
+			name: "json fleet is loaded",
+			data: `{"accounts": [{"name": "customer-a", "regions": ["eu-west-1"], "clusterIds": ["cluster-a"]}]}`,
+			want: &Fleet{
+				Accounts: []*FleetAccount{
+					{Name: "customer-a", Regions: []string{"eu-west-1"}, ClusterIDs: []string{"cluster-a"}},
+				},
+			},
+		},
+		{
+			name:    "error on unknown fields",
+			data:    `{"accounts": [{"name": "customer-a", "region": "eu-west-1", "clusterIds": ["cluster-a"]}]}`,
+			wantErr: "failed to parse fleet file",
+		},
+		{
+			name:    "error when an account neither lists clusters nor discovers orphans",
+			data:    `{"accounts": [{"name": "customer-a", "regions": ["eu-west-1"]}]}`,
+			wantErr: "account customer-a must either list cluster ids or discover orphans",
+		},
+		{
+			name:    "error when discovering orphans without live clusters",
+			data:    `{"accounts": [{"name": "customer-a", "regions": ["eu-west-1"], "discoverOrphans": true}]}`,
+			wantErr: "account customer-a discovers orphans, which requires live clusters to be set",
+		},
+		{
+			name:    "error when an account has no regions",
+			data:    `{"accounts": [{"name": "customer-a", "clusterIds": ["cluster-a"]}]}`,
+			wantErr: "account customer-a has no regions",
+		},
+		{
+			name:    "error when account names are used twice",
+			data:    `{"accounts": [{"name": "a", "regions": ["eu-west-1"], "clusterIds": ["x"]}, {"name": "a", "regions": ["eu-west-1"], "clusterIds": ["y"]}]}`,
+			wantErr: "account name a is used more than once",
+		},
+		{
+			name:    "error when an account name is a path",
+			data:    `{"accounts": [{"name": "../a", "regions": ["eu-west-1"], "clusterIds": ["x"]}]}`,
+			wantErr: "account name ../a must not contain path separators",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "fleet.yaml")
+			if err := os.WriteFile(path, []byte(tt.data), 0600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := LoadFleet(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("LoadFleet() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadFleet() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadFleet() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanupFleet(t *testing.T) {
+	deletingClient := func(account string) Client {
+		return &fakeClient{
+			deleteFn: func(clusterId string, dryRun bool) (*Report, error) {
+				return &Report{Items: []*ReportItem{{ID: account + ":" + clusterId, ActionStatus: ActionStatusComplete}}}, nil
+			},
+			listClustersFn: func() (*ClusterList, error) {
+				return fakeClusterList("live", "orphan"), nil
+			},
+		}
+	}
+	fleet := &Fleet{
+		Accounts: []*FleetAccount{
+			{Name: "listed", Regions: []string{"eu-west-1"}, ClusterIDs: []string{"a", "b"}},
+			{Name: "orphans", Regions: []string{"eu-west-1"}, DiscoverOrphans: true},
+			{Name: "unreachable", Regions: []string{"eu-west-1"}, ClusterIDs: []string{"c"}},
+		},
+	}
+	clientFn := func(ctx context.Context, account *FleetAccount) (Client, error) {
+		if account.Name == "unreachable" {
+			return nil, errors.New("access denied")
+		}
+		return deletingClient(account.Name), nil
+	}
+	got := CleanupFleet(context.TODO(), fleet, clientFn, nil, []string{"live"}, false, false)
+	want := &FleetReport{
+		Accounts: []*FleetAccountReport{
+			{
+				Account: "listed",
+				Report: &Report{Items: []*ReportItem{
+					{ID: "listed:a", ActionStatus: ActionStatusComplete},
+					{ID: "listed:b", ActionStatus: ActionStatusComplete},
+				}},
+			},
+			{
+				Account: "orphans",
+				Report: &Report{Items: []*ReportItem{
+					{ID: "orphans:orphan", ActionStatus: ActionStatusComplete},
+				}},
+			},
+			{
+				Account: "unreachable",
+				Report:  &Report{},
+				Error:   "failed to build client for account unreachable: access denied",
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CleanupFleet() got = %+v, want %+v", got, want)
+	}
+	if failed := got.FailedAccounts(); len(failed) != 1 || failed[0].Account != "unreachable" {
+		t.Errorf("FailedAccounts() got = %+v, want the unreachable account", failed)
+	}
+	if items := got.Combined().Items; len(items) != 3 {
+		t.Errorf("Combined() got %d items, want 3", len(items))
+	}
+}
+
+func TestCleanupFleet_Reconcile(t *testing.T) {
+	fleet := &Fleet{
+		Accounts: []*FleetAccount{
+			{Name: "account", Regions: []string{"eu-west-1"}, ClusterIDs: []string{"a", "b"}},
+		},
+	}
+	clientFn := func(ctx context.Context, account *FleetAccount) (Client, error) {
+		runs := map[string]int{}
+		return &fakeClient{deleteFn: func(clusterId string, dryRun bool) (*Report, error) {
+			runs[clusterId]++
+			//the first cleanup of each cluster leaves its resource in progress, the next one finds it gone
+			if runs[clusterId] > 1 {
+				return &Report{}, nil
+			}
+			return &Report{Items: []*ReportItem{{ID: clusterId, ActionStatus: ActionStatusInProgress}}}, nil
+		}}, nil
+	}
+	reconcilerFn := func(client Client, clusterId string, dryRun bool) *Reconciler {
+		reconciler := NewReconciler(client, clusterId, dryRun)
+		reconciler.Interval = time.Millisecond
+		reconciler.Jitter = 0
+		return reconciler
+	}
+	got := CleanupFleet(context.TODO(), fleet, clientFn, reconcilerFn, nil, false, false)
+	want := &FleetReport{
+		Accounts: []*FleetAccountReport{
+			{
+				Account: "account",
+				Report: &Report{Items: []*ReportItem{
+					{ID: "a", ActionStatus: ActionStatusComplete},
+					{ID: "b", ActionStatus: ActionStatusComplete},
+				}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CleanupFleet() got = %+v, want %+v", got, want)
+	}
+}
+
+func TestCleanupFleet_Concurrency(t *testing.T) {
+	fleet := &Fleet{Concurrency: 2}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		fleet.Accounts = append(fleet.Accounts, &FleetAccount{Name: name, Regions: []string{"eu-west-1"}, ClusterIDs: []string{"cluster"}})
+	}
+	mu := sync.Mutex{}
+	running, maxRunning := 0, 0
+	clientFn := func(ctx context.Context, account *FleetAccount) (Client, error) {
+		return &fakeClient{deleteFn: func(clusterId string, dryRun bool) (*Report, error) {
+			mu.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return &Report{}, nil
+		}}, nil
+	}
+	got := CleanupFleet(context.TODO(), fleet, clientFn, nil, nil, true, false)
+	if len(got.Accounts) != 5 {
+		t.Fatalf("CleanupFleet() got %d account reports, want 5", len(got.Accounts))
+	}
+	if maxRunning > 2 {
+		t.Errorf("CleanupFleet() ran %d accounts at the same time, want at most 2", maxRunning)
+	}
+}
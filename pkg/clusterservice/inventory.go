@@ -0,0 +1,86 @@
+package clusterservice
+
+import (
+	"fmt"
+	"time"
+)
+
+//Resource Read-only description of a resource belonging to a cluster
+type Resource struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	//ResourceType Provider specific type of the resource, e.g. ec2:vpc
+	ResourceType string `json:"resourceType"`
+	Region       string `json:"region"`
+	Account      string `json:"account"`
+	//Manager Name of the resource manager that found the resource
+	Manager string            `json:"manager"`
+	Tags    map[string]string `json:"tags,omitempty"`
+	//State Provider specific state of the resource, e.g. available
+	State string `json:"state,omitempty"`
+	//CreationTime Time the resource was created, when the provider exposes it
+	CreationTime *time.Time `json:"creationTime,omitempty"`
+	//SizeBytes Allocated size of the resource, when the provider exposes it
+	SizeBytes *int64 `json:"sizeBytes,omitempty"`
+}
+
+//Inventory Resources found for a cluster, without performing any actions on them
+type Inventory struct {
+	Resources []*Resource `json:"resources"`
+}
+
+var _ Document = &InventoryDocument{}
+
+//InventoryDocument Renderable representation of an inventory
+type InventoryDocument struct {
+	Resources []*Resource `json:"resources"`
+}
+
+//NewInventoryDocument Build a renderable document from an inventory
+func NewInventoryDocument(inventory *Inventory) *InventoryDocument {
+	resources := inventory.Resources
+	if resources == nil {
+		resources = []*Resource{}
+	}
+	return &InventoryDocument{
+		Resources: resources,
+	}
+}
+
+func (i *InventoryDocument) Columns() []string {
+	return []string{"ID", "Name", "Type", "Region", "Account", "State", "Created", "Size"}
+}
+
+func (i *InventoryDocument) Rows() [][]string {
+	rows := make([][]string, 0, len(i.Resources))
+	for _, resource := range i.Resources {
+		rows = append(rows, []string{resource.ID, resource.Name, resource.ResourceType, resource.Region, resource.Account, resource.State, formatTime(resource.CreationTime), formatSize(resource.SizeBytes)})
+	}
+	return rows
+}
+
+//formatTime Format an optional time for tabular output
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+//formatSize Format an optional size in bytes for tabular output, using binary units
+func formatSize(sizeBytes *int64) string {
+	if sizeBytes == nil {
+		return ""
+	}
+	size := float64(*sizeBytes)
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	unit := 0
+	for size >= 1024 && unit < len(units)-1 {
+		size /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%d B", *sizeBytes)
+	}
+	return fmt.Sprintf("%.1f %s", size, units[unit])
+}
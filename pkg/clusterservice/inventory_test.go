@@ -0,0 +1,144 @@
+package clusterservice
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func fakeInventory() *Inventory {
+	creationTime := time.Date(2021, 3, 4, 10, 30, 0, 0, time.UTC)
+	sizeBytes := int64(20 * 1024 * 1024 * 1024)
+	return &Inventory{
+		Resources: []*Resource{
+			{
+				ID:           "arn:aws:rds:eu-west-1:123456789012:db:test",
+				Name:         "test",
+				ResourceType: "rds:db",
+				Region:       "eu-west-1",
+				Account:      "123456789012",
+				Manager:      "aws_rds",
+				Tags:         map[string]string{"integreatly.org/clusterID": "test"},
+				State:        "available",
+				CreationTime: &creationTime,
+				SizeBytes:    &sizeBytes,
+			},
+			{
+				ID:           "arn:aws:ec2:eu-west-1:123456789012:vpc/vpc-1",
+				Name:         "vpc-1",
+				ResourceType: "ec2:vpc",
+				Region:       "eu-west-1",
+				Account:      "123456789012",
+				Manager:      "aws_ec2_vpc",
+			},
+		},
+	}
+}
+
+func TestInventoryDocument_Render(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    OutputFormat
+		inventory *Inventory
+		want      string
+	}{
+		{
+			name:      "json omits details that are not available",
+			format:    OutputFormatJSON,
+			inventory: fakeInventory(),
+			want: `{
+  "resources": [
+    {
+      "id": "arn:aws:rds:eu-west-1:123456789012:db:test",
+      "name": "test",
+      "resourceType": "rds:db",
+      "region": "eu-west-1",
+      "account": "123456789012",
+      "manager": "aws_rds",
+      "tags": {
+        "integreatly.org/clusterID": "test"
+      },
+      "state": "available",
+      "creationTime": "2021-03-04T10:30:00Z",
+      "sizeBytes": 21474836480
+    },
+    {
+      "id": "arn:aws:ec2:eu-west-1:123456789012:vpc/vpc-1",
+      "name": "vpc-1",
+      "resourceType": "ec2:vpc",
+      "region": "eu-west-1",
+      "account": "123456789012",
+      "manager": "aws_ec2_vpc"
+    }
+  ]
+}
+`,
+		},
+		{
+			name:      "json renders an empty list when there are no resources",
+			format:    OutputFormatJSON,
+			inventory: &Inventory{},
+			want: `{
+  "resources": []
+}
+`,
+		},
+		{
+			name:      "csv formats creation time and size",
+			format:    OutputFormatCSV,
+			inventory: fakeInventory(),
+			want: `ID,Name,Type,Region,Account,State,Created,Size
+arn:aws:rds:eu-west-1:123456789012:db:test,test,rds:db,eu-west-1,123456789012,available,2021-03-04T10:30:00Z,20.0 GiB
+arn:aws:ec2:eu-west-1:123456789012:vpc/vpc-1,vpc-1,ec2:vpc,eu-west-1,123456789012,,,
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer, err := NewRenderer(tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out := &bytes.Buffer{}
+			if err := renderer.Render(out, NewInventoryDocument(tt.inventory)); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("Render() got = %v, want %v", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	size := func(sizeBytes int64) *int64 {
+		return &sizeBytes
+	}
+	tests := []struct {
+		name      string
+		sizeBytes *int64
+		want      string
+	}{
+		{
+			name: "unknown size is empty",
+			want: "",
+		},
+		{
+			name:      "sizes below a kibibyte are shown in bytes",
+			sizeBytes: size(512),
+			want:      "512 B",
+		},
+		{
+			name:      "larger sizes use the largest fitting unit",
+			sizeBytes: size(1536 * 1024),
+			want:      "1.5 MiB",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatSize(tt.sizeBytes); got != tt.want {
+				t.Errorf("formatSize() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
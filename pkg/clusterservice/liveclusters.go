@@ -0,0 +1,189 @@
+package clusterservice
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//LiveClusterSource Provides the ids of the clusters that still exist
+type LiveClusterSource interface {
+	GetLiveClusterIds(ctx context.Context) ([]string, error)
+}
+
+var _ LiveClusterSource = &FileLiveClusterSource{}
+var _ LiveClusterSource = &HTTPLiveClusterSource{}
+
+//FileLiveClusterSource Read live clusters from a local file
+//The file can contain a json list of ids, an ocm style clusters list or one id per line
+type FileLiveClusterSource struct {
+	Path string
+}
+
+func (f *FileLiveClusterSource) GetLiveClusterIds(ctx context.Context) ([]string, error) {
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read live clusters file %s: %w", f.Path, err)
+	}
+	liveClusters, err := parseLiveClusters(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse live clusters file %s: %w", f.Path, err)
+	}
+	return liveClusters.ids(), nil
+}
+
+//HTTPLiveClusterSource Read live clusters from an http endpoint returning json, e.g. the ocm clusters api
+//Ocm style responses are paged through until every cluster has been read
+type HTTPLiveClusterSource struct {
+	URL string
+	//Token Sent as a bearer token when set
+	Token  string
+	Client *http.Client
+}
+
+func (h *HTTPLiveClusterSource) GetLiveClusterIds(ctx context.Context) ([]string, error) {
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	var ids []string
+	for page := 1; ; page++ {
+		liveClusters, err := h.getPage(ctx, client, page)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, liveClusters.ids()...)
+		//only ocm style responses are paged, a page size of zero would never end
+		if liveClusters.Size == 0 || len(liveClusters.Items) == 0 || page*liveClusters.Size >= liveClusters.Total {
+			return ids, nil
+		}
+	}
+}
+
+//getPage Request a single page of live clusters, the page is only added to the url after the first request
+func (h *HTTPLiveClusterSource) getPage(ctx context.Context, client *http.Client, page int) (*liveClusterList, error) {
+	pageURL, err := url.Parse(h.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid live clusters url %s: %w", h.URL, err)
+	}
+	if page > 1 {
+		query := pageURL.Query()
+		query.Set("page", strconv.Itoa(page))
+		pageURL.RawQuery = query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build live clusters request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if h.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.Token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request live clusters from %s: %w", pageURL.Redacted(), err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d requesting live clusters from %s", resp.StatusCode, pageURL.Redacted())
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read live clusters response: %w", err)
+	}
+	liveClusters, err := parseLiveClusters(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse live clusters response from %s: %w", pageURL.Redacted(), err)
+	}
+	return liveClusters, nil
+}
+
+//NewLiveClusterSource Build a live cluster source from a location, http and https urls are requested and anything else is read as a file
+func NewLiveClusterSource(location string, token string) LiveClusterSource {
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		return &HTTPLiveClusterSource{
+			URL:   location,
+			Token: token,
+		}
+	}
+	return &FileLiveClusterSource{
+		Path: location,
+	}
+}
+
+//liveCluster Cluster as returned by the ocm clusters api, only the fields that can be used as the cluster id tag are kept
+type liveCluster struct {
+	ID      string `json:"id"`
+	InfraID string `json:"infra_id"`
+}
+
+//liveClusterList Page of clusters as returned by the ocm clusters api
+type liveClusterList struct {
+	Page  int           `json:"page"`
+	Size  int           `json:"size"`
+	Total int           `json:"total"`
+	Items []liveCluster `json:"items"`
+}
+
+//ids Every id a cluster in the list could be tagged with
+//Resources are tagged with the infra id of a cluster, the cluster id is included too so no live cluster is mistaken for an orphan
+func (l *liveClusterList) ids() []string {
+	var ids []string
+	for _, item := range l.Items {
+		for _, id := range []string{item.InfraID, item.ID} {
+			if id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+//parseLiveClusters Parse a json list of ids, an ocm style clusters list or a list of ids with one per line
+//Blank lines and lines starting with # are ignored in line based lists
+func parseLiveClusters(data []byte) (*liveClusterList, error) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		liveClusters := &liveClusterList{}
+		if err := json.Unmarshal(trimmed, liveClusters); err != nil {
+			return nil, err
+		}
+		return liveClusters, nil
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		var ids []string
+		if err := json.Unmarshal(trimmed, &ids); err != nil {
+			return nil, err
+		}
+		return newLiveClusterList(ids), nil
+	}
+	var ids []string
+	scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ids = append(ids, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return newLiveClusterList(ids), nil
+}
+
+func newLiveClusterList(ids []string) *liveClusterList {
+	liveClusters := &liveClusterList{}
+	for _, id := range ids {
+		liveClusters.Items = append(liveClusters.Items, liveCluster{ID: id})
+	}
+	return liveClusters
+}
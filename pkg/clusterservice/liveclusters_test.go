@@ -0,0 +1,133 @@
+package clusterservice
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseLiveClusters(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "json list of ids",
+			data: `["first", "second"]`,
+			want: []string{"first", "second"},
+		},
+		{
+			name: "ocm style clusters list includes infra ids and cluster ids",
+			data: `{"kind": "ClusterList", "page": 1, "size": 2, "total": 2, "items": [{"id": "1abc", "infra_id": "first-x7k2"}, {"id": "2def"}]}`,
+			want: []string{"first-x7k2", "1abc", "2def"},
+		},
+		{
+			name: "one id per line ignoring blank lines and comments",
+			data: "# live clusters\nfirst\n\n  second  \n",
+			want: []string{"first", "second"},
+		},
+		{
+			name:    "invalid json",
+			data:    `{"items": [`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLiveClusters([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseLiveClusters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got.ids(), tt.want) {
+				t.Errorf("parseLiveClusters() got = %v, want %v", got.ids(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFileLiveClusterSource_GetLiveClusterIds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clusters.json")
+	if err := os.WriteFile(path, []byte(`["first"]`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := NewLiveClusterSource(path, "").GetLiveClusterIds(context.TODO())
+	if err != nil {
+		t.Fatalf("GetLiveClusterIds() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"first"}) {
+		t.Errorf("GetLiveClusterIds() got = %v, want [first]", got)
+	}
+	if _, err := NewLiveClusterSource(filepath.Join(t.TempDir(), "missing.json"), "").GetLiveClusterIds(context.TODO()); err == nil {
+		t.Error("GetLiveClusterIds() expected an error for a missing file")
+	}
+}
+
+func TestHTTPLiveClusterSource_GetLiveClusterIds(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "every page of an ocm style clusters list is requested with the token",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer fake-token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				switch r.URL.Query().Get("page") {
+				case "":
+					fmt.Fprint(w, `{"page": 1, "size": 1, "total": 2, "items": [{"id": "1abc", "infra_id": "first"}]}`)
+				case "2":
+					fmt.Fprint(w, `{"page": 2, "size": 1, "total": 2, "items": [{"id": "2def", "infra_id": "second"}]}`)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			},
+			want: []string{"first", "1abc", "second", "2def"},
+		},
+		{
+			name: "json list of ids is requested once",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("page") != "" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				fmt.Fprint(w, `["first"]`)
+			},
+			want: []string{"first"},
+		},
+		{
+			name: "error on unexpected status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+			source := NewLiveClusterSource(server.URL+"/api/clusters_mgmt/v1/clusters", "fake-token")
+			got, err := source.GetLiveClusterIds(context.TODO())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetLiveClusterIds() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetLiveClusterIds() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
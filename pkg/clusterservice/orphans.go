@@ -0,0 +1,69 @@
+package clusterservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	clusterserviceerrors "github.com/integr8ly/cluster-service/pkg/errors"
+)
+
+//FindOrphanedClusters Clusters in the list that are not in the list of live clusters
+//An empty list of live clusters would make every cluster an orphan, so it is treated as an error
+func FindOrphanedClusters(clusterList *ClusterList, liveClusterIds []string) (*ClusterList, error) {
+	if len(liveClusterIds) == 0 {
+		return nil, errors.New("no live clusters found, refusing to treat every cluster as orphaned")
+	}
+	liveClusters := map[string]bool{}
+	for _, liveClusterId := range liveClusterIds {
+		liveClusters[liveClusterId] = true
+	}
+	orphans := &ClusterList{
+		Clusters: []*ClusterSummary{},
+	}
+	for _, cluster := range clusterList.Clusters {
+		if !liveClusters[cluster.ClusterID] {
+			orphans.Clusters = append(orphans.Clusters, cluster)
+		}
+	}
+	return orphans, nil
+}
+
+//DeleteOrphanedClusters Delete the resources of every orphaned cluster and combine the reports of each cluster
+//When continuing on error the remaining clusters are still cleaned up after a failure and the failures are returned together
+func DeleteOrphanedClusters(ctx context.Context, client Client, orphans *ClusterList, dryRun bool, continueOnError bool) (*Report, error) {
+	return deleteClusters(ctx, client, orphans, "orphaned cluster", dryRun, continueOnError)
+}
+
+//DeleteClusters Delete the resources of every cluster in the list and combine the reports of each cluster
+//When continuing on error the remaining clusters are still cleaned up after a failure and the failures are returned together
+func DeleteClusters(ctx context.Context, client Client, clusters *ClusterList, dryRun bool, continueOnError bool) (*Report, error) {
+	return deleteClusters(ctx, client, clusters, "cluster", dryRun, continueOnError)
+}
+
+//deleteClusters Delete the resources of every cluster in the list, describing the clusters as the kind in errors
+func deleteClusters(ctx context.Context, client Client, clusters *ClusterList, kind string, dryRun bool, continueOnError bool) (*Report, error) {
+	report := &Report{}
+	var cleanupErrors []error
+	for _, cluster := range clusters.Clusters {
+		if ctx.Err() != nil {
+			cleanupErrors = append(cleanupErrors, fmt.Errorf("cleanup of %ss interrupted: %w", kind, ctx.Err()))
+			break
+		}
+		clusterReport, err := client.DeleteResourcesForCluster(ctx, cluster.ClusterID, map[string]string{}, dryRun)
+		if clusterReport != nil {
+			if clusterReport.Identity != nil {
+				report.Identity = clusterReport.Identity
+			}
+			report.Items = append(report.Items, clusterReport.Items...)
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to cleanup %s %s: %w", kind, cluster.ClusterID, err)
+			if !continueOnError {
+				return report, err
+			}
+			cleanupErrors = append(cleanupErrors, err)
+		}
+	}
+	return report, clusterserviceerrors.Aggregate(cleanupErrors)
+}
@@ -0,0 +1,157 @@
+package clusterservice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+//fakeClient Client using the provided functions, operations without a function are not implemented
+type fakeClient struct {
+	deleteFn       func(clusterId string, dryRun bool) (*Report, error)
+	listFn         func(clusterId string) (*Inventory, error)
+	listClustersFn func() (*ClusterList, error)
+	applyPlanFn    func(plan *Plan) (*Report, error)
+}
+
+func (f *fakeClient) DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) (*Report, error) {
+	return f.deleteFn(clusterId, dryRun)
+}
+
+func (f *fakeClient) ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) (*Inventory, error) {
+	if f.listFn == nil {
+		return nil, errors.New("not implemented")
+	}
+	return f.listFn(clusterId)
+}
+
+func (f *fakeClient) ListClusters(ctx context.Context) (*ClusterList, error) {
+	if f.listClustersFn == nil {
+		return nil, errors.New("not implemented")
+	}
+	return f.listClustersFn()
+}
+
+func (f *fakeClient) ApplyPlan(ctx context.Context, plan *Plan) (*Report, error) {
+	if f.applyPlanFn == nil {
+		return nil, errors.New("not implemented")
+	}
+	return f.applyPlanFn(plan)
+}
+
+func fakeClusterList(clusterIds ...string) *ClusterList {
+	clusterList := &ClusterList{
+		Clusters: []*ClusterSummary{},
+	}
+	for _, clusterId := range clusterIds {
+		clusterList.Clusters = append(clusterList.Clusters, &ClusterSummary{ClusterID: clusterId})
+	}
+	return clusterList
+}
+
+func TestFindOrphanedClusters(t *testing.T) {
+	tests := []struct {
+		name           string
+		clusterList    *ClusterList
+		liveClusterIds []string
+		want           *ClusterList
+		wantErr        string
+	}{
+		{
+			name:           "clusters that are not live are orphans",
+			clusterList:    fakeClusterList("live", "orphan"),
+			liveClusterIds: []string{"live", "other"},
+			want:           fakeClusterList("orphan"),
+		},
+		{
+			name:           "no orphans when every cluster is live",
+			clusterList:    fakeClusterList("live"),
+			liveClusterIds: []string{"live"},
+			want:           fakeClusterList(),
+		},
+		{
+			name:        "error when there are no live clusters",
+			clusterList: fakeClusterList("live"),
+			wantErr:     "no live clusters found, refusing to treat every cluster as orphaned",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindOrphanedClusters(tt.clusterList, tt.liveClusterIds)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("FindOrphanedClusters() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindOrphanedClusters() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteOrphanedClusters(t *testing.T) {
+	deleteFn := func(clusterId string, dryRun bool) (*Report, error) {
+		if clusterId == "failing" {
+			return nil, errors.New("delete error")
+		}
+		status := ActionStatusInProgress
+		if dryRun {
+			status = ActionStatusDryRun
+		}
+		return &Report{Items: []*ReportItem{{ID: clusterId, ActionStatus: status}}}, nil
+	}
+	tests := []struct {
+		name            string
+		orphans         *ClusterList
+		dryRun          bool
+		continueOnError bool
+		want            *Report
+		wantErr         string
+	}{
+		{
+			name:    "reports of every orphan are combined",
+			orphans: fakeClusterList("first", "second"),
+			dryRun:  true,
+			want: &Report{Items: []*ReportItem{
+				{ID: "first", ActionStatus: ActionStatusDryRun},
+				{ID: "second", ActionStatus: ActionStatusDryRun},
+			}},
+		},
+		{
+			name:    "cleanup stops at the first failure",
+			orphans: fakeClusterList("first", "failing", "second"),
+			want: &Report{Items: []*ReportItem{
+				{ID: "first", ActionStatus: ActionStatusInProgress},
+			}},
+			wantErr: "failed to cleanup orphaned cluster failing: delete error",
+		},
+		{
+			name:            "remaining orphans are cleaned up when continuing on error",
+			orphans:         fakeClusterList("first", "failing", "second"),
+			continueOnError: true,
+			want: &Report{Items: []*ReportItem{
+				{ID: "first", ActionStatus: ActionStatusInProgress},
+				{ID: "second", ActionStatus: ActionStatusInProgress},
+			}},
+			wantErr: "failed to cleanup orphaned cluster failing: delete error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DeleteOrphanedClusters(context.TODO(), &fakeClient{deleteFn: deleteFn}, tt.orphans, tt.dryRun, tt.continueOnError)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("DeleteOrphanedClusters() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("DeleteOrphanedClusters() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DeleteOrphanedClusters() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package clusterservice
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	//PlanVersion Version of the plan format, plans of other versions are refused
+	PlanVersion = 1
+)
+
+//deletionStates States caused by deleting a resource, a planned resource in one of these states is still applied so an interrupted apply can resume
+var deletionStates = map[string]bool{
+	"deleting": true,
+	"deleted":  true,
+}
+
+//Plan Resources of a cluster reviewed for deletion, applying the plan deletes only these resources
+type Plan struct {
+	Version   int                `json:"version"`
+	ClusterID string             `json:"clusterId"`
+	Region    string             `json:"region"`
+	Tags      map[string]string  `json:"tags,omitempty"`
+	CreatedAt time.Time          `json:"createdAt"`
+	Resources []*PlannedResource `json:"resources"`
+}
+
+//PlannedResource Resource recorded in a plan along with a fingerprint of what was reviewed
+type PlannedResource struct {
+	Resource
+	//Fingerprint Hash of the details of the resource which must not change between planning and applying
+	Fingerprint string `json:"fingerprint"`
+}
+
+//NewPlan Build a plan to delete every resource in the inventory of a cluster
+func NewPlan(clusterId string, region string, tags map[string]string, inventory *Inventory, createdAt time.Time) *Plan {
+	plan := &Plan{
+		Version:   PlanVersion,
+		ClusterID: clusterId,
+		Region:    region,
+		Tags:      tags,
+		CreatedAt: createdAt.UTC(),
+		Resources: make([]*PlannedResource, 0, len(inventory.Resources)),
+	}
+	for _, resource := range inventory.Resources {
+		plan.Resources = append(plan.Resources, &PlannedResource{
+			Resource:    *resource,
+			Fingerprint: ResourceFingerprint(resource),
+		})
+	}
+	return plan
+}
+
+//ResourceFingerprint Hash the details of a resource which identify what was reviewed, its id, type, creation time and tags
+//A resource recreated with the same id has a different creation time, so it gets a different fingerprint
+//The state is left out, it changes as resources are deleted, Compare checks it against deletionStates instead
+func ResourceFingerprint(resource *Resource) string {
+	//tags are encoded as a map, which json sorts by key, so the encoding is stable
+	data, _ := json.Marshal(struct {
+		ID           string            `json:"id"`
+		ResourceType string            `json:"resourceType"`
+		CreationTime *time.Time        `json:"creationTime"`
+		Tags         map[string]string `json:"tags"`
+	}{
+		ID:           resource.ID,
+		ResourceType: resource.ResourceType,
+		CreationTime: resource.CreationTime,
+		Tags:         resource.Tags,
+	})
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+//Inventory Resources recorded in the plan
+func (p *Plan) Inventory() *Inventory {
+	inventory := &Inventory{
+		Resources: make([]*Resource, 0, len(p.Resources)),
+	}
+	for _, planned := range p.Resources {
+		resource := planned.Resource
+		inventory.Resources = append(inventory.Resources, &resource)
+	}
+	return inventory
+}
+
+//Compare Compare the plan against the current inventory of the cluster
+//Returns the ids of the planned resources which are unchanged and can be deleted, along with report items for the planned resources which are refused
+//Resources changed since planning are skipped, including state changes other than those caused by the deletion itself, resources which no longer exist are complete, resources not in the plan are never targeted
+func (p *Plan) Compare(inventory *Inventory) (map[string]bool, []*ReportItem) {
+	currentResources := map[string]*Resource{}
+	for _, resource := range inventory.Resources {
+		currentResources[resource.ID] = resource
+	}
+	targets := map[string]bool{}
+	var refusedItems []*ReportItem
+	for _, planned := range p.Resources {
+		current, ok := currentResources[planned.ID]
+		if !ok {
+			refusedItems = append(refusedItems, newPlannedReportItem(planned, ActionStatusComplete, "resource no longer exists"))
+			continue
+		}
+		if ResourceFingerprint(current) != planned.Fingerprint {
+			refusedItems = append(refusedItems, newPlannedReportItem(planned, ActionStatusSkipped, "resource changed since the plan was made"))
+			continue
+		}
+		if current.State != planned.State && !deletionStates[current.State] {
+			refusedItems = append(refusedItems, newPlannedReportItem(planned, ActionStatusSkipped, fmt.Sprintf("resource state changed from %s to %s since the plan was made", planned.State, current.State)))
+			continue
+		}
+		targets[planned.ID] = true
+	}
+	return targets, refusedItems
+}
+
+func newPlannedReportItem(planned *PlannedResource, status ActionStatus, reason string) *ReportItem {
+	return &ReportItem{
+		ID:           planned.ID,
+		Name:         planned.Name,
+		ResourceType: planned.ResourceType,
+		Region:       planned.Region,
+		Account:      planned.Account,
+		Manager:      planned.Manager,
+		Tags:         planned.Tags,
+		Action:       ActionDelete,
+		ActionStatus: status,
+		StatusReason: reason,
+	}
+}
+
+//SavePlan Write a plan to a json file
+func SavePlan(path string, plan *Plan) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode plan: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write plan file %s: %w", path, err)
+	}
+	return nil
+}
+
+//LoadPlan Read a plan from a json file, plans of an unsupported version are refused
+func LoadPlan(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan file %s: %w", path, err)
+	}
+	plan := &Plan{}
+	if err := json.Unmarshal(data, plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan file %s: %w", path, err)
+	}
+	if plan.Version != PlanVersion {
+		return nil, fmt.Errorf("unsupported plan version %d, expected %d", plan.Version, PlanVersion)
+	}
+	if plan.ClusterID == "" {
+		return nil, fmt.Errorf("plan file %s has no cluster id", path)
+	}
+	return plan, nil
+}
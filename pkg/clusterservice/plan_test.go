@@ -0,0 +1,123 @@
+package clusterservice
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestResourceFingerprint(t *testing.T) {
+	creationTime := time.Date(2021, 3, 4, 10, 30, 0, 0, time.UTC)
+	base := func() *Resource {
+		return &Resource{
+			ID:           "arn:aws:rds:eu-west-1:123456789012:db:test",
+			Name:         "test",
+			ResourceType: "rds:db",
+			State:        "available",
+			CreationTime: &creationTime,
+			Tags:         map[string]string{"integreatly.org/clusterID": "test", "owner": "team"},
+		}
+	}
+	tests := []struct {
+		name     string
+		modifyFn func(resource *Resource)
+		wantSame bool
+	}{
+		{
+			name: "details which aren't reviewed don't change the fingerprint",
+			modifyFn: func(resource *Resource) {
+				resource.Name = "renamed"
+				resource.Tags = map[string]string{"owner": "team", "integreatly.org/clusterID": "test"}
+			},
+			wantSame: true,
+		},
+		{
+			name: "changed tags change the fingerprint",
+			modifyFn: func(resource *Resource) {
+				resource.Tags["owner"] = "other"
+			},
+		},
+		{
+			name: "changed state doesn't change the fingerprint, it's compared separately",
+			modifyFn: func(resource *Resource) {
+				resource.State = "deleting"
+			},
+			wantSame: true,
+		},
+		{
+			name: "recreated resources have a different fingerprint",
+			modifyFn: func(resource *Resource) {
+				recreated := creationTime.Add(time.Hour)
+				resource.CreationTime = &recreated
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modified := base()
+			tt.modifyFn(modified)
+			if same := ResourceFingerprint(base()) == ResourceFingerprint(modified); same != tt.wantSame {
+				t.Errorf("ResourceFingerprint() same = %v, want %v", same, tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestPlan_Compare(t *testing.T) {
+	plan := NewPlan("test", "eu-west-1", nil, &Inventory{
+		Resources: []*Resource{
+			{ID: "unchanged", ResourceType: "ec2:vpc"},
+			{ID: "changed", ResourceType: "ec2:subnet", Tags: map[string]string{"owner": "team"}},
+			{ID: "deleted", ResourceType: "s3:bucket"},
+			{ID: "deleting", ResourceType: "rds:db", State: "available"},
+			{ID: "stopped", ResourceType: "rds:db", State: "available"},
+		},
+	}, time.Now())
+	gotTargets, gotRefused := plan.Compare(&Inventory{
+		Resources: []*Resource{
+			{ID: "unchanged", ResourceType: "ec2:vpc"},
+			{ID: "changed", ResourceType: "ec2:subnet", Tags: map[string]string{"owner": "other"}},
+			{ID: "unplanned", ResourceType: "ec2:vpc"},
+			{ID: "deleting", ResourceType: "rds:db", State: "deleting"},
+			{ID: "stopped", ResourceType: "rds:db", State: "stopped"},
+		},
+	})
+	wantTargets := map[string]bool{"unchanged": true, "deleting": true}
+	if !reflect.DeepEqual(gotTargets, wantTargets) {
+		t.Errorf("Compare() targets = %v, want %v", gotTargets, wantTargets)
+	}
+	wantRefused := []*ReportItem{
+		{ID: "changed", ResourceType: "ec2:subnet", Tags: map[string]string{"owner": "team"}, Action: ActionDelete, ActionStatus: ActionStatusSkipped, StatusReason: "resource changed since the plan was made"},
+		{ID: "deleted", ResourceType: "s3:bucket", Action: ActionDelete, ActionStatus: ActionStatusComplete, StatusReason: "resource no longer exists"},
+		{ID: "stopped", ResourceType: "rds:db", Action: ActionDelete, ActionStatus: ActionStatusSkipped, StatusReason: "resource state changed from available to stopped since the plan was made"},
+	}
+	if !reflect.DeepEqual(gotRefused, wantRefused) {
+		t.Errorf("Compare() refused = %v, want %v", gotRefused, wantRefused)
+	}
+}
+
+func TestSavePlan_LoadPlan(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "plan.json")
+	want := NewPlan("test", "eu-west-1", map[string]string{"owner": "team"}, fakeInventory(), time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC))
+	if err := SavePlan(path, want); err != nil {
+		t.Fatalf("SavePlan() unexpected error = %v", err)
+	}
+	got, err := LoadPlan(path)
+	if err != nil {
+		t.Fatalf("LoadPlan() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadPlan() got = %+v, want %+v", got, want)
+	}
+
+	unsupportedPath := filepath.Join(dir, "unsupported.json")
+	if err := os.WriteFile(unsupportedPath, []byte(`{"version": 2, "clusterId": "test"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPlan(unsupportedPath); err == nil || err.Error() != "unsupported plan version 2, expected 1" {
+		t.Errorf("LoadPlan() error = %v, want unsupported plan version", err)
+	}
+}
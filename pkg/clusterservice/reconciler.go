@@ -0,0 +1,210 @@
+package clusterservice
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	//DefaultReconcileInterval Time the default reconciler waits between cleanups
+	DefaultReconcileInterval = 30 * time.Second
+	//DefaultReconcileMaxInterval Longest time the default reconciler waits between cleanups as it backs off
+	DefaultReconcileMaxInterval = 5 * time.Minute
+	//DefaultReconcileBackoffFactor Factor the default reconciler multiplies the interval by after each cleanup without progress
+	DefaultReconcileBackoffFactor = 2.0
+	//DefaultReconcileJitter Fraction of the interval the default reconciler randomly adds to it, so many reconcilers don't hit the api at the same time
+	DefaultReconcileJitter = 0.1
+)
+
+//CompletionCriteria Decide whether a reconcile is done based on the merged report of every cleanup so far
+type CompletionCriteria func(report *Report) bool
+
+//AllItemsComplete Completion criteria met once every item in the report has been deleted
+func AllItemsComplete(report *Report) bool {
+	return report.AllItemsComplete()
+}
+
+//Reconciler Repeatedly clean up the resources of a cluster until the completion criteria are met
+//The report of each cleanup is merged forward into the reports before it, so items missing from a later cleanup are marked complete
+type Reconciler struct {
+	Client    Client
+	ClusterID string
+	Tags      map[string]string
+	DryRun    bool
+	//Interval Time to wait between cleanups, and after a cleanup which completed items
+	Interval time.Duration
+	//MaxInterval Longest time to wait between cleanups as the interval backs off, backoff is disabled when not above the interval
+	MaxInterval time.Duration
+	//BackoffFactor Factor the interval is multiplied by after each cleanup which completed no items
+	BackoffFactor float64
+	//Jitter Fraction of the interval randomly added to it
+	Jitter float64
+	//Timeout Time to keep cleaning up before giving up, zero relies on the context alone
+	Timeout time.Duration
+	//IsComplete Completion criteria, every item being complete when not set
+	IsComplete CompletionCriteria
+	//OnReport Called with the merged report after each cleanup, e.g. to show progress
+	OnReport func(report *Report)
+
+	mu      sync.Mutex
+	history []*Report
+}
+
+//NewReconciler Build a reconciler using the default interval and completion criteria
+func NewReconciler(client Client, clusterId string, dryRun bool) *Reconciler {
+	return &Reconciler{
+		Client:        client,
+		ClusterID:     clusterId,
+		Tags:          map[string]string{},
+		DryRun:        dryRun,
+		Interval:      DefaultReconcileInterval,
+		MaxInterval:   DefaultReconcileMaxInterval,
+		BackoffFactor: DefaultReconcileBackoffFactor,
+		Jitter:        DefaultReconcileJitter,
+		IsComplete:    AllItemsComplete,
+	}
+}
+
+//Reconcile Clean up the cluster until the completion criteria are met, returning the merged report
+//The wait between cleanups backs off while no items complete, and is pushed back when every item in progress hints at a later check
+//Failures recorded in a report are retried by the next cleanup, a cleanup that fails without a report stops the reconcile
+//The merged report is returned along with any error, so the work done so far can be reported
+func (r *Reconciler) Reconcile(ctx context.Context) (*Report, error) {
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+	isComplete := r.IsComplete
+	if isComplete == nil {
+		isComplete = AllItemsComplete
+	}
+	backoff := r.newBackoff()
+	var mergedReport *Report
+	for {
+		report, err := r.Client.DeleteResourcesForCluster(ctx, r.ClusterID, r.Tags, r.DryRun)
+		if report == nil {
+			if err == nil {
+				err = fmt.Errorf("cleanup returned no report")
+			}
+			return mergedReport, fmt.Errorf("failed to reconcile cluster %s: %w", r.ClusterID, err)
+		}
+		r.mu.Lock()
+		r.history = append(r.history, report)
+		r.mu.Unlock()
+		completeBefore := 0
+		if mergedReport == nil {
+			mergedReport = copyReport(report)
+		} else {
+			completeBefore = countCompleteItems(mergedReport)
+			mergedReport.MergeForward(copyReport(report))
+		}
+		if r.OnReport != nil {
+			r.OnReport(mergedReport)
+		}
+		if isComplete(mergedReport) {
+			return mergedReport, nil
+		}
+		//start backing off again once items are completing
+		if countCompleteItems(mergedReport) > completeBefore {
+			backoff = r.newBackoff()
+		}
+		delay := r.nextCheckDelay(&backoff, mergedReport)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return mergedReport, fmt.Errorf("failed to reconcile cluster %s: %w", r.ClusterID, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+//nextCheckDelay Time to wait before the next cleanup, the next step of the backoff unless every item in progress hints at a later check
+//Hints are limited to the max interval, so a manager can never stall the reconciler for longer than configured
+func (r *Reconciler) nextCheckDelay(backoff *wait.Backoff, report *Report) time.Duration {
+	delay := backoff.Step()
+	var earliestHint time.Duration
+	for _, item := range report.Items {
+		if item.ActionStatus != ActionStatusInProgress {
+			continue
+		}
+		//an item without a hint could complete at any time
+		if item.NextCheckAfter <= 0 {
+			return delay
+		}
+		if earliestHint == 0 || item.NextCheckAfter < earliestHint {
+			earliestHint = item.NextCheckAfter
+		}
+	}
+	if earliestHint > r.maxInterval() {
+		earliestHint = r.maxInterval()
+	}
+	if earliestHint > delay {
+		return earliestHint
+	}
+	return delay
+}
+
+//newBackoff Backoff starting at the interval and growing up to the max interval
+func (r *Reconciler) newBackoff() wait.Backoff {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = DefaultReconcileInterval
+	}
+	backoff := wait.Backoff{
+		Duration: interval,
+		Jitter:   r.Jitter,
+	}
+	if r.maxInterval() > interval && r.BackoffFactor > 1 {
+		backoff.Factor = r.BackoffFactor
+		backoff.Cap = r.maxInterval()
+		backoff.Steps = math.MaxInt32
+	}
+	return backoff
+}
+
+//maxInterval Longest time to wait between cleanups, never shorter than the interval
+func (r *Reconciler) maxInterval() time.Duration {
+	if r.MaxInterval < r.Interval {
+		return r.Interval
+	}
+	return r.MaxInterval
+}
+
+//History Reports of every cleanup run by the reconciler, oldest first
+func (r *Reconciler) History() []*Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	history := make([]*Report, len(r.history))
+	copy(history, r.history)
+	return history
+}
+
+func countCompleteItems(report *Report) int {
+	completeItems := 0
+	for _, item := range report.Items {
+		if item.ActionStatus == ActionStatusComplete {
+			completeItems++
+		}
+	}
+	return completeItems
+}
+
+//copyReport Copy the items of a report, so merging into the copy leaves the original untouched
+func copyReport(report *Report) *Report {
+	reportCopy := &Report{
+		Identity: report.Identity,
+		Items:    make([]*ReportItem, 0, len(report.Items)),
+	}
+	for _, item := range report.Items {
+		itemCopy := *item
+		reportCopy.Items = append(reportCopy.Items, &itemCopy)
+	}
+	return reportCopy
+}
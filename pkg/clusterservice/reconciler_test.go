@@ -0,0 +1,217 @@
+package clusterservice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+//fakeReports Delete function returning the provided reports in order, repeating the last one
+func fakeReports(reports ...*Report) func(clusterId string, dryRun bool) (*Report, error) {
+	calls := 0
+	return func(clusterId string, dryRun bool) (*Report, error) {
+		report := reports[len(reports)-1]
+		if calls < len(reports) {
+			report = reports[calls]
+		}
+		calls++
+		if report == nil {
+			return nil, errors.New("delete error")
+		}
+		return copyReport(report), nil
+	}
+}
+
+func fakeInProgressReport(ids ...string) *Report {
+	report := &Report{Items: []*ReportItem{}}
+	for _, id := range ids {
+		report.Items = append(report.Items, &ReportItem{ID: id, Action: ActionDelete, ActionStatus: ActionStatusInProgress})
+	}
+	return report
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	tests := []struct {
+		name        string
+		deleteFn    func(clusterId string, dryRun bool) (*Report, error)
+		isComplete  CompletionCriteria
+		timeout     time.Duration
+		want        *Report
+		wantHistory int
+		wantErr     string
+	}{
+		{
+			name:     "items deleted between cleanups are marked complete",
+			deleteFn: fakeReports(fakeInProgressReport("first", "second"), fakeInProgressReport("second"), fakeInProgressReport()),
+			want: &Report{Items: []*ReportItem{
+				{ID: "first", Action: ActionDelete, ActionStatus: ActionStatusComplete},
+				{ID: "second", Action: ActionDelete, ActionStatus: ActionStatusComplete},
+			}},
+			wantHistory: 3,
+		},
+		{
+			name:     "completion criteria decide when to stop",
+			deleteFn: fakeReports(fakeInProgressReport("first")),
+			isComplete: func(report *Report) bool {
+				return true
+			},
+			want:        fakeInProgressReport("first"),
+			wantHistory: 1,
+		},
+		{
+			name: "retained final snapshots don't keep the cleanup running",
+			deleteFn: fakeReports(&Report{Items: []*ReportItem{
+				{ID: "first", Action: ActionDelete, ActionStatus: ActionStatusComplete},
+				{ID: "snapshot", Action: ActionDelete, ActionStatus: ActionStatusRetained, StatusReason: "retained until 2999-01-01T00:00:00Z"},
+			}}),
+			timeout: time.Second,
+			want: &Report{Items: []*ReportItem{
+				{ID: "first", Action: ActionDelete, ActionStatus: ActionStatusComplete},
+				{ID: "snapshot", Action: ActionDelete, ActionStatus: ActionStatusRetained, StatusReason: "retained until 2999-01-01T00:00:00Z"},
+			}},
+			wantHistory: 1,
+		},
+		{
+			name:        "cleanup failing without a report stops with the report so far",
+			deleteFn:    fakeReports(fakeInProgressReport("first"), nil),
+			want:        fakeInProgressReport("first"),
+			wantHistory: 1,
+			wantErr:     "failed to reconcile cluster test: delete error",
+		},
+		{
+			name:        "items still in progress after the timeout are reported",
+			deleteFn:    fakeReports(fakeInProgressReport("first")),
+			timeout:     20 * time.Millisecond,
+			want:        fakeInProgressReport("first"),
+			wantErr:     "failed to reconcile cluster test",
+			wantHistory: -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reconciler := NewReconciler(&fakeClient{deleteFn: tt.deleteFn}, "test", false)
+			reconciler.Interval = time.Millisecond
+			reconciler.Timeout = tt.timeout
+			if tt.isComplete != nil {
+				reconciler.IsComplete = tt.isComplete
+			}
+			var onReportCalls int
+			reconciler.OnReport = func(report *Report) {
+				onReportCalls++
+			}
+			got, err := reconciler.Reconcile(context.TODO())
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Errorf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("Reconcile() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Reconcile() got = %v, want %v", got, tt.want)
+			}
+			history := reconciler.History()
+			if tt.wantHistory >= 0 && len(history) != tt.wantHistory {
+				t.Errorf("History() got %d reports, want %d", len(history), tt.wantHistory)
+			}
+			if onReportCalls != len(history) {
+				t.Errorf("OnReport() called %d times, want %d", onReportCalls, len(history))
+			}
+		})
+	}
+}
+
+func TestReconciler_HistoryIsNotMerged(t *testing.T) {
+	reconciler := NewReconciler(&fakeClient{deleteFn: fakeReports(fakeInProgressReport("first"), fakeInProgressReport())}, "test", false)
+	reconciler.Interval = time.Millisecond
+	if _, err := reconciler.Reconcile(context.TODO()); err != nil {
+		t.Fatalf("Reconcile() unexpected error = %v", err)
+	}
+	want := []*Report{fakeInProgressReport("first"), fakeInProgressReport()}
+	if got := reconciler.History(); !reflect.DeepEqual(got, want) {
+		t.Errorf("History() got = %v, want %v", got, want)
+	}
+}
+
+func TestReconciler_Backoff(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxInterval time.Duration
+		want        []time.Duration
+	}{
+		{
+			name:        "interval grows up to the max interval",
+			maxInterval: 4 * time.Second,
+			want:        []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second},
+		},
+		{
+			name:        "interval is fixed when the max interval is not above it",
+			maxInterval: time.Second,
+			want:        []time.Duration{time.Second, time.Second, time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reconciler := &Reconciler{
+				Interval:      time.Second,
+				MaxInterval:   tt.maxInterval,
+				BackoffFactor: 2,
+			}
+			backoff := reconciler.newBackoff()
+			var got []time.Duration
+			for range tt.want {
+				got = append(got, reconciler.nextCheckDelay(&backoff, &Report{}))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nextCheckDelay() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconciler_NextCheckDelay(t *testing.T) {
+	hintedItem := func(status ActionStatus, nextCheckAfter time.Duration) *ReportItem {
+		return &ReportItem{ID: "test", ActionStatus: status, NextCheckAfter: nextCheckAfter}
+	}
+	tests := []struct {
+		name  string
+		items []*ReportItem
+		want  time.Duration
+	}{
+		{
+			name:  "earliest hint is used when every item in progress has one",
+			items: []*ReportItem{hintedItem(ActionStatusInProgress, 20*time.Second), hintedItem(ActionStatusInProgress, 10*time.Second), hintedItem(ActionStatusFailed, 0)},
+			want:  10 * time.Second,
+		},
+		{
+			name:  "interval is used when an item in progress has no hint",
+			items: []*ReportItem{hintedItem(ActionStatusInProgress, 20*time.Second), hintedItem(ActionStatusInProgress, 0)},
+			want:  time.Second,
+		},
+		{
+			name:  "hints are limited to the max interval",
+			items: []*ReportItem{hintedItem(ActionStatusInProgress, 10*time.Minute)},
+			want:  time.Minute,
+		},
+		{
+			name:  "hints shorter than the interval are ignored",
+			items: []*ReportItem{hintedItem(ActionStatusInProgress, time.Millisecond)},
+			want:  time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reconciler := &Reconciler{
+				Interval:    time.Second,
+				MaxInterval: time.Minute,
+			}
+			backoff := reconciler.newBackoff()
+			if got := reconciler.nextCheckDelay(&backoff, &Report{Items: tt.items}); got != tt.want {
+				t.Errorf("nextCheckDelay() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
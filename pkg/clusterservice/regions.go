@@ -0,0 +1,149 @@
+package clusterservice
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	clusterserviceerrors "github.com/integr8ly/cluster-service/pkg/errors"
+)
+
+var _ Client = &MultiRegionClient{}
+
+//MultiRegionClient Run every operation against a client per region and merge the results, e.g. to find snapshots copied to another region
+//Regions are handled one after another in alphabetical order, so reports are stable between runs
+type MultiRegionClient struct {
+	//Clients Client for each region, keyed by region
+	Clients map[string]Client
+	//ContinueOnError Keep going with the other regions after a region fails, the failures are returned together
+	ContinueOnError bool
+}
+
+//NewMultiRegionClient Build a client running every operation in each of the regions
+func NewMultiRegionClient(clients map[string]Client, continueOnError bool) *MultiRegionClient {
+	return &MultiRegionClient{
+		Clients:         clients,
+		ContinueOnError: continueOnError,
+	}
+}
+
+//Regions Regions operations run in, in the order they run
+func (m *MultiRegionClient) Regions() []string {
+	regions := make([]string, 0, len(m.Clients))
+	for region := range m.Clients {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+func (m *MultiRegionClient) DeleteResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string, dryRun bool) (*Report, error) {
+	report := &Report{}
+	err := m.forEachRegion(ctx, func(region string, client Client) error {
+		regionReport, err := client.DeleteResourcesForCluster(ctx, clusterId, tags, dryRun)
+		if regionReport != nil {
+			if regionReport.Identity != nil {
+				report.Identity = regionReport.Identity
+			}
+			report.Items = append(report.Items, regionReport.Items...)
+		}
+		return err
+	})
+	return report, err
+}
+
+func (m *MultiRegionClient) ListResourcesForCluster(ctx context.Context, clusterId string, tags map[string]string) (*Inventory, error) {
+	inventory := &Inventory{}
+	err := m.forEachRegion(ctx, func(region string, client Client) error {
+		regionInventory, err := client.ListResourcesForCluster(ctx, clusterId, tags)
+		if regionInventory != nil {
+			inventory.Resources = append(inventory.Resources, regionInventory.Resources...)
+		}
+		return err
+	})
+	if err != nil && !m.ContinueOnError {
+		return nil, err
+	}
+	return inventory, err
+}
+
+//ListClusters List the clusters of every region, clusters with resources in several regions are summarised once
+func (m *MultiRegionClient) ListClusters(ctx context.Context) (*ClusterList, error) {
+	clusters := map[string]*ClusterSummary{}
+	err := m.forEachRegion(ctx, func(region string, client Client) error {
+		regionClusterList, err := client.ListClusters(ctx)
+		if regionClusterList != nil {
+			for _, cluster := range regionClusterList.Clusters {
+				clusters[cluster.ClusterID] = mergeClusterSummaries(clusters[cluster.ClusterID], cluster)
+			}
+		}
+		return err
+	})
+	if err != nil && !m.ContinueOnError {
+		return nil, err
+	}
+	clusterList := &ClusterList{
+		Clusters: make([]*ClusterSummary, 0, len(clusters)),
+	}
+	for _, cluster := range clusters {
+		clusterList.Clusters = append(clusterList.Clusters, cluster)
+	}
+	sort.Slice(clusterList.Clusters, func(i, j int) bool {
+		return clusterList.Clusters[i].ClusterID < clusterList.Clusters[j].ClusterID
+	})
+	return clusterList, err
+}
+
+//ApplyPlan Apply the plan in the region it was made in
+func (m *MultiRegionClient) ApplyPlan(ctx context.Context, plan *Plan) (*Report, error) {
+	client, ok := m.Clients[plan.Region]
+	if !ok {
+		return nil, fmt.Errorf("plan was made in region %s, which is not one of the regions %v", plan.Region, m.Regions())
+	}
+	return client.ApplyPlan(ctx, plan)
+}
+
+//forEachRegion Run a function for every region, stopping at the first failure unless continuing on error
+func (m *MultiRegionClient) forEachRegion(ctx context.Context, regionFn func(region string, client Client) error) error {
+	var regionErrors []error
+	for _, region := range m.Regions() {
+		if ctx.Err() != nil {
+			regionErrors = append(regionErrors, fmt.Errorf("interrupted before region %s: %w", region, ctx.Err()))
+			break
+		}
+		if err := regionFn(region, m.Clients[region]); err != nil {
+			err = fmt.Errorf("failed in region %s: %w", region, err)
+			if !m.ContinueOnError {
+				return err
+			}
+			regionErrors = append(regionErrors, err)
+		}
+	}
+	return clusterserviceerrors.Aggregate(regionErrors)
+}
+
+//mergeClusterSummaries Combine the summaries of a cluster found in two regions, the first may be nil
+func mergeClusterSummaries(summary *ClusterSummary, other *ClusterSummary) *ClusterSummary {
+	merged := &ClusterSummary{
+		ClusterID:          other.ClusterID,
+		ResourceCount:      other.ResourceCount,
+		ResourceCounts:     map[string]int{},
+		OldestCreationTime: other.OldestCreationTime,
+		TotalSizeBytes:     other.TotalSizeBytes,
+	}
+	for resourceType, count := range other.ResourceCounts {
+		merged.ResourceCounts[resourceType] += count
+	}
+	if summary == nil {
+		return merged
+	}
+	merged.ResourceCount += summary.ResourceCount
+	merged.TotalSizeBytes += summary.TotalSizeBytes
+	for resourceType, count := range summary.ResourceCounts {
+		merged.ResourceCounts[resourceType] += count
+	}
+	if summary.OldestCreationTime != nil && (merged.OldestCreationTime == nil || summary.OldestCreationTime.Before(*merged.OldestCreationTime)) {
+		merged.OldestCreationTime = summary.OldestCreationTime
+	}
+	return merged
+}
@@ -0,0 +1,137 @@
+package clusterservice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMultiRegionClient_DeleteResourcesForCluster(t *testing.T) {
+	regionReport := func(region string) *Report {
+		return &Report{
+			Identity: &CallerIdentity{Account: "123456789012"},
+			Items: []*ReportItem{
+				{ID: "arn:" + region, Region: region, ActionStatus: ActionStatusInProgress},
+			},
+		}
+	}
+	regionClient := func(region string, err error) Client {
+		return &fakeClient{deleteFn: func(clusterId string, dryRun bool) (*Report, error) {
+			if err != nil {
+				return nil, err
+			}
+			return regionReport(region), nil
+		}}
+	}
+	tests := []struct {
+		name            string
+		clients         map[string]Client
+		continueOnError bool
+		wantIds         []string
+		wantErr         string
+	}{
+		{
+			name: "reports of every region are merged in region order",
+			clients: map[string]Client{
+				"us-east-1": regionClient("us-east-1", nil),
+				"eu-west-1": regionClient("eu-west-1", nil),
+			},
+			wantIds: []string{"arn:eu-west-1", "arn:us-east-1"},
+		},
+		{
+			name: "regions after a failed region are not cleaned up",
+			clients: map[string]Client{
+				"eu-west-1": regionClient("eu-west-1", errors.New("access denied")),
+				"us-east-1": regionClient("us-east-1", nil),
+			},
+			wantErr: "failed in region eu-west-1: access denied",
+		},
+		{
+			name: "regions after a failed region are cleaned up when continuing on error",
+			clients: map[string]Client{
+				"eu-west-1": regionClient("eu-west-1", errors.New("access denied")),
+				"us-east-1": regionClient("us-east-1", nil),
+			},
+			continueOnError: true,
+			wantIds:         []string{"arn:us-east-1"},
+			wantErr:         "failed in region eu-west-1: access denied",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMultiRegionClient(tt.clients, tt.continueOnError).DeleteResourcesForCluster(context.TODO(), "test", map[string]string{}, false)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("DeleteResourcesForCluster() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("DeleteResourcesForCluster() unexpected error = %v", err)
+			}
+			var gotIds []string
+			for _, item := range got.Items {
+				gotIds = append(gotIds, item.ID)
+			}
+			if !reflect.DeepEqual(gotIds, tt.wantIds) {
+				t.Errorf("DeleteResourcesForCluster() items = %v, want %v", gotIds, tt.wantIds)
+			}
+			if tt.wantIds != nil && got.Identity == nil {
+				t.Errorf("DeleteResourcesForCluster() identity of the regions is missing")
+			}
+		})
+	}
+}
+
+func TestMultiRegionClient_ListClusters(t *testing.T) {
+	older := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)
+	clients := map[string]Client{
+		"eu-west-1": &fakeClient{listClustersFn: func() (*ClusterList, error) {
+			return &ClusterList{Clusters: []*ClusterSummary{
+				{ClusterID: "b", ResourceCount: 1, ResourceCounts: map[string]int{"rds:instance": 1}, OldestCreationTime: &newer, TotalSizeBytes: 10},
+			}}, nil
+		}},
+		"us-east-1": &fakeClient{listClustersFn: func() (*ClusterList, error) {
+			return &ClusterList{Clusters: []*ClusterSummary{
+				{ClusterID: "a", ResourceCount: 1, ResourceCounts: map[string]int{"s3": 1}},
+				{ClusterID: "b", ResourceCount: 2, ResourceCounts: map[string]int{"rds:snapshot": 2}, OldestCreationTime: &older, TotalSizeBytes: 5},
+			}}, nil
+		}},
+	}
+	got, err := NewMultiRegionClient(clients, false).ListClusters(context.TODO())
+	if err != nil {
+		t.Fatalf("ListClusters() unexpected error = %v", err)
+	}
+	want := &ClusterList{Clusters: []*ClusterSummary{
+		{ClusterID: "a", ResourceCount: 1, ResourceCounts: map[string]int{"s3": 1}},
+		{ClusterID: "b", ResourceCount: 3, ResourceCounts: map[string]int{"rds:instance": 1, "rds:snapshot": 2}, OldestCreationTime: &older, TotalSizeBytes: 15},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListClusters() got = %+v, want %+v", got, want)
+	}
+}
+
+func TestMultiRegionClient_ApplyPlan(t *testing.T) {
+	applied := ""
+	regionClient := func(region string) Client {
+		return &fakeClient{applyPlanFn: func(plan *Plan) (*Report, error) {
+			applied = region
+			return &Report{}, nil
+		}}
+	}
+	client := NewMultiRegionClient(map[string]Client{
+		"eu-west-1": regionClient("eu-west-1"),
+		"us-east-1": regionClient("us-east-1"),
+	}, false)
+	if _, err := client.ApplyPlan(context.TODO(), &Plan{Region: "us-east-1"}); err != nil {
+		t.Fatalf("ApplyPlan() unexpected error = %v", err)
+	}
+	if applied != "us-east-1" {
+		t.Errorf("ApplyPlan() applied in region %q, want us-east-1", applied)
+	}
+	wantErr := "plan was made in region ap-south-1, which is not one of the regions [eu-west-1 us-east-1]"
+	if _, err := client.ApplyPlan(context.TODO(), &Plan{Region: "ap-south-1"}); err == nil || err.Error() != wantErr {
+		t.Errorf("ApplyPlan() error = %v, wantErr %v", err, wantErr)
+	}
+}
@@ -0,0 +1,166 @@
+package clusterservice
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/olekukonko/tablewriter"
+	"sigs.k8s.io/yaml"
+)
+
+//OutputFormat Descriptor of a format a document can be rendered in
+type OutputFormat string
+
+const (
+	//OutputFormatTable Human readable table
+	OutputFormatTable OutputFormat = "table"
+	//OutputFormatJSON Indented JSON document
+	OutputFormatJSON OutputFormat = "json"
+	//OutputFormatYAML YAML document
+	OutputFormatYAML OutputFormat = "yaml"
+	//OutputFormatCSV Comma separated values with a header row
+	OutputFormatCSV OutputFormat = "csv"
+)
+
+//Document Information that can be rendered in any output format
+//Structured formats serialize the document itself, tabular formats use its columns and rows
+type Document interface {
+	//Columns Headers used by tabular output formats
+	Columns() []string
+	//Rows Values used by tabular output formats, one value per column
+	Rows() [][]string
+}
+
+//Renderer Write a document to an output in a specific format
+type Renderer interface {
+	Render(out io.Writer, doc Document) error
+}
+
+var renderers = map[OutputFormat]Renderer{
+	OutputFormatTable: &TableRenderer{},
+	OutputFormatJSON:  &JSONRenderer{},
+	OutputFormatYAML:  &YAMLRenderer{},
+	OutputFormatCSV:   &CSVRenderer{},
+}
+
+//RegisterRenderer Make a renderer available for an output format, replacing any existing renderer for the format
+func RegisterRenderer(format OutputFormat, renderer Renderer) {
+	renderers[format] = renderer
+}
+
+//NewRenderer Get the renderer for an output format
+func NewRenderer(format OutputFormat) (Renderer, error) {
+	renderer, ok := renderers[format]
+	if !ok {
+		return nil, fmt.Errorf("output format %s not supported, use one of %v", format, SupportedOutputFormats())
+	}
+	return renderer, nil
+}
+
+//SupportedOutputFormats List all output formats with a registered renderer
+func SupportedOutputFormats() []OutputFormat {
+	var formats []OutputFormat
+	for format := range renderers {
+		formats = append(formats, format)
+	}
+	sort.Slice(formats, func(i, j int) bool {
+		return formats[i] < formats[j]
+	})
+	return formats
+}
+
+var _ Renderer = &TableRenderer{}
+
+//TableRenderer Render a document as a human readable table
+type TableRenderer struct{}
+
+func (t *TableRenderer) Render(out io.Writer, doc Document) error {
+	table := tablewriter.NewWriter(out)
+	table.SetHeader(doc.Columns())
+	table.AppendBulk(doc.Rows())
+	table.Render()
+	return nil
+}
+
+var _ Renderer = &JSONRenderer{}
+
+//JSONRenderer Render a document as indented JSON
+type JSONRenderer struct{}
+
+func (j *JSONRenderer) Render(out io.Writer, doc Document) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode document as json: %w", err)
+	}
+	return nil
+}
+
+var _ Renderer = &YAMLRenderer{}
+
+//YAMLRenderer Render a document as YAML, using the same field names as JSONRenderer
+type YAMLRenderer struct{}
+
+func (y *YAMLRenderer) Render(out io.Writer, doc Document) error {
+	docBytes, err := yaml.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode document as yaml: %w", err)
+	}
+	if _, err := out.Write(docBytes); err != nil {
+		return fmt.Errorf("failed to write yaml document: %w", err)
+	}
+	return nil
+}
+
+var _ Renderer = &CSVRenderer{}
+
+//CSVRenderer Render a document as comma separated values, with the columns as the first row
+type CSVRenderer struct{}
+
+func (c *CSVRenderer) Render(out io.Writer, doc Document) error {
+	writer := csv.NewWriter(out)
+	if err := writer.Write(doc.Columns()); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+	if err := writer.WriteAll(doc.Rows()); err != nil {
+		return fmt.Errorf("failed to write csv rows: %w", err)
+	}
+	return nil
+}
+
+var _ Document = &ReportDocument{}
+
+//ReportDocument Renderable representation of a report, including a summary of the item statuses
+type ReportDocument struct {
+	Identity *CallerIdentity `json:"identity,omitempty"`
+	Summary  *ReportSummary  `json:"summary"`
+	Items    []*ReportItem   `json:"items"`
+}
+
+//NewReportDocument Build a renderable document from a report
+func NewReportDocument(report *Report) *ReportDocument {
+	items := report.Items
+	if items == nil {
+		items = []*ReportItem{}
+	}
+	return &ReportDocument{
+		Identity: report.Identity,
+		Summary:  report.Summary(),
+		Items:    items,
+	}
+}
+
+func (r *ReportDocument) Columns() []string {
+	return []string{"ID", "Name", "Type", "Region", "Account", "Manager", "Action", "Status", "Reason", "Final Snapshot"}
+}
+
+func (r *ReportDocument) Rows() [][]string {
+	rows := make([][]string, 0, len(r.Items))
+	for _, item := range r.Items {
+		rows = append(rows, []string{item.ID, item.Name, item.ResourceType, item.Region, item.Account, item.Manager, string(item.Action), string(item.ActionStatus), item.StatusReason, item.FinalSnapshot})
+	}
+	return rows
+}
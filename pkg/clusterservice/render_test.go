@@ -0,0 +1,203 @@
+package clusterservice
+
+import (
+	"bytes"
+	"testing"
+)
+
+func fakeReport() *Report {
+	return &Report{
+		Items: []*ReportItem{
+			{
+				ID:           "arn:aws:s3:::test",
+				Name:         "test",
+				ResourceType: "s3",
+				Region:       "eu-west-1",
+				Manager:      "aws_s3",
+				Tags:         map[string]string{"integreatly.org/clusterID": "test"},
+				Action:       ActionDelete,
+				ActionStatus: ActionStatusComplete,
+			},
+			{
+				ID:           "arn:aws:rds:eu-west-1:123456789012:db:test",
+				Name:         "test, with comma",
+				ResourceType: "rds:db",
+				Region:       "eu-west-1",
+				Account:      "123456789012",
+				Manager:      "aws_rds",
+				Action:       ActionDelete,
+				ActionStatus: ActionStatusSkipped,
+				StatusReason: "deletion protection enabled",
+			},
+		},
+	}
+}
+
+func TestRenderer_Render(t *testing.T) {
+	tests := []struct {
+		name   string
+		format OutputFormat
+		report *Report
+		want   string
+	}{
+		{
+			name:   "json includes summary and items",
+			format: OutputFormatJSON,
+			report: fakeReport(),
+			want: `{
+  "summary": {
+    "total": 2,
+    "statuses": {
+      "complete": 1,
+      "dry run": 0,
+      "failed": 0,
+      "in progress": 0,
+      "protected": 0,
+      "retained": 0,
+      "skipped": 1
+    }
+  },
+  "items": [
+    {
+      "id": "arn:aws:s3:::test",
+      "name": "test",
+      "resourceType": "s3",
+      "region": "eu-west-1",
+      "account": "",
+      "manager": "aws_s3",
+      "tags": {
+        "integreatly.org/clusterID": "test"
+      },
+      "action": "delete",
+      "actionStatus": "complete"
+    },
+    {
+      "id": "arn:aws:rds:eu-west-1:123456789012:db:test",
+      "name": "test, with comma",
+      "resourceType": "rds:db",
+      "region": "eu-west-1",
+      "account": "123456789012",
+      "manager": "aws_rds",
+      "action": "delete",
+      "actionStatus": "skipped",
+      "statusReason": "deletion protection enabled"
+    }
+  ]
+}
+`,
+		},
+		{
+			name:   "json renders an empty list when there are no items",
+			format: OutputFormatJSON,
+			report: &Report{},
+			want: `{
+  "summary": {
+    "total": 0,
+    "statuses": {
+      "complete": 0,
+      "dry run": 0,
+      "failed": 0,
+      "in progress": 0,
+      "protected": 0,
+      "retained": 0,
+      "skipped": 0
+    }
+  },
+  "items": []
+}
+`,
+		},
+		{
+			name:   "yaml uses the same field names as json",
+			format: OutputFormatYAML,
+			report: fakeReport(),
+			want: `items:
+- account: ""
+  action: delete
+  actionStatus: complete
+  id: arn:aws:s3:::test
+  manager: aws_s3
+  name: test
+  region: eu-west-1
+  resourceType: s3
+  tags:
+    integreatly.org/clusterID: test
+- account: "123456789012"
+  action: delete
+  actionStatus: skipped
+  id: arn:aws:rds:eu-west-1:123456789012:db:test
+  manager: aws_rds
+  name: test, with comma
+  region: eu-west-1
+  resourceType: rds:db
+  statusReason: deletion protection enabled
+summary:
+  statuses:
+    complete: 1
+    dry run: 0
+    failed: 0
+    in progress: 0
+    protected: 0
+    retained: 0
+    skipped: 1
+  total: 2
+`,
+		},
+		{
+			name:   "csv has a header row and quotes values when required",
+			format: OutputFormatCSV,
+			report: fakeReport(),
+			want: `ID,Name,Type,Region,Account,Manager,Action,Status,Reason,Final Snapshot
+arn:aws:s3:::test,test,s3,eu-west-1,,aws_s3,delete,complete,,
+arn:aws:rds:eu-west-1:123456789012:db:test,"test, with comma",rds:db,eu-west-1,123456789012,aws_rds,delete,skipped,deletion protection enabled,
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer, err := NewRenderer(tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out := &bytes.Buffer{}
+			if err := renderer.Render(out, NewReportDocument(tt.report)); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("Render() got = %v, want %v", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRenderer(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  OutputFormat
+		wantErr string
+	}{
+		{
+			name:   "supported format returns a renderer",
+			format: OutputFormatTable,
+		},
+		{
+			name:    "unsupported format returns an error",
+			format:  "xml",
+			wantErr: "output format xml not supported, use one of [csv json table yaml]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRenderer(tt.format)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("NewRenderer() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got == nil {
+				t.Errorf("NewRenderer() got = %v, error = %v", got, err)
+			}
+		})
+	}
+}
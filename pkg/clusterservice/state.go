@@ -0,0 +1,73 @@
+package clusterservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+//State Progress of the cleanup of a cluster, persisted so a later cleanup can resume where it stopped
+type State struct {
+	ClusterID string `json:"clusterId"`
+	//Report Merged report of every cleanup of the cluster so far
+	Report *Report `json:"report,omitempty"`
+	//Pending Resources discovered by a manager but not yet deleted, which can't be discovered again, keyed by manager
+	Pending map[string][]string `json:"pending,omitempty"`
+}
+
+//StateStore Persist the state of a cleanup between runs
+type StateStore interface {
+	//Load Read the persisted state, nil if no state has been persisted yet
+	Load(ctx context.Context) (*State, error)
+	Save(ctx context.Context, state *State) error
+}
+
+var _ StateStore = &FileStateStore{}
+
+//FileStateStore Persist state to a local json file
+type FileStateStore struct {
+	Path string
+}
+
+func (f *FileStateStore) Load(ctx context.Context) (*State, error) {
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file %s: %w", f.Path, err)
+	}
+	state := &State{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", f.Path, err)
+	}
+	return state, nil
+}
+
+//Save Write the state to a temporary file which then replaces the state file, so a crash never leaves a partially written state
+func (f *FileStateStore) Save(ctx context.Context, state *State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(f.Path), filepath.Base(f.Path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary state file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to write temporary state file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary state file: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), f.Path); err != nil {
+		return fmt.Errorf("failed to replace state file %s: %w", f.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package clusterservice
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFileStateStore(t *testing.T) {
+	dir := t.TempDir()
+	store := &FileStateStore{Path: filepath.Join(dir, "state.json")}
+	got, err := store.Load(context.TODO())
+	if err != nil {
+		t.Fatalf("Load() unexpected error = %v", err)
+	}
+	if got != nil {
+		t.Errorf("Load() got = %v, want nil when no state has been saved", got)
+	}
+	want := &State{
+		ClusterID: "test",
+		Report: &Report{
+			Items: []*ReportItem{{ID: "test", Action: ActionDelete, ActionStatus: ActionStatusInProgress}},
+		},
+		Pending: map[string][]string{"aws_elasticache": {"subnet-group"}},
+	}
+	if err := store.Save(context.TODO(), want); err != nil {
+		t.Fatalf("Save() unexpected error = %v", err)
+	}
+	got, err = store.Load(context.TODO())
+	if err != nil {
+		t.Fatalf("Load() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() got = %+v, want %+v", got, want)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Save() left %d files behind, want only the state file", len(entries))
+	}
+}
+
+func TestFileStateStore_LoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&FileStateStore{Path: path}).Load(context.TODO()); err == nil {
+		t.Error("Load() expected an error for an invalid state file")
+	}
+}
@@ -1,5 +1,7 @@
 package clusterservice
 
+import "time"
+
 //Action Descriptor of an action
 type Action string
 
@@ -17,17 +19,45 @@ const (
 	ActionStatusComplete ActionStatus = "complete"
 	//ActionStatusSkipped Action has been skipped, not due to dry-run
 	ActionStatusSkipped ActionStatus = "skipped"
+	//ActionStatusFailed Action could not be performed due to an error
+	ActionStatusFailed ActionStatus = "failed"
+	//ActionStatusProtected Action is not allowed by the safety policy
+	ActionStatusProtected ActionStatus = "protected"
+	//ActionStatusRetained Action is postponed until the retention of a final snapshot elapses
+	ActionStatusRetained ActionStatus = "retained"
 	//ActionStatusEmpty Blank status of action
 	ActionStatusEmpty ActionStatus = ""
 )
 
+//knownActionStatuses Statuses which are always included in a report summary, even when no items have them
+var knownActionStatuses = []ActionStatus{
+	ActionStatusDryRun,
+	ActionStatusInProgress,
+	ActionStatusComplete,
+	ActionStatusSkipped,
+	ActionStatusFailed,
+	ActionStatusProtected,
+	ActionStatusRetained,
+}
+
 //Report Information about what resources are found in the AWS account related to the cluster
 type Report struct {
-	Items []*ReportItem
+	//Identity Credentials the report was produced with, when they were verified
+	Identity *CallerIdentity `json:"identity,omitempty"`
+	Items    []*ReportItem   `json:"items"`
+}
+
+//CallerIdentity Account and principal of the credentials used to act on resources
+type CallerIdentity struct {
+	Account string `json:"account"`
+	ARN     string `json:"arn"`
 }
 
 //MergeForward Merge provided report into this report, assuming the provided report was created after this one
 func (r *Report) MergeForward(mergeTarget *Report) {
+	if mergeTarget.Identity != nil {
+		r.Identity = mergeTarget.Identity
+	}
 	for _, item := range r.Items {
 		item.MergeForward(findReportItem(item.ID, mergeTarget))
 	}
@@ -38,15 +68,48 @@ func (r *Report) MergeForward(mergeTarget *Report) {
 	}
 }
 
+//Summary Count the items in the report for each known action status
+func (r *Report) Summary() *ReportSummary {
+	summary := &ReportSummary{
+		Statuses: map[ActionStatus]int{},
+	}
+	for _, status := range knownActionStatuses {
+		summary.Statuses[status] = 0
+	}
+	for _, item := range r.Items {
+		summary.Total++
+		summary.Statuses[item.ActionStatus]++
+	}
+	return summary
+}
+
+//FailedItems List the items in the report which failed
+func (r *Report) FailedItems() []*ReportItem {
+	var failedItems []*ReportItem
+	for _, item := range r.Items {
+		if item.ActionStatus == ActionStatusFailed {
+			failedItems = append(failedItems, item)
+		}
+	}
+	return failedItems
+}
+
+//AllItemsComplete Whether every item in the report is complete, protected and retained items aren't acted on by this cleanup so they count as complete
 func (r *Report) AllItemsComplete() bool {
 	for _, item := range r.Items {
-		if item.ActionStatus != ActionStatusComplete {
+		if item.ActionStatus != ActionStatusComplete && item.ActionStatus != ActionStatusProtected && item.ActionStatus != ActionStatusRetained {
 			return false
 		}
 	}
 	return true
 }
 
+//ReportSummary Number of report items in each action status
+type ReportSummary struct {
+	Total    int                  `json:"total"`
+	Statuses map[ActionStatus]int `json:"statuses"`
+}
+
 func findReportItem(id string, report *Report) *ReportItem {
 	var foundItem *ReportItem
 	for _, item := range report.Items {
@@ -60,10 +123,24 @@ func findReportItem(id string, report *Report) *ReportItem {
 
 //ReportItem Information about a specific AWS resource
 type ReportItem struct {
-	ID           string
-	Name         string
-	Action       Action
-	ActionStatus ActionStatus
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	//ResourceType Provider specific type of the resource, e.g. ec2:vpc
+	ResourceType string `json:"resourceType"`
+	Region       string `json:"region"`
+	Account      string `json:"account"`
+	//Manager Name of the resource manager that produced the item
+	Manager string `json:"manager"`
+	//Tags Tags observed on the resource when it was discovered
+	Tags         map[string]string `json:"tags,omitempty"`
+	Action       Action            `json:"action"`
+	ActionStatus ActionStatus      `json:"actionStatus"`
+	//StatusReason Free-form explanation of the current action status
+	StatusReason string `json:"statusReason,omitempty"`
+	//FinalSnapshot Identifier of the snapshot taken of the resource before deleting it
+	FinalSnapshot string `json:"finalSnapshot,omitempty"`
+	//NextCheckAfter Hint from the manager of how long the action is expected to take before checking it again, zero for no hint
+	NextCheckAfter time.Duration `json:"-"`
 }
 
 //MergeForward Merge provided item into this item, assuming the provided item was created after this one
@@ -71,9 +148,22 @@ func (r *ReportItem) MergeForward(mergeTarget *ReportItem) {
 	//merge target no longer exists, so it must have been deleted
 	if mergeTarget == nil {
 		r.ActionStatus = ActionStatusComplete
+		r.StatusReason = ""
+		r.NextCheckAfter = 0
 		return
 	}
 	r.Name = mergeTarget.Name
+	r.ResourceType = mergeTarget.ResourceType
+	r.Region = mergeTarget.Region
+	r.Account = mergeTarget.Account
+	r.Manager = mergeTarget.Manager
+	r.Tags = mergeTarget.Tags
 	r.Action = mergeTarget.Action
 	r.ActionStatus = mergeTarget.ActionStatus
+	r.StatusReason = mergeTarget.StatusReason
+	r.NextCheckAfter = mergeTarget.NextCheckAfter
+	//the snapshot is only known while the resource is being deleted, so it's kept once the resource is gone
+	if mergeTarget.FinalSnapshot != "" {
+		r.FinalSnapshot = mergeTarget.FinalSnapshot
+	}
 }
@@ -46,6 +46,123 @@ func TestReport_MergeForward(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "final snapshot is kept when later runs no longer report it",
+			fields: fields{
+				Items: []*ReportItem{
+					{
+						ID:            "test",
+						Name:          "test",
+						Action:        ActionDelete,
+						ActionStatus:  ActionStatusInProgress,
+						FinalSnapshot: "test-final",
+					},
+				},
+			},
+			args: args{
+				mergeTarget: &Report{
+					Items: []*ReportItem{
+						{
+							ID:           "test",
+							Name:         "test",
+							Action:       ActionDelete,
+							ActionStatus: ActionStatusInProgress,
+							StatusReason: "deletion already in progress",
+						},
+					},
+				},
+			},
+			want: &Report{
+				Items: []*ReportItem{
+					{
+						ID:            "test",
+						Name:          "test",
+						Action:        ActionDelete,
+						ActionStatus:  ActionStatusInProgress,
+						StatusReason:  "deletion already in progress",
+						FinalSnapshot: "test-final",
+					},
+				},
+			},
+		},
+		{
+			name: "status reason is cleared when item no longer exists",
+			fields: fields{
+				Items: []*ReportItem{
+					{
+						ID:           "test",
+						Name:         "test",
+						ResourceType: "ec2:vpc",
+						Action:       ActionDelete,
+						ActionStatus: ActionStatusSkipped,
+						StatusReason: "resource has dependencies which have not been deleted",
+					},
+				},
+			},
+			args: args{
+				mergeTarget: &Report{
+					Items: []*ReportItem{},
+				},
+			},
+			want: &Report{
+				Items: []*ReportItem{
+					{
+						ID:           "test",
+						Name:         "test",
+						ResourceType: "ec2:vpc",
+						Action:       ActionDelete,
+						ActionStatus: ActionStatusComplete,
+					},
+				},
+			},
+		},
+		{
+			name: "resource details are taken from the newer item",
+			fields: fields{
+				Items: []*ReportItem{
+					{
+						ID:           "test",
+						Name:         "test",
+						Action:       ActionDelete,
+						ActionStatus: ActionStatusInProgress,
+					},
+				},
+			},
+			args: args{
+				mergeTarget: &Report{
+					Items: []*ReportItem{
+						{
+							ID:           "test",
+							Name:         "test",
+							ResourceType: "ec2:subnet",
+							Region:       "eu-west-1",
+							Account:      "123456789012",
+							Manager:      "aws_ec2_subnet",
+							Tags:         map[string]string{"integreatly.org/clusterID": "test"},
+							Action:       ActionDelete,
+							ActionStatus: ActionStatusSkipped,
+							StatusReason: "resource has dependencies which have not been deleted",
+						},
+					},
+				},
+			},
+			want: &Report{
+				Items: []*ReportItem{
+					{
+						ID:           "test",
+						Name:         "test",
+						ResourceType: "ec2:subnet",
+						Region:       "eu-west-1",
+						Account:      "123456789012",
+						Manager:      "aws_ec2_subnet",
+						Tags:         map[string]string{"integreatly.org/clusterID": "test"},
+						Action:       ActionDelete,
+						ActionStatus: ActionStatusSkipped,
+						StatusReason: "resource has dependencies which have not been deleted",
+					},
+				},
+			},
+		},
 		{
 			name: "overrides and appends work as expected",
 			fields: fields{
@@ -106,3 +223,63 @@ func TestReport_MergeForward(t *testing.T) {
 		})
 	}
 }
+
+func TestReport_FailedItems(t *testing.T) {
+	failedItem := &ReportItem{
+		ID:           "failed",
+		Name:         "failed",
+		Action:       ActionDelete,
+		ActionStatus: ActionStatusFailed,
+		StatusReason: "failed to delete",
+	}
+	report := &Report{
+		Items: []*ReportItem{
+			{
+				ID:           "complete",
+				Name:         "complete",
+				Action:       ActionDelete,
+				ActionStatus: ActionStatusComplete,
+			},
+			failedItem,
+		},
+	}
+	want := []*ReportItem{failedItem}
+	if got := report.FailedItems(); !reflect.DeepEqual(got, want) {
+		t.Errorf("FailedItems() got = %v, want %v", got, want)
+	}
+}
+
+func TestReport_AllItemsComplete(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []ActionStatus
+		want     bool
+	}{
+		{
+			name:     "complete and protected items are done",
+			statuses: []ActionStatus{ActionStatusComplete, ActionStatusProtected},
+			want:     true,
+		},
+		{
+			name:     "retained items are done",
+			statuses: []ActionStatus{ActionStatusComplete, ActionStatusRetained},
+			want:     true,
+		},
+		{
+			name:     "items in progress are not done",
+			statuses: []ActionStatus{ActionStatusComplete, ActionStatusInProgress},
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &Report{}
+			for _, status := range tt.statuses {
+				report.Items = append(report.Items, &ReportItem{ID: string(status), ActionStatus: status})
+			}
+			if got := report.AllItemsComplete(); got != tt.want {
+				t.Errorf("AllItemsComplete() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+
+	clusterserviceerrors "github.com/integr8ly/cluster-service/pkg/errors"
+)
+
+const (
+	//EnvPrefix Prefix of the env vars overriding flags, e.g. CLUSTER_SERVICE_DRY_RUN overrides --dry-run
+	EnvPrefix = "CLUSTER_SERVICE_"
+	//keyManagers Key of the settings of single resource managers, every other key sets the flag of the same name
+	keyManagers = "managers"
+)
+
+//Config Settings read from a yaml or json file, every key except managers sets the command line flag of the same name
+//Flags set on the command line take precedence over env vars, which take precedence over the file
+type Config struct {
+	//Flags Values of command line flags keyed by flag name
+	Flags map[string]interface{}
+	//Managers Settings of single resource managers keyed by manager type name, e.g. rds:instance
+	Managers map[string]*ManagerConfig
+}
+
+//ManagerConfig Settings of a single resource manager
+type ManagerConfig struct {
+	//Concurrency Maximum number of items the manager performs actions on at the same time
+	Concurrency int `json:"concurrency,omitempty"`
+	//Timeout Deadline for a single run of the manager
+	Timeout Duration `json:"timeout,omitempty"`
+}
+
+//Duration Duration written as a string such as 10m
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration must be a string such as 10m: %w", err)
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	d.Duration = duration
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Duration.String())
+}
+
+//Load Read a config file, an empty file is an empty config
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	config := &Config{
+		Flags:    map[string]interface{}{},
+		Managers: map[string]*ManagerConfig{},
+	}
+	for key, value := range values {
+		if key != keyManagers {
+			config.Flags[key] = value
+			continue
+		}
+		//decode the managers strictly, so misspelled settings are reported instead of ignored
+		managersJSON, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read managers of config file %s: %w", path, err)
+		}
+		decoder := json.NewDecoder(bytes.NewReader(managersJSON))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&config.Managers); err != nil {
+			return nil, fmt.Errorf("failed to parse managers of config file %s: %w", path, err)
+		}
+	}
+	return config, nil
+}
+
+//UnknownKeys Keys which don't match a flag in any of the flag sets, sorted
+func (c *Config) UnknownKeys(flagSets ...*pflag.FlagSet) []string {
+	var unknownKeys []string
+	for key := range c.Flags {
+		if !hasFlag(key, flagSets) {
+			unknownKeys = append(unknownKeys, key)
+		}
+	}
+	sort.Strings(unknownKeys)
+	return unknownKeys
+}
+
+//Apply Set every flag in the flag sets which wasn't set on the command line, from its env var or the config
+//A nil config only applies env vars, unknown keys are ignored so they can be reported separately
+func (c *Config) Apply(lookupEnv func(key string) (string, bool), flagSets ...*pflag.FlagSet) error {
+	var applyErrors []error
+	//persistent flags are shared by the flag sets of commands, list flags would be appended to if set twice
+	applied := map[*pflag.Flag]bool{}
+	//flags of the same name in several commands get the same value, so each invalid value is only reported once
+	failed := map[string]bool{}
+	fail := func(flagName string, err error) {
+		if !failed[flagName] {
+			failed[flagName] = true
+			applyErrors = append(applyErrors, err)
+		}
+	}
+	for _, flagSet := range flagSets {
+		flagSet.VisitAll(func(flag *pflag.Flag) {
+			if flag.Changed || applied[flag] {
+				return
+			}
+			applied[flag] = true
+			if value, ok := lookupEnv(EnvVar(flag.Name)); ok {
+				if err := flagSet.Set(flag.Name, value); err != nil {
+					fail(flag.Name, fmt.Errorf("invalid value of env var %s: %w", EnvVar(flag.Name), err))
+				}
+				return
+			}
+			if c == nil {
+				return
+			}
+			value, ok := c.Flags[flag.Name]
+			if !ok {
+				return
+			}
+			flagValue, err := formatFlagValue(value)
+			if err == nil {
+				err = flagSet.Set(flag.Name, flagValue)
+			}
+			if err != nil {
+				fail(flag.Name, fmt.Errorf("invalid value of config key %s: %w", flag.Name, err))
+			}
+		})
+	}
+	return clusterserviceerrors.Aggregate(applyErrors)
+}
+
+//EnvVar Name of the env var overriding a flag
+func EnvVar(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+func hasFlag(name string, flagSets []*pflag.FlagSet) bool {
+	for _, flagSet := range flagSets {
+		if flagSet.Lookup(name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+//formatFlagValue Format a config value the way it's written on the command line, lists as comma separated values and maps as comma separated key=value pairs
+func formatFlagValue(value interface{}) (string, error) {
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue, nil
+	case bool:
+		return strconv.FormatBool(typedValue), nil
+	case float64:
+		return strconv.FormatFloat(typedValue, 'f', -1, 64), nil
+	case []interface{}:
+		values := make([]string, 0, len(typedValue))
+		for _, item := range typedValue {
+			itemValue, err := formatFlagValue(item)
+			if err != nil {
+				return "", err
+			}
+			values = append(values, itemValue)
+		}
+		return strings.Join(values, ","), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(typedValue))
+		for key := range typedValue {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, 0, len(keys))
+		for _, key := range keys {
+			itemValue, err := formatFlagValue(typedValue[key])
+			if err != nil {
+				return "", err
+			}
+			pairs = append(pairs, key+"="+itemValue)
+		}
+		return strings.Join(pairs, ","), nil
+	}
+	return "", fmt.Errorf("unsupported value %v", value)
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+func writeConfig(t *testing.T, data string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    *Config
+		wantErr string
+	}{
+		{
+			name: "flags and managers are read",
+			data: `
+region: us-east-1
+dry-run: false
+managers:
+  rds:instance:
+    concurrency: 2
+    timeout: 10m
+`,
+			want: &Config{
+				Flags: map[string]interface{}{"region": "us-east-1", "dry-run": false},
+				Managers: map[string]*ManagerConfig{
+					"rds:instance": {Concurrency: 2, Timeout: Duration{10 * time.Minute}},
+				},
+			},
+		},
+		{
+			name: "empty file is an empty config",
+			data: "",
+			want: &Config{Flags: map[string]interface{}{}, Managers: map[string]*ManagerConfig{}},
+		},
+		{
+			name:    "error on unknown manager settings",
+			data:    `managers: {"rds:instance": {parallelism: 2}}`,
+			wantErr: `unknown field "parallelism"`,
+		},
+		{
+			name:    "error on invalid manager timeout",
+			data:    `managers: {"rds:instance": {timeout: soon}}`,
+			wantErr: "failed to parse managers of config file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(writeConfig(t, tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_Apply(t *testing.T) {
+	newFlagSet := func() *pflag.FlagSet {
+		flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flagSet.String("region", "eu-west-1", "")
+		flagSet.Bool("dry-run", true, "")
+		flagSet.Int("concurrency", 5, "")
+		flagSet.Duration("timeout", time.Minute, "")
+		flagSet.StringSlice("types", []string{}, "")
+		flagSet.StringToString("tags", map[string]string{}, "")
+		return flagSet
+	}
+	tests := []struct {
+		name    string
+		config  *Config
+		args    []string
+		env     map[string]string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "config sets flags not set on the command line",
+			config: &Config{Flags: map[string]interface{}{
+				"region":      "us-east-1",
+				"dry-run":     false,
+				"concurrency": float64(10),
+				"timeout":     "1h",
+				"types":       []interface{}{"s3", "rds:instance"},
+				"tags":        map[string]interface{}{"owner": "team", "env": "prod"},
+			}},
+			args: []string{"--region=ap-south-1"},
+			want: map[string]string{
+				"region":      "ap-south-1",
+				"dry-run":     "false",
+				"concurrency": "10",
+				"timeout":     "1h0m0s",
+				"types":       "[s3,rds:instance]",
+				"tags":        "[env=prod,owner=team]",
+			},
+		},
+		{
+			name:   "env vars override the config",
+			config: &Config{Flags: map[string]interface{}{"region": "us-east-1"}},
+			env:    map[string]string{"CLUSTER_SERVICE_REGION": "eu-central-1", "CLUSTER_SERVICE_DRY_RUN": "false"},
+			want: map[string]string{
+				"region":  "eu-central-1",
+				"dry-run": "false",
+			},
+		},
+		{
+			name: "env vars are applied without a config",
+			env:  map[string]string{"CLUSTER_SERVICE_CONCURRENCY": "3"},
+			want: map[string]string{"concurrency": "3"},
+		},
+		{
+			name:    "error on invalid values",
+			config:  &Config{Flags: map[string]interface{}{"concurrency": "many"}},
+			wantErr: "invalid value of config key concurrency",
+		},
+		{
+			name:    "error on invalid env vars",
+			env:     map[string]string{"CLUSTER_SERVICE_DRY_RUN": "maybe"},
+			wantErr: "invalid value of env var CLUSTER_SERVICE_DRY_RUN",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flagSet := newFlagSet()
+			if err := flagSet.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			lookupEnv := func(key string) (string, bool) {
+				value, ok := tt.env[key]
+				return value, ok
+			}
+			//the same flag set twice must not append to list flags twice
+			err := tt.config.Apply(lookupEnv, flagSet, flagSet)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply() unexpected error = %v", err)
+			}
+			for name, want := range tt.want {
+				if got := flagSet.Lookup(name).Value.String(); got != want {
+					t.Errorf("Apply() flag %s = %s, want %s", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestConfig_UnknownKeys(t *testing.T) {
+	first := pflag.NewFlagSet("first", pflag.ContinueOnError)
+	first.String("region", "", "")
+	second := pflag.NewFlagSet("second", pflag.ContinueOnError)
+	second.Bool("dry-run", true, "")
+	config := &Config{Flags: map[string]interface{}{"region": "eu-west-1", "dry-run": false, "regoin": "eu-west-1", "dryrun": true}}
+	want := []string{"dryrun", "regoin"}
+	if got := config.UnknownKeys(first, second); !reflect.DeepEqual(got, want) {
+		t.Errorf("UnknownKeys() got = %v, want %v", got, want)
+	}
+}
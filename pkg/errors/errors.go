@@ -2,6 +2,7 @@ package errors
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 )
@@ -12,6 +13,31 @@ func WrapLog(err error, msg string, logger *logrus.Entry) error {
 	return wrap(err, msg)
 }
 
+//Aggregate Combine multiple errors into a single error, nil if no errors are provided
+//A single error is returned as-is so its message and type are preserved
+func Aggregate(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	return &AggregateError{Errors: errs}
+}
+
+//AggregateError Error made up of multiple independent errors
+type AggregateError struct {
+	Errors []error
+}
+
+func (a *AggregateError) Error() string {
+	messages := make([]string, 0, len(a.Errors))
+	for _, err := range a.Errors {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("%d errors occurred: %s", len(a.Errors), strings.Join(messages, "; "))
+}
+
 func wrap(err error, msg string) error {
 	return fmt.Errorf("%s: %w", msg, err)
 }